@@ -20,7 +20,13 @@ var verifyCmd = &cobra.Command{
 		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 		defer cancel()
 
-		return pkg.VerifyObservability(ctx)
+		cfg := pkg.DefaultTestConfig()
+		cfg.PlainOutput = detectEnvironment().Plain
+		cfg.FailOnWarnings, _ = cmd.Flags().GetBool("fail-on-warn")
+		cfg.DumpDir, _ = cmd.Flags().GetString("dump-dir")
+		cfg.RandomizePorts, _ = cmd.Flags().GetBool("randomize-ports")
+		cfg.OTELRequireCollector, _ = cmd.Flags().GetBool("require-otel")
+		return pkg.RunVerification(ctx, cfg, false)
 	},
 }
 
@@ -28,11 +34,24 @@ var publishCmd = &cobra.Command{
 	Use:   "publish",
 	Short: "Publish to GitHub",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		forceTag, _ := cmd.Flags().GetBool("force-tag")
+		draft, _ := cmd.Flags().GetBool("draft")
+		prerelease, _ := cmd.Flags().GetBool("prerelease")
+		assets, _ := cmd.Flags().GetStringSlice("asset")
+		owner, _ := cmd.Flags().GetString("owner")
+		tag, _ := cmd.Flags().GetString("tag")
+		branch, _ := cmd.Flags().GetString("branch")
+		sshKey, _ := cmd.Flags().GetString("ssh-key")
+		token, _ := cmd.Flags().GetString("token")
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+
 		// Delegate publish workflow to pkg.PublishRepo
 		opts := pkg.PublishOptions{
 			RepoPath: ".",
 			Repository: pkg.RepositoryConfig{
-				Owner:       "raja-aiml",
+				Owner:       owner,
 				Name:        "air",
 				Description: "AI Runtime Infrastructure - Build production-ready AI agents and MCP servers in Go with batteries-included observability",
 				Private:     false,
@@ -53,21 +72,20 @@ var publishCmd = &cobra.Command{
 				},
 			},
 			Release: pkg.ReleaseConfig{
-				Tag: "v0.1.0",
-				Message: `Release v0.1.0 - Initial release of air
-
-Features:
-- Full observability stack (OpenTelemetry, Jaeger, Prometheus)
-- PostgreSQL with pgvector for AI embeddings
-- Testing infrastructure with Testcontainers
-- Docker Compose integration
-- CLI tools for infrastructure management
-- Production-ready foundation for AI agents and MCP servers`,
+				Tag:         tag,
 				AuthorName:  "Raja",
 				AuthorEmail: "raja@aiml.com",
+				ForceTag:    forceTag,
+				Draft:       draft,
+				Prerelease:  prerelease,
 			},
 			Remote: "origin",
-			Branch: "main",
+			Branch: branch,
+			Auth: pkg.GitAuthOptions{
+				Token:      token,
+				SSHKeyPath: sshKey,
+			},
+			Assets: assets,
 		}
 
 		if err := pkg.PublishRepo(opts); err != nil {
@@ -96,7 +114,9 @@ var serveCmd = &cobra.Command{
 		}
 
 		fmt.Fprintln(os.Stderr, "Starting air MCP server...")
-		server := pkg.NewMCPServer(registry, pkg.DefaultMCPConfig())
+		cfg := pkg.DefaultMCPConfig()
+		cfg.EnvironmentInfo = mcpEnvironmentInfo
+		server := pkg.NewMCPServer(registry, cfg)
 		return server.ServeStdio(ctx)
 	},
 }
@@ -116,7 +136,9 @@ var nlpCmd = &cobra.Command{
 
 		input := strings.Join(args, " ")
 
-		parser, err := pkg.NewParser(registry, pkg.DefaultParserConfig())
+		parserCfg := pkg.DefaultParserConfig()
+		parserCfg.CachePath = pkg.DefaultCachePath()
+		parser, err := pkg.NewParser(registry, parserCfg)
 		if err != nil {
 			return fmt.Errorf("failed to initialize NLP parser: %w", err)
 		}
@@ -128,20 +150,29 @@ var nlpCmd = &cobra.Command{
 			fmt.Println("Using local embeddings only (no LLM API key found)")
 		}
 
-		result, err := parser.Parse(ctx, input)
+		candidates, err := parser.Candidates(ctx, input, 3)
 		if err != nil {
 			return fmt.Errorf("failed to parse command: %w", err)
 		}
+		result := candidates[0]
 
-		fmt.Printf("Matched command: %s (confidence: %.2f, source: %s)\n\n",
+		fmt.Printf("Matched command: %s (confidence: %.2f, source: %s)\n",
 			result.Command, result.Confidence, result.Source)
+		// A weak local match may have picked the wrong command
+		if result.Source == "embeddings" && result.Confidence < parser.Threshold() && len(candidates) > 1 {
+			fmt.Println("Other candidates:")
+			for _, c := range candidates[1:] {
+				fmt.Printf("  %s (confidence: %.2f)\n", c.Command, c.Confidence)
+			}
+		}
+		fmt.Println()
 
-		execResult, err := registry.Execute(ctx, result.Command, result.Parameters)
+		execResult, err := registry.Execute(pkg.WithLiveOutput(ctx, os.Stdout), result.Command, result.Parameters)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(execResult.Message)
+		printMessage(execResult.Message)
 		return nil
 	},
 }
@@ -159,36 +190,74 @@ var execCmd = &cobra.Command{
 			return err
 		}
 
-		// Convert args to command name and params
-		var cmdName string
-		var cmdArgs []string
-
-		if len(args) >= 2 && !strings.HasPrefix(args[1], "-") {
-			cmdName = args[0] + "." + args[1]
-			cmdArgs = args[2:]
-		} else if strings.Contains(args[0], ".") {
-			cmdName = args[0]
-			cmdArgs = args[1:]
-		} else {
-			return fmt.Errorf("unknown command: %s\nRun 'air help' for usage", args[0])
-		}
-
-		if _, ok := registry.Get(cmdName); !ok {
-			return fmt.Errorf("unknown command: %s\nRun 'air commands' to see available commands", cmdName)
+		command, cmdArgs, err := resolveExecCommand(registry, args)
+		if err != nil {
+			return err
 		}
+		cmdName := command.Name
 
 		params := parseCommandFlags(cmdArgs)
 
-		result, err := registry.Execute(ctx, cmdName, params)
+		result, err := registry.Execute(pkg.WithLiveOutput(ctx, os.Stdout), cmdName, params)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println(result.Message)
+		printMessage(result.Message)
 		return nil
 	},
 }
 
+// resolveExecCommand maps exec arguments to a registered command: "group
+// action" ("db migrate"), a dotted name or an alias ("up"). The remaining
+// arguments are the command's flags.
+func resolveExecCommand(registry *pkg.Registry, args []string) (*pkg.Command, []string, error) {
+	name := args[0]
+	if len(args) >= 2 && !strings.HasPrefix(args[1], "-") {
+		if cmd, ok := registry.Get(args[0] + "." + args[1]); ok {
+			return cmd, args[2:], nil
+		}
+		name += "." + args[1]
+	}
+	if cmd, ok := registry.Get(args[0]); ok {
+		return cmd, args[1:], nil
+	}
+	return nil, nil, fmt.Errorf("unknown command: %s\nRun 'air commands' to see available commands", name)
+}
+
+// mcpEnvironmentInfo reports Docker and database availability for the air.info tool
+func mcpEnvironmentInfo(ctx context.Context) map[string]any {
+	env := map[string]any{
+		"ci": detectEnvironment().CI,
+	}
+	if resolved, err := resolveConfig(); err != nil {
+		env["config_error"] = err.Error()
+	} else {
+		env["database_configured"] = resolved.DatabaseURL.Source != pkg.ConfigSourceDefault
+		env["compose_file"] = resolved.ComposeFile.Value
+	}
+	if err := pkg.PingDocker(ctx); err != nil {
+		env["docker_available"] = false
+		env["docker_error"] = err.Error()
+	} else {
+		env["docker_available"] = true
+	}
+	return env
+}
+
 func init() {
+	publishCmd.Flags().String("owner", "", "Repository owner (default: owner of the remote URL, then the authenticated user)")
+	publishCmd.Flags().String("token", "", "GitHub token for HTTPS pushes (default $GITHUB_TOKEN)")
+	publishCmd.Flags().String("ssh-key", "", "Private key file for SSH remotes (default: ssh-agent)")
+	publishCmd.Flags().String("branch", "", "Branch to push (default: the branch HEAD points to)")
+	publishCmd.Flags().String("tag", "", "Release tag to create and push; its message is the changelog since the previous tag (default: no tag)")
+	publishCmd.Flags().Bool("force-tag", false, "Move an existing release tag to HEAD and force-push it")
+	publishCmd.Flags().StringSlice("asset", nil, "File to attach to the GitHub release (repeatable)")
+	publishCmd.Flags().Bool("draft", false, "Create the GitHub release as a draft")
+	publishCmd.Flags().Bool("prerelease", false, "Mark the GitHub release as a prerelease")
+	verifyCmd.Flags().Bool("fail-on-warn", false, "Exit with status 2 when checks pass with warnings")
+	verifyCmd.Flags().Bool("randomize-ports", false, "Publish containers and the server on free host ports so parallel runs do not collide")
+	verifyCmd.Flags().Bool("require-otel", false, "Fail server startup if the OTEL collector is unreachable instead of warning")
+	verifyCmd.Flags().String("dump-dir", "", "Write the matched trace (trace.json) and Prometheus query results (metrics.json) to this directory")
 	serveCmd.Flags().Bool("mcp", false, "Run as MCP server (stdio transport)")
 }
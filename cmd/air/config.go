@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:          "config",
+	Short:        "Print the effective configuration and where each value came from",
+	Long:         "Print the resolved configuration (flags > env > config file > defaults) with secrets masked",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resolved, err := resolveConfig()
+		if err != nil {
+			return err
+		}
+
+		entries := resolved.Entries()
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		}
+
+		for _, e := range entries {
+			value := e.Value
+			if value == "" {
+				value = "(unset)"
+			}
+			fmt.Printf("%-14s %-60s %s\n", e.Key, value, e.Source)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.Flags().Bool("json", false, "Print the configuration as JSON")
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	pkg "github.com/raja-aiml/air/pkg"
+	"github.com/spf13/cobra"
+)
+
+// doctorStatus is the outcome of a single diagnostic check
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is one line of the air doctor checklist
+type doctorCheck struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Fix    string
+}
+
+// requiredPorts are the host ports published by the compose stack
+var requiredPorts = []struct {
+	Port    string
+	Service string
+}{
+	{"5432", "postgres"},
+	{"16686", "jaeger UI"},
+	{"9090", "prometheus"},
+	{"4317", "otel-collector OTLP gRPC"},
+	{"4318", "otel-collector OTLP HTTP"},
+	{"8888", "otel-collector telemetry"},
+	{"8889", "otel-collector metrics"},
+	{"13133", "otel-collector health"},
+}
+
+var doctorCmd = &cobra.Command{
+	Use:          "doctor",
+	Short:        "Diagnose the local environment (Docker, ports, compose file, services)",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		checks := runDoctor(ctx)
+		printDoctor(checks)
+
+		for _, c := range checks {
+			if c.Status == doctorFail {
+				return fmt.Errorf("air doctor found problems")
+			}
+		}
+		return nil
+	},
+}
+
+// runDoctor executes every diagnostic check in order
+func runDoctor(ctx context.Context) []doctorCheck {
+	var checks []doctorCheck
+
+	// Docker daemon
+	dockerOK := true
+	if err := pkg.PingDocker(ctx); err != nil {
+		dockerOK = false
+		checks = append(checks, doctorCheck{
+			Name:   "Docker daemon",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "Start Docker Desktop (or the docker daemon) and check DOCKER_HOST",
+		})
+	} else {
+		checks = append(checks, doctorCheck{Name: "Docker daemon", Status: doctorOK, Detail: "reachable"})
+	}
+
+	// Configuration
+	resolved, err := resolveConfig()
+	if err != nil {
+		checks = append(checks, doctorCheck{
+			Name:   "Configuration",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "Fix the config file or point --config / AIR_CONFIG at a valid one",
+		})
+		resolved = &pkg.ResolvedConfig{}
+	}
+
+	// Compose file
+	composeFile := resolved.ComposeFile.Value
+	switch {
+	case composeFile == "":
+		checks = append(checks, doctorCheck{
+			Name:   "Compose file",
+			Status: doctorFail,
+			Detail: "not found",
+			Fix:    "Run from the repository root or pass --compose-file (or set AIR_COMPOSE_FILE)",
+		})
+	default:
+		if err := pkg.ValidateComposeFile(ctx, pkg.ComposeConfig{ComposeFilePath: composeFile, ProjectName: resolved.ProjectName.Value}); err != nil {
+			checks = append(checks, doctorCheck{
+				Name:   "Compose file",
+				Status: doctorFail,
+				Detail: fmt.Sprintf("%s: %v", composeFile, err),
+				Fix:    "Fix the YAML error reported above",
+			})
+		} else {
+			checks = append(checks, doctorCheck{Name: "Compose file", Status: doctorOK, Detail: composeFile})
+		}
+	}
+
+	// Ports (in use is expected if the stack is already running)
+	for _, p := range requiredPorts {
+		if pkg.IsPortAvailable(p.Port) {
+			checks = append(checks, doctorCheck{Name: "Port " + p.Port, Status: doctorOK, Detail: "free (" + p.Service + ")"})
+			continue
+		}
+		checks = append(checks, doctorCheck{
+			Name:   "Port " + p.Port,
+			Status: doctorWarn,
+			Detail: "in use (" + p.Service + ")",
+			Fix:    fmt.Sprintf("If the air stack is not running, stop the process holding it (lsof -i :%s)", p.Port),
+		})
+	}
+
+	// Database (only if configured; the built-in default is not checked)
+	if databaseURL := resolved.DatabaseURL; databaseURL.Source != pkg.ConfigSourceDefault && databaseURL.Value != "" {
+		dbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := pkg.VerifyPostgresHealth(dbCtx, databaseURL.Value)
+		cancel()
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Name:   "Database",
+				Status: doctorFail,
+				Detail: err.Error(),
+				Fix:    "Run 'air stack up' or check --database-url / DATABASE_URL / the config file",
+			})
+		} else {
+			checks = append(checks, doctorCheck{Name: "Database", Status: doctorOK, Detail: "reachable"})
+		}
+	}
+
+	// Observability endpoints (warn only: the stack may simply not be started yet)
+	endpoints := []struct{ Name, URL string }{
+		{"Jaeger", "http://localhost:16686/api/services"},
+		{"Prometheus", "http://localhost:9090/-/healthy"},
+		{"OTEL Collector", "http://localhost:13133/"},
+	}
+	client := pkg.NewHTTPClient(3 * time.Second)
+	for _, e := range endpoints {
+		if client.CheckEndpoint(ctx, e.URL) {
+			checks = append(checks, doctorCheck{Name: e.Name, Status: doctorOK, Detail: e.URL})
+			continue
+		}
+		fix := "Run 'air stack up' to start the observability stack"
+		if !dockerOK {
+			fix = "Start Docker first, then run 'air stack up'"
+		}
+		checks = append(checks, doctorCheck{Name: e.Name, Status: doctorWarn, Detail: "not responding at " + e.URL, Fix: fix})
+	}
+
+	return checks
+}
+
+// printDoctor renders the checklist with fixes for anything not OK
+func printDoctor(checks []doctorCheck) {
+	icons := map[doctorStatus]string{doctorOK: "✓", doctorWarn: "!", doctorFail: "✗"}
+	arrow := "→"
+	if detectEnvironment().Plain {
+		icons = map[doctorStatus]string{doctorOK: "ok", doctorWarn: "warn", doctorFail: "FAIL"}
+		arrow = "->"
+	}
+
+	var warnings, failures int
+	fmt.Println("air doctor")
+	fmt.Println()
+	for _, c := range checks {
+		fmt.Printf("  %-4s %-16s %s\n", icons[c.Status], c.Name, c.Detail)
+		if c.Status != doctorOK && c.Fix != "" {
+			fmt.Printf("      %s %s\n", arrow, c.Fix)
+		}
+		switch c.Status {
+		case doctorWarn:
+			warnings++
+		case doctorFail:
+			failures++
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%d checks, %d warnings, %d failures\n", len(checks), warnings, failures)
+}
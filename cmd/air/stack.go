@@ -2,8 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
 	"time"
 
 	pkg "github.com/raja-aiml/air/pkg"
@@ -13,7 +18,14 @@ import (
 var stackCmd = &cobra.Command{
 	Use:   "stack",
 	Short: "Infrastructure stack management",
-	Long:  "Start/stop/monitor infrastructure stack via Docker Compose",
+	Long: `Start/stop/monitor infrastructure stack via Docker Compose
+
+  up [service]       start the stack, or one service and its dependencies
+  stop <service>     stop one service, leaving the rest running
+  down               stop the stack and remove its resources
+  status [--stats]   show service states, optionally with CPU and memory usage
+  logs [-f] <svc>    show (or follow) service logs
+  logs [-f] --all    interleave the logs of every service`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			return cmd.Help()
@@ -22,16 +34,29 @@ var stackCmd = &cobra.Command{
 		action := args[0]
 		switch action {
 		case "up":
+			if len(args) > 1 {
+				return stackUpService(args[1])
+			}
 			return stackUp()
+		case "stop":
+			if len(args) < 2 {
+				return fmt.Errorf("usage: air stack stop <service>")
+			}
+			return stackStopService(args[1])
 		case "down":
 			return stackDown()
 		case "status":
-			return stackStatus()
+			withStats, _ := cmd.Flags().GetBool("stats")
+			return stackStatus(withStats)
 		case "logs":
+			follow, _ := cmd.Flags().GetBool("follow")
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				return stackAllLogs(follow)
+			}
 			if len(args) < 2 {
-				return fmt.Errorf("usage: air stack logs <service>")
+				return fmt.Errorf("usage: air stack logs [-f] <service|--all>")
 			}
-			return stackLogs(args[1])
+			return stackLogs(args[1], follow)
 		default:
 			return fmt.Errorf("unknown stack action: %s", action)
 		}
@@ -42,62 +67,104 @@ func stackUp() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	svc, err := pkg.NewComposeService(pkg.ComposeConfig{
-		ComposeFilePath: "config/docker/docker-compose.yml",
-		ProjectName:     "skillflow",
-		Env:             make(map[string]string),
-	})
+	svc, err := newStackService()
 	if err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
+		return err
 	}
 	defer svc.Close()
 
 	start := time.Now()
 	if err := svc.Start(ctx); err != nil {
-		return err
+		return withComposeHint(err)
 	}
 
+	spinner := pkg.NewStdoutSpinner(detectEnvironment().Plain)
+	spinner.Start("Waiting for services to become healthy...")
 	if err := svc.WaitForHealthy(ctx, 60*time.Second); err != nil {
+		spinner.Stop("")
+		return err
+	}
+
+	spinner.Stop(fmt.Sprintf("Services healthy (%v)", time.Since(start)))
+
+	// A stale local tag still starts fine, so only warn
+	if _, err := svc.VerifyImageDigests(ctx); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	return nil
+}
+
+// stackUpService starts one service and the dependencies it needs
+func stackUpService(name string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	svc, err := newStackService()
+	if err != nil {
 		return err
 	}
+	defer svc.Close()
+
+	if err := svc.StartService(ctx, name); err != nil {
+		return withComposeHint(err)
+	}
 
-	fmt.Printf("Services healthy (%v)\n", time.Since(start))
+	fmt.Printf("Service %s started\n", name)
+	return nil
+}
+
+// stackStopService stops one service without touching the rest of the stack
+func stackStopService(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	svc, err := newStackService()
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	if err := svc.StopService(ctx, name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Service %s stopped\n", name)
 	return nil
 }
 
 func stackDown() error {
+	if !confirm("Stop the stack and remove its containers, networks and volumes?") {
+		fmt.Println("Aborted")
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	svc, err := pkg.NewComposeService(pkg.ComposeConfig{
-		ComposeFilePath: "config/docker/docker-compose.yml",
-		ProjectName:     "skillflow",
-		Env:             make(map[string]string),
-	})
+	svc, err := newStackService()
 	if err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
+		return err
 	}
 	defer svc.Close()
 
 	if err := svc.Stop(ctx); err != nil {
 		return err
 	}
+	if err := svc.VerifyCleanup(ctx); err != nil {
+		return fmt.Errorf("%w\nHint: remove them with docker rm -f, docker network rm and docker volume rm", err)
+	}
 
 	fmt.Println("Services stopped")
 	return nil
 }
 
-func stackStatus() error {
+func stackStatus(withStats bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	svc, err := pkg.NewComposeService(pkg.ComposeConfig{
-		ComposeFilePath: "config/docker/docker-compose.yml",
-		ProjectName:     "skillflow",
-		Env:             make(map[string]string),
-	})
+	svc, err := newStackService()
 	if err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
+		return err
 	}
 	defer svc.Close()
 
@@ -107,30 +174,157 @@ func stackStatus() error {
 	}
 
 	for name, info := range status.Services {
-		fmt.Printf("%s: %s\n", name, info.State)
+		if !withStats || info.State != "running" {
+			fmt.Printf("%s: %s\n", name, info.State)
+			continue
+		}
+		stats, err := svc.Stats(ctx, name)
+		if err != nil {
+			fmt.Printf("%s: %s (stats unavailable: %v)\n", name, info.State, err)
+			continue
+		}
+		fmt.Printf("%s: %s (cpu %.1f%%, mem %s / %s)\n", name, info.State,
+			stats.CPUPercent, formatBytes(stats.MemoryUsage), formatBytes(stats.MemoryLimit))
 	}
 	return nil
 }
 
-func stackLogs(service string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// formatBytes renders n in binary units, as docker stats does
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func stackLogs(service string, follow bool) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
+	if !follow {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, 10*time.Second)
+		defer timeoutCancel()
+	}
 
-	svc, err := pkg.NewComposeService(pkg.ComposeConfig{
-		ComposeFilePath: "config/docker/docker-compose.yml",
-		ProjectName:     "skillflow",
-		Env:             make(map[string]string),
-	})
+	svc, err := newStackService()
 	if err != nil {
-		return fmt.Errorf("failed to initialize: %w", err)
+		return err
 	}
 	defer svc.Close()
 
-	logs, err := svc.Logs(ctx, service)
+	logs, err := svc.LogsStream(ctx, service, follow)
 	if err != nil {
 		return err
 	}
+	defer logs.Close()
 
-	fmt.Println(strings.TrimSpace(logs))
+	if _, err := io.Copy(os.Stdout, logs); err != nil {
+		return fmt.Errorf("stream logs: %w", err)
+	}
 	return nil
 }
+
+// stackAllLogs prints every service's logs in timestamp order, each line
+// prefixed with its service name like docker compose logs
+func stackAllLogs(follow bool) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if !follow {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, 10*time.Second)
+		defer timeoutCancel()
+	}
+
+	svc, err := newStackService()
+	if err != nil {
+		return err
+	}
+	defer svc.Close()
+
+	status, err := svc.Status(ctx)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(status.Services))
+	for name := range status.Services {
+		names = append(names, name)
+	}
+	color := !detectEnvironment().Plain && pkg.IsTerminal(os.Stdout)
+	prefix := logPrefixes(names, color)
+
+	return svc.AllLogs(ctx, follow, func(line pkg.ComposeLogLine) {
+		fmt.Printf("%s%s\n", prefix(line.Service), line.Text)
+	})
+}
+
+// logPrefixColors are the ANSI colors cycled through for service prefixes
+var logPrefixColors = []string{"36", "33", "32", "35", "34", "96", "93", "92", "95", "94"}
+
+// logPrefixes returns the "service | " prefix for each line, padded to the
+// longest service name and, with color, colored per service in name order
+func logPrefixes(services []string, color bool) func(service string) string {
+	sort.Strings(services)
+	width := 0
+	for _, name := range services {
+		width = max(width, len(name))
+	}
+	colors := make(map[string]string, len(services))
+	for i, name := range services {
+		colors[name] = logPrefixColors[i%len(logPrefixColors)]
+	}
+	return func(service string) string {
+		prefix := fmt.Sprintf("%-*s | ", width, service)
+		if code, ok := colors[service]; ok && color {
+			return "\033[" + code + "m" + prefix + "\033[0m"
+		}
+		return prefix
+	}
+}
+
+func init() {
+	stackCmd.Flags().BoolP("follow", "f", false, "Follow log output until interrupted (stack logs)")
+	stackCmd.Flags().Bool("stats", false, "Include CPU and memory usage of running services (stack status)")
+	stackCmd.Flags().Bool("all", false, "Interleave the logs of every service (stack logs)")
+}
+
+// newStackService creates the compose service for the resolved compose file and project
+func newStackService() (*pkg.ComposeService, error) {
+	resolved, err := resolveConfig()
+	if err != nil {
+		return nil, err
+	}
+	if resolved.ComposeFile.Value == "" {
+		return nil, fmt.Errorf("no compose file found: pass --compose-file or set AIR_COMPOSE_FILE")
+	}
+
+	svc, err := pkg.NewComposeService(pkg.ComposeConfig{
+		ComposeFilePath: resolved.ComposeFile.Value,
+		ProjectName:     resolved.ProjectName.Value,
+		Env:             make(map[string]string),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", withComposeHint(err))
+	}
+	return svc, nil
+}
+
+// withComposeHint appends actionable guidance to well-known compose failures.
+func withComposeHint(err error) error {
+	switch {
+	case errors.Is(err, pkg.ErrDockerUnavailable):
+		return fmt.Errorf("%w\nHint: start Docker Desktop (or the docker daemon) and try again", err)
+	case errors.Is(err, pkg.ErrPortInUse):
+		return fmt.Errorf("%w\nHint: stop the process holding the port or change the published port in the compose file", err)
+	case errors.Is(err, pkg.ErrImagePull):
+		return fmt.Errorf("%w\nHint: check your network connection and registry credentials (docker login)", err)
+	case errors.Is(err, pkg.ErrCircularDependency):
+		return fmt.Errorf("%w\nHint: remove the depends_on cycle from the compose file", err)
+	}
+	return err
+}
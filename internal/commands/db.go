@@ -1,31 +1,69 @@
 package commands
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/raja-aiml/air/internal/engine"
 	db "github.com/raja-aiml/air/internal/foundation/database"
+	"github.com/raja-aiml/air/internal/foundation/terminal"
 )
 
+// Querier is the subset of pool operations used by database commands.
+// *pgxpool.Pool satisfies it; tests can inject a fake via NewDBCommandsWithFactory.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Ping(ctx context.Context) error
+}
+
+var _ Querier = (*pgxpool.Pool)(nil)
+
+// QuerierFactory opens a Querier for a database URL.
+// The returned release function is called once the command completes.
+type QuerierFactory func(ctx context.Context, databaseURL string) (Querier, func(), error)
+
 // DBCommands holds dependencies for database commands.
 type DBCommands struct {
 	databaseURL string
+	connect     QuerierFactory
+	migrations  func() ([]db.MigrationInfo, error) // known migrations for db.status
 }
 
-// NewDBCommands creates database command handlers.
+// NewDBCommands creates database command handlers backed by a pgx pool.
 func NewDBCommands(databaseURL string) *DBCommands {
-	return &DBCommands{databaseURL: databaseURL}
+	return NewDBCommandsWithFactory(databaseURL, poolFactory)
+}
+
+// NewDBCommandsWithFactory creates database command handlers using a custom Querier factory.
+func NewDBCommandsWithFactory(databaseURL string, factory QuerierFactory) *DBCommands {
+	return &DBCommands{databaseURL: databaseURL, connect: factory, migrations: db.Migrations}
+}
+
+// poolFactory is the default QuerierFactory, opening a pgx connection pool.
+func poolFactory(ctx context.Context, databaseURL string) (Querier, func(), error) {
+	pool, err := db.NewPool(ctx, databaseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pool, pool.Close, nil
 }
 
 // Register adds all database commands to the registry.
 func (c *DBCommands) Register(r *engine.Registry) {
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "db.migrate",
+		Aliases:     []string{"migrate"},
 		Description: "Run database migrations",
 		Examples: []string{
 			"run migrations",
@@ -33,11 +71,40 @@ func (c *DBCommands) Register(r *engine.Registry) {
 			"apply migrations",
 			"update database schema",
 		},
+		Parameters: []engine.Parameter{
+			{Name: "timeout", Type: "duration", Default: db.DefaultMigrationTimeout, Description: "Fail a migration that runs longer than this (0 disables)"},
+		},
+		Execute: c.migrate,
+	})
+
+	r.MustRegister(&engine.Command{
+		Name:        "db.rollback",
+		Aliases:     []string{"rollback"},
+		Description: "Roll back migrations applied after a version",
+		Examples: []string{
+			"rollback migrations",
+			"undo migration",
+			"revert database schema",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "version", Type: "int", Required: true, Min: 0, Description: "Version to roll back to (0 reverts all migrations)"},
+		},
+		Execute: c.rollback,
+	})
+
+	r.MustRegister(&engine.Command{
+		Name:        "db.status",
+		Description: "Show which migrations are applied and which are pending",
+		Examples: []string{
+			"migration status",
+			"which migrations have run",
+			"show pending migrations",
+		},
 		Parameters: []engine.Parameter{},
-		Execute:    c.migrate,
+		Execute:    c.status,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "db.ping",
 		Description: "Check database connectivity",
 		Examples: []string{
@@ -46,11 +113,13 @@ func (c *DBCommands) Register(r *engine.Registry) {
 			"is database running",
 			"test database",
 		},
-		Parameters: []engine.Parameter{},
-		Execute:    c.ping,
+		Parameters: []engine.Parameter{
+			{Name: "timeout", Type: "duration", Default: defaultDBCommandTimeout, Description: "Give up after this long (0 disables)"},
+		},
+		Execute: c.ping,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "db.query",
 		Description: "Execute a SQL query",
 		Examples: []string{
@@ -60,11 +129,27 @@ func (c *DBCommands) Register(r *engine.Registry) {
 		},
 		Parameters: []engine.Parameter{
 			{Name: "sql", Type: "string", Required: true, Description: "SQL query to execute"},
+			{Name: "format", Type: "string", Default: "table", Enum: []string{"table", "json", "csv"}, Description: "Output format (table, json, csv)"},
+			{Name: "timeout", Type: "duration", Default: defaultDBCommandTimeout, Description: "Cancel the query after this long (0 disables)"},
 		},
 		Execute: c.query,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
+		Name:        "db.run",
+		Description: "Execute a SQL script file in a single transaction",
+		Examples: []string{
+			"run sql file",
+			"execute sql script",
+			"apply fix-up script",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "file", Type: "string", Required: true, Description: "Path to the SQL file"},
+		},
+		Execute: c.run,
+	})
+
+	r.MustRegister(&engine.Command{
 		Name:        "db.shell",
 		Description: "Start interactive SQL shell (pure Go, no psql required)",
 		Examples: []string{
@@ -78,29 +163,164 @@ func (c *DBCommands) Register(r *engine.Registry) {
 	})
 }
 
-// withPool creates a database connection pool and passes it to the given function.
-// It handles pool creation, error handling, and cleanup automatically.
-func (c *DBCommands) withPool(ctx context.Context, fn func(*pgxpool.Pool) (engine.Result, error)) (engine.Result, error) {
-	pool, err := db.NewPool(ctx, c.databaseURL)
+// withQuerier opens a Querier via the configured factory and passes it to the given function.
+// It handles connection, error handling, and cleanup automatically.
+func (c *DBCommands) withQuerier(ctx context.Context, fn func(Querier) (engine.Result, error)) (engine.Result, error) {
+	q, release, err := c.connect(ctx, c.databaseURL)
 	if err != nil {
 		return engine.ErrorResult(err), err
 	}
-	defer pool.Close()
-	return fn(pool)
+	if release != nil {
+		defer release()
+	}
+	return fn(q)
 }
 
 func (c *DBCommands) migrate(ctx context.Context, params map[string]any) (engine.Result, error) {
-	return c.withPool(ctx, func(pool *pgxpool.Pool) (engine.Result, error) {
-		if err := db.RunMigrations(ctx, pool); err != nil {
+	timeout := engine.Params(params).Duration("timeout", db.DefaultMigrationTimeout)
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		// Migrations need transactions, which only a real pool provides
+		pool, ok := q.(*pgxpool.Pool)
+		if !ok {
+			err := fmt.Errorf("migrations require a *pgxpool.Pool, got %T", q)
+			return engine.ErrorResult(err), err
+		}
+		if err := db.Migrate(ctx, pool, db.MigrationConfig{StatementTimeout: timeout}); err != nil {
 			return engine.ErrorResult(err), err
 		}
 		return engine.NewResult("Migrations applied successfully"), nil
 	})
 }
 
+func (c *DBCommands) rollback(ctx context.Context, params map[string]any) (engine.Result, error) {
+	p := engine.Params(params)
+	if !p.Has("version") {
+		err := fmt.Errorf("required parameter %q not provided", "version")
+		return engine.ErrorResult(err), err
+	}
+	version := p.Int("version", -1)
+
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		pool, ok := q.(*pgxpool.Pool)
+		if !ok {
+			err := fmt.Errorf("migrations require a *pgxpool.Pool, got %T", q)
+			return engine.ErrorResult(err), err
+		}
+		if err := db.RollbackMigration(ctx, pool, version); err != nil {
+			return engine.ErrorResult(err), err
+		}
+		return engine.NewResult(fmt.Sprintf("Rolled back to migration %d", version)), nil
+	})
+}
+
+const appliedMigrationsSQL = `SELECT version, applied_at FROM schema_migrations ORDER BY version`
+
+// undefinedTable is the SQLSTATE for a missing relation; schema_migrations
+// does not exist until the first db.migrate
+const undefinedTable = "42P01"
+
+func (c *DBCommands) status(ctx context.Context, params map[string]any) (engine.Result, error) {
+	migrations, err := c.migrations()
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		applied, err := appliedMigrations(ctx, q)
+		if err != nil {
+			return engine.ErrorResult(err), err
+		}
+		statuses := db.CompareMigrations(migrations, applied)
+
+		table := &db.QueryResult{Columns: []string{"version", "name", "status", "applied_at"}}
+		pending := []int{}
+		for _, st := range statuses {
+			state, at := "PENDING", ""
+			if st.Applied() {
+				state, at = "APPLIED", st.AppliedAt.Format(time.RFC3339)
+			} else {
+				pending = append(pending, st.Version)
+			}
+			table.Rows = append(table.Rows, []any{st.Version, st.Name, state, at})
+		}
+
+		var sb strings.Builder
+		if err := db.FormatTable(&sb, table); err != nil {
+			return engine.ErrorResult(err), err
+		}
+		fmt.Fprintf(&sb, "%d applied, %d pending", len(statuses)-len(pending), len(pending))
+
+		return engine.NewResultWithData(sb.String(), map[string]any{
+			"migrations": statuses,
+			"pending":    pending,
+		}), nil
+	})
+}
+
+// appliedMigrations reads schema_migrations as version -> applied_at,
+// treating a missing table as no migrations applied
+func appliedMigrations(ctx context.Context, q Querier) (map[int]time.Time, error) {
+	result, err := executeQuery(ctx, q, appliedMigrationsSQL)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == undefinedTable {
+			return map[int]time.Time{}, nil
+		}
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]time.Time, len(result.Rows))
+	for _, row := range result.Rows {
+		if len(row) != 2 {
+			return nil, fmt.Errorf("read schema_migrations: expected 2 columns, got %d", len(row))
+		}
+		var version int
+		switch v := row[0].(type) {
+		case int32:
+			version = int(v)
+		case int64:
+			version = int(v)
+		case int:
+			version = v
+		default:
+			return nil, fmt.Errorf("read schema_migrations: unexpected version type %T", row[0])
+		}
+		at, _ := row[1].(time.Time)
+		applied[version] = at
+	}
+	return applied, nil
+}
+
+// defaultDBCommandTimeout bounds db.query and db.ping unless overridden by
+// their timeout parameter
+const defaultDBCommandTimeout = 30 * time.Second
+
+// withTimeout applies the command's timeout parameter to ctx; zero or a
+// negative value leaves ctx unbounded
+func withTimeout(ctx context.Context, p engine.Params) (context.Context, time.Duration, context.CancelFunc) {
+	timeout := p.Duration("timeout", defaultDBCommandTimeout)
+	if timeout <= 0 {
+		return ctx, 0, func() {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, timeout, cancel
+}
+
+// timeoutError reports a timed-out command with its limit, passing other errors through
+func timeoutError(err error, timeout time.Duration) error {
+	if timeout > 0 && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
+
 func (c *DBCommands) ping(ctx context.Context, params map[string]any) (engine.Result, error) {
-	return c.withPool(ctx, func(pool *pgxpool.Pool) (engine.Result, error) {
-		if err := db.Ping(ctx, pool); err != nil {
+	ctx, timeout, cancel := withTimeout(ctx, engine.Params(params))
+	defer cancel()
+
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		if err := q.Ping(ctx); err != nil {
+			err = timeoutError(err, timeout)
 			return engine.ErrorResult(err), err
 		}
 		return engine.NewResult("Database connection successful"), nil
@@ -113,73 +333,284 @@ func (c *DBCommands) query(ctx context.Context, params map[string]any) (engine.R
 	if err != nil {
 		return engine.ErrorResult(err), err
 	}
+	format := p.String("format", "table")
+	ctx, timeout, cancel := withTimeout(ctx, p)
+	defer cancel()
+
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		result, err := executeQuery(ctx, q, sql)
+		if err != nil {
+			err = timeoutError(err, timeout)
+			return engine.ErrorResult(err), err
+		}
+
+		var sb strings.Builder
+		if err := db.Format(&sb, result, format); err != nil {
+			return engine.ErrorResult(err), err
+		}
+		return engine.NewResultWithData(strings.TrimRight(sb.String(), "\n"), result), nil
+	})
+}
+
+// TxBeginner is implemented by Queriers that can open a transaction,
+// such as *pgxpool.Pool.
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var _ TxBeginner = (*pgxpool.Pool)(nil)
+
+// StatementResult reports one statement executed by db.run.
+type StatementResult struct {
+	Line         int    `json:"line"`
+	Command      string `json:"command"`
+	RowsAffected int64  `json:"rows_affected"`
+}
+
+func (c *DBCommands) run(ctx context.Context, params map[string]any) (engine.Result, error) {
+	path, err := engine.Params(params).StringRequired("file")
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+	script, err := os.ReadFile(path)
+	if err != nil {
+		err = fmt.Errorf("read sql file: %w", err)
+		return engine.ErrorResult(err), err
+	}
+	statements := db.SplitStatements(string(script))
+	if len(statements) == 0 {
+		err := fmt.Errorf("no SQL statements in %s", path)
+		return engine.ErrorResult(err), err
+	}
 
-	return c.withPool(ctx, func(pool *pgxpool.Pool) (engine.Result, error) {
-		result, err := executeQuery(ctx, pool, sql)
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		beginner, ok := q.(TxBeginner)
+		if !ok {
+			err := fmt.Errorf("db.run requires transaction support, got %T", q)
+			return engine.ErrorResult(err), err
+		}
+
+		results, err := execScript(ctx, beginner, statements)
 		if err != nil {
 			return engine.ErrorResult(err), err
 		}
-		return engine.NewResultWithData("Query executed", result), nil
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Executed %d statements from %s in one transaction", len(results), path)
+		for i, r := range results {
+			fmt.Fprintf(&sb, "\n  %d. %s: %d rows affected (line %d)", i+1, r.Command, r.RowsAffected, r.Line)
+		}
+		return engine.NewResultWithData(sb.String(), results), nil
 	})
 }
 
+// execScript runs statements in one transaction, rolling back on the first failure
+func execScript(ctx context.Context, beginner TxBeginner, statements []db.Statement) ([]StatementResult, error) {
+	tx, err := beginner.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	results := make([]StatementResult, 0, len(statements))
+	for i, stmt := range statements {
+		tag, err := tx.Exec(ctx, stmt.SQL)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d (line %d) failed, transaction rolled back: %w", i+1, stmt.Line, err)
+		}
+		results = append(results, StatementResult{
+			Line:         stmt.Line,
+			Command:      commandName(tag, stmt.SQL),
+			RowsAffected: tag.RowsAffected(),
+		})
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return results, nil
+}
+
+// commandName returns the statement's command ("INSERT", "CREATE TABLE"...),
+// from the server's command tag when it has one
+func commandName(tag pgconn.CommandTag, sql string) string {
+	if name := strings.TrimRight(tag.String(), " 0123456789"); name != "" {
+		return name
+	}
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return ""
+}
+
+// shellHistorySize is the number of db.shell input lines kept in the history file
+const shellHistorySize = 1000
+
 func (c *DBCommands) shell(ctx context.Context, params map[string]any) (engine.Result, error) {
-	return c.withPool(ctx, func(pool *pgxpool.Pool) (engine.Result, error) {
-		fmt.Println("Connected to database. Type SQL queries, or 'exit' to quit.")
+	return c.withQuerier(ctx, func(q Querier) (engine.Result, error) {
+		history, err := terminal.LoadHistory(shellHistoryPath(), shellHistorySize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v (history disabled)\n", err)
+			history, _ = terminal.LoadHistory("", shellHistorySize)
+		}
+
+		fmt.Println("Connected to database. End SQL statements with ';' (or \\g); \\dt lists tables, \\d <table> describes one; 'exit' to quit.")
 		fmt.Println("-----------------------------------------------------------")
 
-		reader := bufio.NewReader(os.Stdin)
-		for {
-			fmt.Print("sql> ")
-			line, err := reader.ReadString('\n')
-			if err != nil {
-				break
+		runShell(ctx, q, terminal.NewLineReader(os.Stdin, os.Stdout, history), os.Stdout)
+		return engine.NewResult("Shell session ended"), nil
+	})
+}
+
+// shellHistoryPath returns $AIR_SQL_HISTORY, or ~/.air_sql_history ("" if no home directory)
+func shellHistoryPath() string {
+	if path := os.Getenv("AIR_SQL_HISTORY"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".air_sql_history")
+}
+
+// runShell executes statements until exit, end of input or ctx cancellation.
+// Input accumulates across lines until a statement ends with ';' or '\g',
+// like psql; exit, quit, \q and the \dt and \d meta-commands take effect
+// immediately.
+func runShell(ctx context.Context, q Querier, lines *terminal.LineReader, out io.Writer) {
+	var statement []string
+	for {
+		prompt := "sql> "
+		if len(statement) > 0 {
+			prompt = "sql-> "
+		}
+
+		line, err := lines.ReadLine(ctx, prompt)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Fprintln(out)
 			}
+			return
+		}
 
-			line = strings.TrimSpace(line)
-			if line == "" {
+		trimmed := strings.TrimSpace(line)
+		switch strings.ToLower(trimmed) {
+		case "exit", "quit", "\\q":
+			return
+		case "":
+			if len(statement) == 0 {
 				continue
 			}
-			if strings.ToLower(line) == "exit" || strings.ToLower(line) == "quit" || strings.ToLower(line) == "\\q" {
-				break
-			}
+		}
 
-			result, err := executeQuery(ctx, pool, line)
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+		// Backslash meta-commands run immediately, like psql, outside a statement
+		if len(statement) == 0 && strings.HasPrefix(trimmed, `\`) && trimmed != `\g` {
+			runMetaCommand(ctx, q, trimmed, out)
+			continue
+		}
+
+		// \g ends a statement without being part of it
+		if sql, ok := strings.CutSuffix(trimmed, `\g`); ok {
+			statement = append(statement, sql)
+		} else {
+			statement = append(statement, line)
+			if !strings.HasSuffix(trimmed, ";") {
 				continue
 			}
+		}
 
-			printQueryResult(result)
+		query := strings.TrimSpace(strings.Join(statement, "\n"))
+		statement = statement[:0]
+		if query == "" || query == ";" {
+			continue
 		}
 
-		return engine.NewResult("Shell session ended"), nil
-	})
+		result, err := executeQuery(ctx, q, query)
+		if err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+			continue
+		}
+
+		if err := db.FormatTable(out, result); err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+		}
+	}
 }
 
-// QueryResult holds the result of a SQL query.
-type QueryResult struct {
-	Columns      []string        `json:"columns"`
-	Rows         [][]interface{} `json:"rows"`
-	RowsAffected int64           `json:"rows_affected"`
+// listTablesSQL lists user tables and views, as psql's \dt does
+const listTablesSQL = `SELECT table_schema AS schema, table_name AS name, table_type AS type
+FROM information_schema.tables
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY table_schema, table_name`
+
+// describeTableSQL lists the columns of a table; an empty schema ($1) means
+// the current schema
+const describeTableSQL = `SELECT column_name AS column, data_type AS type, is_nullable AS nullable, column_default AS "default"
+FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema()) AND table_name = $2
+ORDER BY ordinal_position`
+
+// runMetaCommand handles the psql-style \dt and \d [table] commands
+func runMetaCommand(ctx context.Context, q Querier, input string, out io.Writer) {
+	fields := strings.Fields(strings.TrimSuffix(input, ";"))
+	command, args := fields[0], fields[1:]
+
+	var (
+		result *db.QueryResult
+		err    error
+	)
+	switch {
+	case command == `\dt`, command == `\d` && len(args) == 0:
+		result, err = executeQuery(ctx, q, listTablesSQL)
+	case command == `\d`:
+		schema, table, ok := strings.Cut(args[0], ".")
+		if !ok {
+			schema, table = "", args[0]
+		}
+		result, err = executeQuery(ctx, q, describeTableSQL, schema, table)
+		if err == nil && len(result.Rows) == 0 {
+			fmt.Fprintf(out, "Did not find any relation named %q.\n", args[0])
+			return
+		}
+	default:
+		fmt.Fprintf(out, "Error: unknown command %s (supported: \\dt, \\d [table], \\g, \\q)\n", command)
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintf(out, "Error: %v\n", err)
+		return
+	}
+	if err := db.FormatTable(out, result); err != nil {
+		fmt.Fprintf(out, "Error: %v\n", err)
+	}
 }
 
-func executeQuery(ctx context.Context, pool *pgxpool.Pool, sql string) (*QueryResult, error) {
-	rows, err := pool.Query(ctx, sql)
+func executeQuery(ctx context.Context, q Querier, sql string, args ...any) (*db.QueryResult, error) {
+	rows, err := q.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// Get column names
+	// Get column names and types
 	fields := rows.FieldDescriptions()
+	typeMap := pgtype.NewMap()
+	if conn := rows.Conn(); conn != nil {
+		typeMap = conn.TypeMap()
+	}
 	columns := make([]string, len(fields))
+	columnTypes := make([]string, len(fields))
 	for i, f := range fields {
 		columns[i] = string(f.Name)
+		if typ, ok := typeMap.TypeForOID(f.DataTypeOID); ok {
+			columnTypes[i] = typ.Name
+		}
 	}
 
 	// Collect rows
-	var resultRows [][]interface{}
+	var resultRows [][]any
 	for rows.Next() {
 		values, err := rows.Values()
 		if err != nil {
@@ -192,52 +623,10 @@ func executeQuery(ctx context.Context, pool *pgxpool.Pool, sql string) (*QueryRe
 		return nil, err
 	}
 
-	return &QueryResult{
+	return &db.QueryResult{
 		Columns:      columns,
+		ColumnTypes:  columnTypes,
 		Rows:         resultRows,
 		RowsAffected: int64(len(resultRows)),
 	}, nil
 }
-
-func printQueryResult(result *QueryResult) {
-	if len(result.Columns) == 0 {
-		fmt.Printf("Query OK, %d rows affected\n", result.RowsAffected)
-		return
-	}
-
-	// Calculate column widths
-	widths := make([]int, len(result.Columns))
-	for i, col := range result.Columns {
-		widths[i] = len(col)
-	}
-	for _, row := range result.Rows {
-		for i, val := range row {
-			s := fmt.Sprintf("%v", val)
-			if len(s) > widths[i] {
-				widths[i] = len(s)
-			}
-		}
-	}
-
-	// Print header
-	for i, col := range result.Columns {
-		fmt.Printf("%-*s  ", widths[i], col)
-	}
-	fmt.Println()
-
-	// Print separator
-	for i := range result.Columns {
-		fmt.Print(strings.Repeat("-", widths[i]) + "  ")
-	}
-	fmt.Println()
-
-	// Print rows
-	for _, row := range result.Rows {
-		for i, val := range row {
-			fmt.Printf("%-*v  ", widths[i], val)
-		}
-		fmt.Println()
-	}
-
-	fmt.Printf("(%d rows)\n", len(result.Rows))
-}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	db "github.com/raja-aiml/air/internal/foundation/database"
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+	"github.com/raja-aiml/air/pkg/spantest"
+)
+
+func TestDBStatusAgainstPostgres(t *testing.T) {
+	ctx := context.Background()
+
+	infra, err := containers.StartEmbedded(ctx, containers.DefaultConfig())
+	if err != nil {
+		t.Fatalf("start embedded postgres: %v", err)
+	}
+	defer containers.CleanupInfrastructure(infra)
+
+	pool, err := db.NewPool(ctx, infra.PostgresURL)
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	defer pool.Close()
+
+	// Apply the first of two migrations by hand; the embedded schema needs
+	// pgvector, which embedded Postgres lacks
+	if _, err := pool.Exec(ctx, `
+		CREATE TABLE schema_migrations (version INT PRIMARY KEY, applied_at TIMESTAMPTZ NOT NULL DEFAULT now());
+		CREATE TABLE first (id INT);
+		INSERT INTO schema_migrations (version) VALUES (1);
+	`); err != nil {
+		t.Fatalf("apply migration 1: %v", err)
+	}
+
+	c := NewDBCommands(infra.PostgresURL)
+	c.migrations = func() ([]db.MigrationInfo, error) {
+		return []db.MigrationInfo{{Version: 1, Name: "001_first"}, {Version: 2, Name: "002_second"}}, nil
+	}
+	result, err := c.status(ctx, nil)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !strings.Contains(result.Message, "APPLIED") || !strings.Contains(result.Message, "PENDING") {
+		t.Fatalf("expected APPLIED and PENDING rows, got:\n%s", result.Message)
+	}
+	if pending := result.Data.(map[string]any)["pending"].([]int); len(pending) != 1 || pending[0] != 2 {
+		t.Fatalf("expected pending [2], got %v", pending)
+	}
+}
+
+func TestMigrationTimeoutAndSpans(t *testing.T) {
+	ctx := context.Background()
+	exporter := spantest.Record(t)
+
+	infra, err := containers.StartEmbedded(ctx, containers.DefaultConfig())
+	if err != nil {
+		t.Fatalf("start embedded postgres: %v", err)
+	}
+	defer containers.CleanupInfrastructure(infra)
+
+	pool, err := db.NewPool(ctx, infra.PostgresURL)
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	defer pool.Close()
+
+	fsys := fstest.MapFS{
+		"001_first.sql": {Data: []byte(`CREATE TABLE first (id INT)`)},
+		"002_slow.sql":  {Data: []byte(`SELECT pg_sleep(5)`)},
+	}
+	err = db.Migrate(ctx, pool, db.MigrationConfig{FS: fsys, Dir: ".", StatementTimeout: 200 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "apply migration 2: exceeded 200ms timeout") {
+		t.Fatalf("expected migration 2 to time out, got %v", err)
+	}
+
+	versions := map[int64]string{}
+	for _, span := range spantest.FindSpans(exporter.GetSpans(), "db.migration") {
+		if v, ok := spantest.Attribute(span, "db.migration.version"); ok {
+			versions[v.AsInt64()] = span.Status.Code.String()
+		}
+	}
+	if versions[1] != "Ok" || versions[2] != "Error" {
+		t.Fatalf("expected an Ok span for migration 1 and an Error span for 2, got %v", versions)
+	}
+}
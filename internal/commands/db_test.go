@@ -0,0 +1,364 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/raja-aiml/air/internal/foundation/database"
+	"github.com/raja-aiml/air/internal/foundation/terminal"
+)
+
+// fakeQuerier returns canned rows for every query.
+type fakeQuerier struct {
+	columns []string
+	oids    []uint32
+	rows    [][]any
+	err     error
+	pingErr error
+	queries []string
+	args    [][]any
+	block   bool   // wait for ctx cancellation instead of answering
+	failOn  string // Exec fails for this statement
+	tx      *fakeTx
+}
+
+func (f *fakeQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.queries = append(f.queries, sql)
+	f.args = append(f.args, args)
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &fakeRows{columns: f.columns, oids: f.oids, rows: f.rows, idx: -1}, nil
+}
+
+func (f *fakeQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.queries = append(f.queries, sql)
+	if sql == f.failOn {
+		return pgconn.CommandTag{}, errors.New("syntax error")
+	}
+	return pgconn.NewCommandTag("UPDATE 2"), f.err
+}
+
+func (f *fakeQuerier) Begin(ctx context.Context) (pgx.Tx, error) {
+	f.tx = &fakeTx{q: f}
+	return f.tx, nil
+}
+
+// fakeTx records the transaction outcome; unused pgx.Tx methods panic.
+type fakeTx struct {
+	pgx.Tx
+	q          *fakeQuerier
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return tx.q.Exec(ctx, sql, args...)
+}
+
+func (tx *fakeTx) Commit(ctx context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(ctx context.Context) error {
+	if !tx.committed {
+		tx.rolledBack = true
+	}
+	return nil
+}
+
+func (f *fakeQuerier) Ping(ctx context.Context) error {
+	if f.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return f.pingErr
+}
+
+// fakeRows implements pgx.Rows over in-memory values.
+type fakeRows struct {
+	columns []string
+	oids    []uint32
+	rows    [][]any
+	idx     int
+}
+
+func (r *fakeRows) Close()                        {}
+func (r *fakeRows) Err() error                    { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag { return pgconn.CommandTag{} }
+func (r *fakeRows) Conn() *pgx.Conn               { return nil }
+func (r *fakeRows) RawValues() [][]byte           { return nil }
+func (r *fakeRows) Scan(dest ...any) error        { return errors.New("not implemented") }
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, c := range r.columns {
+		fields[i] = pgconn.FieldDescription{Name: c}
+		if i < len(r.oids) {
+			fields[i].DataTypeOID = r.oids[i]
+		}
+	}
+	return fields
+}
+
+func (r *fakeRows) Next() bool {
+	r.idx++
+	return r.idx < len(r.rows)
+}
+
+func (r *fakeRows) Values() ([]any, error) {
+	return r.rows[r.idx], nil
+}
+
+func newFakeDBCommands(q *fakeQuerier) *DBCommands {
+	return NewDBCommandsWithFactory("postgres://fake", func(ctx context.Context, url string) (Querier, func(), error) {
+		return q, nil, nil
+	})
+}
+
+func TestDBPing(t *testing.T) {
+	q := &fakeQuerier{}
+	result, err := newFakeDBCommands(q).ping(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Message != "Database connection successful" {
+		t.Fatalf("unexpected message: %q", result.Message)
+	}
+
+	q.pingErr = errors.New("connection refused")
+	result, err = newFakeDBCommands(q).ping(context.Background(), nil)
+	if err == nil || result.Success {
+		t.Fatal("expected ping failure")
+	}
+}
+
+func TestDBQuery(t *testing.T) {
+	q := &fakeQuerier{
+		columns: []string{"id", "name"},
+		oids:    []uint32{pgtype.Int4OID, pgtype.TextOID},
+		rows:    [][]any{{1, "alice"}, {2, "bob"}},
+	}
+
+	result, err := newFakeDBCommands(q).query(context.Background(), map[string]any{"sql": "SELECT id, name FROM users"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	qr, ok := result.Data.(*db.QueryResult)
+	if !ok {
+		t.Fatalf("expected *QueryResult data, got %T", result.Data)
+	}
+	if len(qr.Columns) != 2 || len(qr.Rows) != 2 {
+		t.Fatalf("expected 2 columns and 2 rows, got %d and %d", len(qr.Columns), len(qr.Rows))
+	}
+	if len(qr.ColumnTypes) != 2 || qr.ColumnTypes[0] != "int4" || qr.ColumnTypes[1] != "text" {
+		t.Fatalf("expected column types [int4 text], got %v", qr.ColumnTypes)
+	}
+	if !strings.Contains(result.Message, "alice") || !strings.Contains(result.Message, "(2 rows)") {
+		t.Fatalf("expected rendered table in message, got %q", result.Message)
+	}
+	if q.queries[0] != "SELECT id, name FROM users" {
+		t.Fatalf("unexpected query: %q", q.queries[0])
+	}
+}
+
+func TestDBQueryTimeout(t *testing.T) {
+	q := &fakeQuerier{block: true}
+	cmds := newFakeDBCommands(q)
+
+	_, err := cmds.query(context.Background(), map[string]any{"sql": "SELECT pg_sleep(60)", "timeout": "20ms"})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 20ms") {
+		t.Fatalf("expected query timeout error, got %v", err)
+	}
+
+	_, err = cmds.ping(context.Background(), map[string]any{"timeout": 20 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected ping timeout error, got %v", err)
+	}
+}
+
+func TestDBQueryMissingSQL(t *testing.T) {
+	q := &fakeQuerier{}
+	if _, err := newFakeDBCommands(q).query(context.Background(), map[string]any{}); err == nil {
+		t.Fatal("expected error for missing sql parameter")
+	}
+	if len(q.queries) != 0 {
+		t.Fatal("expected no queries to be executed")
+	}
+}
+
+func TestDBMigrateRequiresPool(t *testing.T) {
+	if _, err := newFakeDBCommands(&fakeQuerier{}).migrate(context.Background(), nil); err == nil {
+		t.Fatal("expected error when querier is not a pool")
+	}
+}
+
+func TestRunShell(t *testing.T) {
+	q := &fakeQuerier{columns: []string{"n"}, rows: [][]any{{1}}}
+	input := "SELECT 1;\n\nSELECT 2\\g\nexit\nSELECT 3;\n"
+	var out strings.Builder
+
+	runShell(context.Background(), q, terminal.NewLineReader(strings.NewReader(input), &out, nil), &out)
+
+	if len(q.queries) != 2 || q.queries[0] != "SELECT 1;" || q.queries[1] != "SELECT 2" {
+		t.Fatalf("expected two queries before exit, got %v", q.queries)
+	}
+	if !strings.Contains(out.String(), "(1 row") {
+		t.Fatalf("expected rendered results, got %q", out.String())
+	}
+}
+
+func TestRunShellMultiLine(t *testing.T) {
+	q := &fakeQuerier{columns: []string{"n"}, rows: [][]any{{1}}}
+	input := "SELECT id,\n\n       name\nFROM users\n  WHERE id = 1;\n"
+	var out strings.Builder
+
+	runShell(context.Background(), q, terminal.NewLineReader(strings.NewReader(input), &out, nil), &out)
+
+	want := "SELECT id,\n\n       name\nFROM users\n  WHERE id = 1;"
+	if len(q.queries) != 1 || q.queries[0] != want {
+		t.Fatalf("expected one accumulated statement %q, got %q", want, q.queries)
+	}
+	if !strings.Contains(out.String(), "sql-> ") {
+		t.Fatalf("expected continuation prompt, got %q", out.String())
+	}
+}
+
+func TestRunShellMetaCommands(t *testing.T) {
+	q := &fakeQuerier{columns: []string{"name"}, rows: [][]any{{"users"}}}
+	input := "\\dt\n\\d public.users;\n\\d users\n\\x\n"
+	var out strings.Builder
+
+	runShell(context.Background(), q, terminal.NewLineReader(strings.NewReader(input), &out, nil), &out)
+
+	if len(q.queries) != 3 || q.queries[0] != listTablesSQL || q.queries[1] != describeTableSQL {
+		t.Fatalf("expected list then describe queries, got %v", q.queries)
+	}
+	if got := q.args[1]; len(got) != 2 || got[0] != "public" || got[1] != "users" {
+		t.Fatalf("expected schema-qualified describe args, got %v", got)
+	}
+	if got := q.args[2]; len(got) != 2 || got[0] != "" || got[1] != "users" {
+		t.Fatalf("expected unqualified describe args, got %v", got)
+	}
+	if !strings.Contains(out.String(), "unknown command \\x") {
+		t.Fatalf("expected unknown meta-command error, got %q", out.String())
+	}
+}
+
+func TestRunShellDescribeMissingTable(t *testing.T) {
+	q := &fakeQuerier{columns: []string{"column"}}
+	var out strings.Builder
+
+	runShell(context.Background(), q, terminal.NewLineReader(strings.NewReader("\\d missing\n"), &out, nil), &out)
+
+	if !strings.Contains(out.String(), `Did not find any relation named "missing"`) {
+		t.Fatalf("expected missing relation message, got %q", out.String())
+	}
+}
+
+func writeSQLFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fix.sql")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write sql file: %v", err)
+	}
+	return path
+}
+
+func TestDBRun(t *testing.T) {
+	q := &fakeQuerier{}
+	path := writeSQLFile(t, "-- fix-up\nUPDATE users SET active = true WHERE id IN (1, 2);\n\nUPDATE orders SET note = 'a;b';\n")
+
+	result, err := newFakeDBCommands(q).run(context.Background(), map[string]any{"file": path})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(q.queries) != 2 || q.queries[1] != "UPDATE orders SET note = 'a;b'" {
+		t.Fatalf("expected two split statements, got %q", q.queries)
+	}
+	if !q.tx.committed {
+		t.Fatal("expected transaction to be committed")
+	}
+	results, ok := result.Data.([]StatementResult)
+	if !ok || len(results) != 2 || results[1].Line != 4 || results[0].RowsAffected != 2 || results[0].Command != "UPDATE" {
+		t.Fatalf("unexpected per-statement results: %#v", result.Data)
+	}
+	if !strings.Contains(result.Message, "2. UPDATE: 2 rows affected (line 4)") {
+		t.Fatalf("expected per-statement report, got %q", result.Message)
+	}
+}
+
+func TestDBRunRollsBackOnFailure(t *testing.T) {
+	q := &fakeQuerier{failOn: "DELETE FROM nowhere"}
+	path := writeSQLFile(t, "UPDATE users SET active = false;\nDELETE FROM nowhere;\nUPDATE never_reached SET x = 1;\n")
+
+	_, err := newFakeDBCommands(q).run(context.Background(), map[string]any{"file": path})
+	if err == nil || !strings.Contains(err.Error(), "statement 2 (line 2)") {
+		t.Fatalf("expected failing statement in error, got %v", err)
+	}
+	if q.tx.committed || !q.tx.rolledBack {
+		t.Fatal("expected transaction to be rolled back")
+	}
+	if len(q.queries) != 2 {
+		t.Fatalf("expected execution to stop at the failure, got %q", q.queries)
+	}
+
+	if _, err := newFakeDBCommands(q).run(context.Background(), map[string]any{"file": writeSQLFile(t, "-- nothing\n")}); err == nil {
+		t.Fatal("expected error for a script without statements")
+	}
+}
+
+func TestDBStatusMarksPendingMigrations(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	q := &fakeQuerier{
+		columns: []string{"version", "applied_at"},
+		rows:    [][]any{{int32(1), at}},
+	}
+	c := newFakeDBCommands(q)
+	c.migrations = func() ([]db.MigrationInfo, error) {
+		return []db.MigrationInfo{{Version: 1, Name: "001_init"}, {Version: 2, Name: "002_more"}}, nil
+	}
+
+	result, err := c.status(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	for _, want := range []string{"001_init", "APPLIED", "2025-06-01T12:00:00Z", "002_more", "PENDING", "1 applied, 1 pending"} {
+		if !strings.Contains(result.Message, want) {
+			t.Fatalf("expected %q in:\n%s", want, result.Message)
+		}
+	}
+	data := result.Data.(map[string]any)
+	if pending := data["pending"].([]int); len(pending) != 1 || pending[0] != 2 {
+		t.Fatalf("expected pending [2], got %v", pending)
+	}
+	if statuses := data["migrations"].([]db.MigrationStatus); len(statuses) != 2 || !statuses[0].Applied() {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+}
+
+func TestDBStatusWithoutSchemaTable(t *testing.T) {
+	q := &fakeQuerier{err: &pgconn.PgError{Code: "42P01", Message: `relation "schema_migrations" does not exist`}}
+	result, err := newFakeDBCommands(q).status(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	migrations, _ := db.Migrations()
+	if pending := result.Data.(map[string]any)["pending"].([]int); len(pending) != len(migrations) {
+		t.Fatalf("expected all %d migrations pending, got %v", len(migrations), pending)
+	}
+}
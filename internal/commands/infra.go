@@ -4,27 +4,48 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/raja-aiml/air/internal/engine"
 	"github.com/raja-aiml/air/internal/foundation/compose"
+	"github.com/raja-aiml/air/internal/foundation/terminal"
 )
 
+// ComposeManager is the subset of compose operations used by infrastructure commands.
+// *compose.Service implements it; tests can substitute a fake (see compose/fakecompose).
+type ComposeManager interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Status(ctx context.Context) (*compose.ServiceStatus, error)
+	Logs(ctx context.Context, serviceName string) (string, error)
+	WaitForHealthy(ctx context.Context, timeout time.Duration) error
+	Close() error
+}
+
+var _ ComposeManager = (*compose.Service)(nil)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
 // InfraCommands holds dependencies for infrastructure commands.
 type InfraCommands struct {
-	composeSvc *compose.Service
+	composeSvc ComposeManager
 }
 
 // NewInfraCommands creates infrastructure command handlers.
-func NewInfraCommands(composeSvc *compose.Service) *InfraCommands {
+func NewInfraCommands(composeSvc ComposeManager) *InfraCommands {
 	return &InfraCommands{composeSvc: composeSvc}
 }
 
 // Register adds all infrastructure commands to the registry.
 func (c *InfraCommands) Register(r *engine.Registry) {
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "infra.start",
+		Aliases:     []string{"up", "start"},
 		Description: "Start infrastructure services (postgres, jaeger, prometheus, otel-collector)",
 		Examples: []string{
 			"start infrastructure",
@@ -40,8 +61,9 @@ func (c *InfraCommands) Register(r *engine.Registry) {
 		Execute: c.start,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "infra.stop",
+		Aliases:     []string{"down", "stop"},
 		Description: "Stop infrastructure services",
 		Examples: []string{
 			"stop infrastructure",
@@ -54,8 +76,9 @@ func (c *InfraCommands) Register(r *engine.Registry) {
 		Execute:    c.stop,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "infra.status",
+		Aliases:     []string{"status", "ps"},
 		Description: "Show status of infrastructure services",
 		Examples: []string{
 			"show infrastructure status",
@@ -63,13 +86,18 @@ func (c *InfraCommands) Register(r *engine.Registry) {
 			"check service status",
 			"are services healthy",
 			"list running services",
+			"watch infrastructure status",
 		},
-		Parameters: []engine.Parameter{},
-		Execute:    c.status,
+		Parameters: []engine.Parameter{
+			{Name: "watch", Type: "bool", Default: false, Description: "Refresh status until all services are healthy"},
+			{Name: "interval", Type: "duration", Default: 2 * time.Second, Min: 100 * time.Millisecond, Description: "Refresh interval in watch mode"},
+		},
+		Execute: c.status,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "infra.logs",
+		Aliases:     []string{"logs"},
 		Description: "Show logs from infrastructure services",
 		Examples: []string{
 			"show logs",
@@ -83,7 +111,7 @@ func (c *InfraCommands) Register(r *engine.Registry) {
 		Execute: c.logs,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "infra.clean",
 		Description: "Remove all infrastructure containers, volumes, and networks",
 		Examples: []string{
@@ -124,15 +152,118 @@ func (c *InfraCommands) stop(ctx context.Context, params map[string]any) (engine
 }
 
 func (c *InfraCommands) status(ctx context.Context, params map[string]any) (engine.Result, error) {
+	p := engine.Params(params)
+	if p.Bool("watch", false) {
+		return c.watchStatus(ctx, p.Duration("interval", 2*time.Second))
+	}
+
 	status, err := c.composeSvc.Status(ctx)
 	if err != nil {
 		return engine.ErrorResult(err), err
 	}
 
-	// Format status for display
+	return engine.NewResultWithData(formatStatus(status), status), nil
+}
+
+// watchStatus polls status every interval until every project service is
+// present and healthy or the context is cancelled, and returns the last status
+// seen. Each poll is rendered to the caller's live output, if any, redrawing
+// in place on an interactive terminal.
+func (c *InfraCommands) watchStatus(ctx context.Context, interval time.Duration) (engine.Result, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	live, hasLive := engine.LiveOutput(ctx)
+	redraw := hasLive && isInteractive(live)
+	updates := c.statusUpdates(ctx, interval)
+
+	var last *compose.ServiceStatus
+	stopped := func(err error) (engine.Result, error) {
+		if last == nil {
+			return engine.ErrorResult(err), err
+		}
+		return engine.NewResultWithData(formatStatus(last)+"\nWatch stopped before all services were healthy", last), nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return stopped(ctx.Err())
+		case update, ok := <-updates:
+			if !ok {
+				return stopped(fmt.Errorf("status updates stopped"))
+			}
+			if update.err != nil {
+				return engine.ErrorResult(update.err), update.err
+			}
+
+			last = update.status
+			switch {
+			case redraw:
+				fmt.Fprintf(live, "%s%s\n(refreshing every %s, Ctrl+C to stop)\n", clearScreen, formatStatus(last), interval)
+			case hasLive:
+				fmt.Fprintln(live, formatStatus(last))
+			}
+
+			if last.Ready() {
+				return engine.NewResultWithData(formatStatus(last)+"\nAll services healthy", last), nil
+			}
+		}
+	}
+}
+
+type statusUpdate struct {
+	status *compose.ServiceStatus
+	err    error
+}
+
+// statusUpdates streams status by polling Status every interval; it stops after the first error.
+func (c *InfraCommands) statusUpdates(ctx context.Context, interval time.Duration) <-chan statusUpdate {
+	ch := make(chan statusUpdate)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			status, err := c.composeSvc.Status(ctx)
+			select {
+			case ch <- statusUpdate{status: status, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// isInteractive reports whether w is a terminal that accepts cursor control,
+// i.e. not redirected and not in plain (CI, NO_COLOR) mode.
+func isInteractive(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && terminal.IsTerminal(f) && !terminal.DetectEnvironment(false).Plain
+}
+
+// formatStatus renders service status for display, sorted by service name.
+func formatStatus(status *compose.ServiceStatus) string {
+	names := make([]string, 0, len(status.Services))
+	for name := range status.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	var sb strings.Builder
 	sb.WriteString("Infrastructure Status:\n")
-	for name, info := range status.Services {
+	for _, name := range names {
+		info := status.Services[name]
 		healthIcon := "?"
 		switch info.Health {
 		case "healthy":
@@ -147,8 +278,10 @@ func (c *InfraCommands) status(ctx context.Context, params map[string]any) (engi
 			sb.WriteString(fmt.Sprintf("    Ports: %s\n", strings.Join(info.Ports, ", ")))
 		}
 	}
-
-	return engine.NewResultWithData(sb.String(), status), nil
+	for _, name := range status.Missing {
+		sb.WriteString(fmt.Sprintf("  - %s: not created\n", name))
+	}
+	return sb.String()
 }
 
 func (c *InfraCommands) logs(ctx context.Context, params map[string]any) (engine.Result, error) {
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raja-aiml/air/internal/engine"
+	"github.com/raja-aiml/air/internal/foundation/compose"
+	"github.com/raja-aiml/air/internal/foundation/compose/fakecompose"
+)
+
+func newInfraRegistry(fake *fakecompose.Service) *engine.Registry {
+	r := engine.NewRegistry()
+	NewInfraCommands(fake).Register(r)
+	return r
+}
+
+func TestInfraStart(t *testing.T) {
+	fake := fakecompose.New("postgres")
+	r := newInfraRegistry(fake)
+
+	result, err := r.Execute(context.Background(), "infra.start", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got %q", result.Message)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 || calls[0] != "Start" || calls[1] != "WaitForHealthy" {
+		t.Fatalf("expected [Start WaitForHealthy], got %v", calls)
+	}
+}
+
+func TestInfraStartError(t *testing.T) {
+	fake := fakecompose.New()
+	fake.StartErr = compose.ErrDockerUnavailable
+	r := newInfraRegistry(fake)
+
+	result, err := r.Execute(context.Background(), "infra.start", nil)
+	if !errors.Is(err, compose.ErrDockerUnavailable) {
+		t.Fatalf("expected ErrDockerUnavailable, got %v", err)
+	}
+	if result.Success {
+		t.Fatal("expected failed result")
+	}
+}
+
+func TestInfraStartUnhealthy(t *testing.T) {
+	fake := fakecompose.New()
+	fake.WaitForHealthyErr = errors.New("timeout waiting for services to be healthy")
+	r := newInfraRegistry(fake)
+
+	result, err := r.Execute(context.Background(), "infra.start", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(result.Message, "health check failed") {
+		t.Fatalf("expected health check failure message, got %q", result.Message)
+	}
+}
+
+func TestInfraStatus(t *testing.T) {
+	fake := fakecompose.New()
+	fake.StatusResult = &compose.ServiceStatus{Services: map[string]compose.ServiceInfo{
+		"postgres": {Name: "postgres", State: "running", Health: "healthy", Ports: []string{"0.0.0.0:5432->5432/tcp"}},
+		"jaeger":   {Name: "jaeger", State: "running", Health: "starting"},
+	}}
+	r := newInfraRegistry(fake)
+
+	result, err := r.Execute(context.Background(), "infra.status", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"+ postgres: running (health: healthy)",
+		"Ports: 0.0.0.0:5432->5432/tcp",
+		"~ jaeger: running (health: starting)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result.Message, want) {
+			t.Fatalf("expected status output to contain %q, got:\n%s", want, result.Message)
+		}
+	}
+	if _, ok := result.Data.(*compose.ServiceStatus); !ok {
+		t.Fatalf("expected *compose.ServiceStatus data, got %T", result.Data)
+	}
+}
+
+func TestInfraLogs(t *testing.T) {
+	fake := fakecompose.New()
+	fake.ServiceLogs["postgres"] = "database system is ready"
+	r := newInfraRegistry(fake)
+
+	result, err := r.Execute(context.Background(), "infra.logs", map[string]any{"service": "postgres"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Message != "database system is ready" {
+		t.Fatalf("unexpected logs: %q", result.Message)
+	}
+
+	if _, err := r.Execute(context.Background(), "infra.logs", map[string]any{"service": "missing"}); err == nil {
+		t.Fatal("expected error for unknown service")
+	}
+}
+
+// sequencedFake returns canned statuses from Status in order, repeating the last one.
+type sequencedFake struct {
+	*fakecompose.Service
+	statuses []*compose.ServiceStatus
+}
+
+func (f *sequencedFake) Status(ctx context.Context) (*compose.ServiceStatus, error) {
+	f.Service.Status(ctx)
+	status := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+	return status, nil
+}
+
+func TestInfraStatusWatchUntilHealthy(t *testing.T) {
+	starting := &compose.ServiceStatus{Services: map[string]compose.ServiceInfo{
+		"postgres": {Name: "postgres", State: "running", Health: "starting"},
+	}, Missing: []string{"jaeger"}}
+	partial := &compose.ServiceStatus{Services: map[string]compose.ServiceInfo{
+		"postgres": {Name: "postgres", State: "running", Health: "healthy"},
+	}, Missing: []string{"jaeger"}}
+	healthy := &compose.ServiceStatus{Services: map[string]compose.ServiceInfo{
+		"postgres": {Name: "postgres", State: "running", Health: "healthy"},
+		"jaeger":   {Name: "jaeger", State: "running", Health: "none"},
+	}}
+	fake := &sequencedFake{Service: fakecompose.New(), statuses: []*compose.ServiceStatus{starting, partial, healthy}}
+
+	var out strings.Builder
+	ctx := engine.WithLiveOutput(context.Background(), &out)
+	result, err := NewInfraCommands(fake).status(ctx, map[string]any{"watch": true, "interval": time.Millisecond})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(result.Message, "All services healthy") {
+		t.Fatalf("expected healthy result, got %q", result.Message)
+	}
+	if n := len(fake.Calls()); n != 3 {
+		t.Fatalf("expected to wait for the missing service (3 polls), got %d", n)
+	}
+	if n := strings.Count(out.String(), "Infrastructure Status:"); n != 3 {
+		t.Fatalf("expected 3 renders, got %d", n)
+	}
+	if !strings.Contains(out.String(), "jaeger: not created") {
+		t.Fatalf("expected missing service in output, got %q", out.String())
+	}
+	if strings.Contains(out.String(), clearScreen) {
+		t.Fatalf("expected no clear-screen escape outside a terminal, got %q", out.String())
+	}
+}
+
+func TestInfraStatusWatchWithoutLiveOutput(t *testing.T) {
+	// e.g. the MCP server, whose stdout is the protocol stream
+	result, err := NewInfraCommands(fakecompose.New("postgres")).status(context.Background(), map[string]any{"watch": true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(result.Message, "All services healthy") {
+		t.Fatalf("expected healthy result, got %q", result.Message)
+	}
+}
+
+func TestInfraStatusWatchPollsUntilCancelled(t *testing.T) {
+	fake := fakecompose.New()
+	fake.StatusResult = &compose.ServiceStatus{Services: map[string]compose.ServiceInfo{
+		"jaeger": {Name: "jaeger", State: "running", Health: "starting"},
+	}}
+
+	var out strings.Builder
+	ctx, cancel := context.WithTimeout(engine.WithLiveOutput(context.Background(), &out), 50*time.Millisecond)
+	defer cancel()
+
+	result, err := NewInfraCommands(fake).status(ctx, map[string]any{"watch": true, "interval": 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(result.Message, "Watch stopped") {
+		t.Fatalf("expected stopped message, got %q", result.Message)
+	}
+	if len(fake.Calls()) < 2 {
+		t.Fatalf("expected repeated Status polling, got %v", fake.Calls())
+	}
+}
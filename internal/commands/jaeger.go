@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/httpclient"
+)
+
+// Page selects a window of list results.
+// A zero Limit returns everything after Offset.
+type Page struct {
+	Limit  int
+	Offset int
+	Filter string // case-insensitive substring match
+}
+
+// TraceSummary is a condensed view of a single Jaeger trace.
+type TraceSummary struct {
+	TraceID   string        `json:"trace_id"`
+	Operation string        `json:"operation"`
+	Spans     int           `json:"spans"`
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// queryRetries and queryBackoff retry query API calls that hit a backend
+// still starting up or briefly overloaded
+const (
+	queryRetries = 2
+	queryBackoff = 250 * time.Millisecond
+)
+
+// newQueryClient returns the HTTP client used for Jaeger and Prometheus
+// queries; each call shows up as a child span of the command's trace
+func newQueryClient() *httpclient.Client {
+	return httpclient.New(httpclient.DefaultTimeout,
+		httpclient.WithRetry(queryRetries, queryBackoff),
+		httpclient.WithTracing(),
+	)
+}
+
+// JaegerClient queries the Jaeger HTTP query API.
+type JaegerClient struct {
+	baseURL string
+	client  *httpclient.Client
+}
+
+// NewJaegerClient creates a client for the Jaeger query service at baseURL.
+func NewJaegerClient(baseURL string) *JaegerClient {
+	return &JaegerClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  newQueryClient(),
+	}
+}
+
+// Services returns one page of service names and the total number that matched the filter.
+func (j *JaegerClient) Services(ctx context.Context, page Page) ([]string, int, error) {
+	var result struct {
+		Data []string `json:"data"`
+	}
+	if err := j.client.GetJSON(ctx, j.baseURL+"/api/services", &result); err != nil {
+		return nil, 0, err
+	}
+
+	services := make([]string, 0, len(result.Data))
+	for _, svc := range result.Data {
+		if matchesFilter(svc, page.Filter) {
+			services = append(services, svc)
+		}
+	}
+	sort.Strings(services)
+
+	return paginate(services, page), len(services), nil
+}
+
+// maxTraceFetch caps how many traces Traces requests from Jaeger while
+// looking for enough filter matches
+const maxTraceFetch = 1000
+
+// Traces returns one page of traces for service, newest first. The filter
+// matches against the root operation name, and Offset and Limit count
+// matching traces. Jaeger supports neither, so Traces fetches the newest
+// Limit+Offset traces and, while a filter leaves too few matches, asks for
+// twice as many until there are enough, Jaeger runs out, or maxTraceFetch
+// is reached.
+func (j *JaegerClient) Traces(ctx context.Context, service string, lookback time.Duration, page Page) ([]TraceSummary, error) {
+	want := page.Limit + page.Offset
+	if page.Limit == 0 {
+		want = 0
+	}
+
+	fetch := want
+	for {
+		fetched, err := j.fetchTraces(ctx, service, lookback, fetch)
+		if err != nil {
+			return nil, err
+		}
+
+		traces := make([]TraceSummary, 0, len(fetched))
+		for _, t := range fetched {
+			if matchesFilter(t.Operation, page.Filter) {
+				traces = append(traces, t)
+			}
+		}
+		if fetch == 0 || len(traces) >= want || len(fetched) < fetch || fetch >= maxTraceFetch {
+			sort.Slice(traces, func(a, b int) bool {
+				return traces[a].StartTime.After(traces[b].StartTime)
+			})
+			return paginate(traces, page), nil
+		}
+		fetch = min(fetch*2, maxTraceFetch)
+	}
+}
+
+// fetchTraces queries up to limit traces for service (Jaeger's default
+// when limit is 0) and summarizes each by its root span
+func (j *JaegerClient) fetchTraces(ctx context.Context, service string, lookback time.Duration, limit int) ([]TraceSummary, error) {
+	q := url.Values{}
+	q.Set("service", service)
+	if lookback > 0 {
+		q.Set("lookback", lookback.String())
+		q.Set("start", strconv.FormatInt(time.Now().Add(-lookback).UnixMicro(), 10))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+
+	var result struct {
+		Data []struct {
+			TraceID string `json:"traceID"`
+			Spans   []struct {
+				OperationName string `json:"operationName"`
+				References    []struct {
+					RefType string `json:"refType"`
+				} `json:"references"`
+				StartTime int64 `json:"startTime"`
+				Duration  int64 `json:"duration"`
+			} `json:"spans"`
+		} `json:"data"`
+	}
+	if err := j.client.GetJSON(ctx, j.baseURL+"/api/traces?"+q.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	traces := make([]TraceSummary, 0, len(result.Data))
+	for _, t := range result.Data {
+		summary := TraceSummary{TraceID: t.TraceID, Spans: len(t.Spans)}
+		for _, span := range t.Spans {
+			if len(span.References) == 0 {
+				summary.Operation = span.OperationName
+				summary.StartTime = time.UnixMicro(span.StartTime)
+				summary.Duration = time.Duration(span.Duration) * time.Microsecond
+				break
+			}
+		}
+		traces = append(traces, summary)
+	}
+	return traces, nil
+}
+
+func matchesFilter(value, filter string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(value), strings.ToLower(filter))
+}
+
+// paginate returns the slice window selected by page.
+func paginate[T any](items []T, page Page) []T {
+	if page.Offset >= len(items) {
+		return []T{}
+	}
+	items = items[max(page.Offset, 0):]
+	if page.Limit > 0 && page.Limit < len(items) {
+		items = items[:page.Limit]
+	}
+	return items
+}
+
+// pageSummary describes which slice of total a page covers, e.g. "showing 21-40 of 120".
+func pageSummary(page Page, shown, total int) string {
+	if shown == 0 {
+		return fmt.Sprintf("showing 0 of %d", total)
+	}
+	return fmt.Sprintf("showing %d-%d of %d", page.Offset+1, page.Offset+shown, total)
+}
@@ -4,15 +4,25 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"go/ast"
 	"go/format"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/raja-aiml/air/internal/engine"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/cover"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
 	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 // LintCommands holds dependencies for linting commands.
@@ -25,7 +35,7 @@ func NewLintCommands() *LintCommands {
 
 // Register adds all linting commands to the registry.
 func (c *LintCommands) Register(r *engine.Registry) {
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "lint.check",
 		Description: "Run static analysis checks on Go code (uses go/analysis)",
 		Examples: []string{
@@ -41,7 +51,52 @@ func (c *LintCommands) Register(r *engine.Registry) {
 		Execute: c.check,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
+		Name:        "lint.errcheck",
+		Description: "Find calls whose error result is silently discarded (uses go/analysis)",
+		Examples: []string{
+			"check for unchecked errors",
+			"find ignored errors",
+			"errcheck",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "path", Type: "string", Default: "./...", Description: "Path to analyze"},
+			{Name: "exclude", Type: "[]string", Default: defaultErrcheckExclude, Description: "Functions whose errors may be ignored; a trailing * matches any suffix"},
+		},
+		Execute: c.errcheck,
+	})
+
+	r.MustRegister(&engine.Command{
+		Name:        "lint.complexity",
+		Description: "Report functions whose cyclomatic complexity exceeds a threshold",
+		Examples: []string{
+			"check complexity",
+			"find complex functions",
+			"cyclomatic complexity",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "path", Type: "string", Default: ".", Description: "Path to analyze"},
+			{Name: "threshold", Type: "int", Default: defaultComplexityThreshold, Min: 1, Description: "Report functions scoring above this"},
+		},
+		Execute: c.complexity,
+	})
+
+	r.MustRegister(&engine.Command{
+		Name:        "test.coverage",
+		Description: "Run tests with coverage and fail if total coverage is below a minimum",
+		Examples: []string{
+			"check test coverage",
+			"coverage gate",
+			"run tests with coverage",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "path", Type: "string", Default: "./...", Description: "Packages to test"},
+			{Name: "min", Type: "int", Default: defaultMinCoverage, Min: 0, Max: 100, Description: "Minimum total statement coverage, in percent"},
+		},
+		Execute: c.coverage,
+	})
+
+	r.MustRegister(&engine.Command{
 		Name:        "fmt.check",
 		Description: "Check if Go code is properly formatted",
 		Examples: []string{
@@ -55,7 +110,22 @@ func (c *LintCommands) Register(r *engine.Registry) {
 		Execute: c.formatCheck,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
+		Name:        "mod.check",
+		Description: "Check that go.mod and go.sum are tidy (pure Go, no go mod tidy)",
+		Examples: []string{
+			"check go.mod",
+			"is go.mod tidy",
+			"check dependencies",
+			"find unused dependencies",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "path", Type: "string", Default: ".", Description: "Module root directory"},
+		},
+		Execute: c.modCheck,
+	})
+
+	r.MustRegister(&engine.Command{
 		Name:        "fmt.fix",
 		Description: "Format Go code (pure Go, no gofmt binary required)",
 		Examples: []string{
@@ -109,6 +179,388 @@ func (c *LintCommands) check(ctx context.Context, params map[string]any) (engine
 	}), nil
 }
 
+// defaultErrcheckExclude lists calls whose error results are conventionally
+// ignored, in types.Func.FullName form
+var defaultErrcheckExclude = []string{
+	"fmt.Print*",
+	"fmt.Fprint*",
+	"(*bytes.Buffer).Write*",
+	"(*strings.Builder).Write*",
+}
+
+func (c *LintCommands) errcheck(ctx context.Context, params map[string]any) (engine.Result, error) {
+	p := engine.Params(params)
+	path := p.String("path", "./...")
+	exclude := p.StringSlice("exclude", defaultErrcheckExclude)
+	if s, ok := params["exclude"].(string); ok {
+		exclude = strings.Split(s, ",")
+	}
+
+	issues, err := runErrcheck(ctx, path, exclude)
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+
+	message := "Unchecked Errors:\n"
+	if len(issues) == 0 {
+		message += "  No issues found!"
+	} else {
+		message += fmt.Sprintf("  %d unchecked errors:\n", len(issues))
+		for _, issue := range issues {
+			message += fmt.Sprintf("    - %s\n", issue)
+		}
+	}
+
+	return engine.NewResultWithData(message, map[string]any{
+		"issues_count": len(issues),
+		"issues":       issues,
+	}), nil
+}
+
+// runErrcheck loads the packages matching pattern and reports each
+// discarded error as "file:line:col: message"
+func runErrcheck(ctx context.Context, pattern string, exclude []string) ([]string, error) {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.LoadSyntax | packages.NeedDeps,
+		Context: ctx,
+	}, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+
+	var issues []string
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			issues = append(issues, fmt.Sprintf("%s: %s", pkg.PkgPath, e.Msg))
+		}
+	}
+	if len(issues) > 0 {
+		return issues, nil
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{newErrcheckAnalyzer(exclude)}, pkgs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("run errcheck: %w", err)
+	}
+	for act := range graph.All() {
+		if act.Err != nil {
+			return nil, fmt.Errorf("errcheck %s: %w", act.Package.PkgPath, act.Err)
+		}
+		for _, d := range act.Diagnostics {
+			issues = append(issues, fmt.Sprintf("%s: %s", act.Package.Fset.Position(d.Pos), d.Message))
+		}
+	}
+	sort.Strings(issues)
+	return issues, nil
+}
+
+// newErrcheckAnalyzer reports expression, defer and go statements that
+// call a function returning an error, unless the callee matches exclude
+func newErrcheckAnalyzer(exclude []string) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: "errcheck",
+		Doc:  "report calls whose error result is discarded",
+		Run: func(pass *analysis.Pass) (any, error) {
+			for _, file := range pass.Files {
+				ast.Inspect(file, func(n ast.Node) bool {
+					var call *ast.CallExpr
+					switch stmt := n.(type) {
+					case *ast.ExprStmt:
+						call, _ = stmt.X.(*ast.CallExpr)
+					case *ast.DeferStmt:
+						call = stmt.Call
+					case *ast.GoStmt:
+						call = stmt.Call
+					}
+					if call == nil || !returnsError(pass.TypesInfo, call) {
+						return true
+					}
+					name := calleeName(pass.TypesInfo, call)
+					if errcheckExcluded(name, exclude) {
+						return true
+					}
+					pass.Reportf(call.Pos(), "error return value of %s is not checked", name)
+					return true
+				})
+			}
+			return nil, nil
+		},
+	}
+}
+
+// returnsError reports whether any result of call has type error
+func returnsError(info *types.Info, call *ast.CallExpr) bool {
+	tv, ok := info.Types[call]
+	if !ok || tv.IsType() {
+		return false // type conversion
+	}
+	errType := types.Universe.Lookup("error").Type()
+	switch t := tv.Type.(type) {
+	case *types.Tuple:
+		for v := range t.Variables() {
+			if types.Identical(v.Type(), errType) {
+				return true
+			}
+		}
+		return false
+	default:
+		return t != nil && types.Identical(t, errType)
+	}
+}
+
+// calleeName returns the callee's full name, such as
+// "(*net/http.Response).Write", falling back to its source text
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	if fn, ok := typeutil.Callee(info, call).(*types.Func); ok {
+		return fn.FullName()
+	}
+	return types.ExprString(call.Fun)
+}
+
+func errcheckExcluded(name string, exclude []string) bool {
+	for _, pattern := range exclude {
+		pattern = strings.TrimSpace(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultComplexityThreshold is the conventional gocyclo limit
+const defaultComplexityThreshold = 15
+
+// FunctionComplexity is the cyclomatic complexity of one function.
+type FunctionComplexity struct {
+	Function   string `json:"function"`
+	Position   string `json:"position"` // file:line of the declaration
+	Complexity int    `json:"complexity"`
+}
+
+func (c *LintCommands) complexity(ctx context.Context, params map[string]any) (engine.Result, error) {
+	p := engine.Params(params)
+	path := p.String("path", ".")
+	threshold := p.Int("threshold", defaultComplexityThreshold)
+
+	var flagged []FunctionComplexity
+	var errors []string
+
+	err := walkGoFiles(path, func(filePath string, info os.FileInfo) error {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, filePath, nil, 0)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", filePath, err))
+			return nil
+		}
+		for _, fc := range fileComplexity(fset, node) {
+			if fc.Complexity > threshold {
+				flagged = append(flagged, fc)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+
+	sort.SliceStable(flagged, func(i, j int) bool { return flagged[i].Complexity > flagged[j].Complexity })
+
+	message := "Complexity Results:\n"
+	if len(flagged) == 0 {
+		message += fmt.Sprintf("  No functions above complexity %d.", threshold)
+	} else {
+		message += fmt.Sprintf("  %d functions above complexity %d:\n", len(flagged), threshold)
+		for _, fc := range flagged {
+			message += fmt.Sprintf("    - %3d %s (%s)\n", fc.Complexity, fc.Function, fc.Position)
+		}
+	}
+
+	if len(errors) > 0 {
+		message += "\n  Errors:\n"
+		for _, e := range errors {
+			message += fmt.Sprintf("    - %s\n", e)
+		}
+	}
+
+	return engine.NewResultWithData(message, map[string]any{
+		"threshold": threshold,
+		"count":     len(flagged),
+		"functions": flagged,
+		"errors":    errors,
+	}), nil
+}
+
+// fileComplexity scores every function declaration in file. Function
+// literals count toward the function that contains them.
+func fileComplexity(fset *token.FileSet, file *ast.File) []FunctionComplexity {
+	var results []FunctionComplexity
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		pos := fset.Position(fn.Pos())
+		results = append(results, FunctionComplexity{
+			Function:   funcName(fn),
+			Position:   fmt.Sprintf("%s:%d", pos.Filename, pos.Line),
+			Complexity: cyclomatic(fn.Body),
+		})
+	}
+	return results
+}
+
+// cyclomatic returns 1 plus the number of decision points in body: if,
+// for and range statements, non-default case and select clauses, and the
+// && and || operators
+func cyclomatic(body *ast.BlockStmt) int {
+	score := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			score++
+		case *ast.CaseClause:
+			if n.List != nil {
+				score++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				score++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				score++
+			}
+		}
+		return true
+	})
+	return score
+}
+
+// funcName formats a declaration as "Func", "(T).Method" or "(*T).Method"
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return fmt.Sprintf("(%s).%s", recvTypeName(fn.Recv.List[0].Type), fn.Name.Name)
+}
+
+// recvTypeName returns a receiver type without type parameters
+func recvTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "*" + recvTypeName(t.X)
+	case *ast.IndexExpr:
+		return recvTypeName(t.X)
+	case *ast.IndexListExpr:
+		return recvTypeName(t.X)
+	default:
+		return types.ExprString(expr)
+	}
+}
+
+// defaultMinCoverage is the test.coverage threshold when --min is not given
+const defaultMinCoverage = 70
+
+// PackageCoverage is the statement coverage of one package.
+type PackageCoverage struct {
+	Package    string  `json:"package"`
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percent    float64 `json:"percent"`
+}
+
+func (c *LintCommands) coverage(ctx context.Context, params map[string]any) (engine.Result, error) {
+	p := engine.Params(params)
+	path := p.String("path", "./...")
+	minPercent := p.Int("min", defaultMinCoverage)
+
+	profiles, err := runCoverage(ctx, path)
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+	pkgs, total := coverageByPackage(profiles)
+
+	message := "Coverage Results:\n"
+	for _, pc := range pkgs {
+		message += fmt.Sprintf("  %6.1f%%  %s\n", pc.Percent, pc.Package)
+	}
+	message += fmt.Sprintf("\n  Total: %.1f%% of statements (minimum %d%%)", total, minPercent)
+
+	data := map[string]any{
+		"total":    total,
+		"min":      minPercent,
+		"packages": pkgs,
+	}
+	if total < float64(minPercent) {
+		err := fmt.Errorf("coverage %.1f%% is below the %d%% minimum", total, minPercent)
+		return engine.Result{Success: false, Message: message, Data: data}, err
+	}
+	return engine.NewResultWithData(message, data), nil
+}
+
+// runCoverage runs go test with a coverage profile for pattern and parses it
+func runCoverage(ctx context.Context, pattern string) ([]*cover.Profile, error) {
+	profile, err := os.CreateTemp("", "air-coverage-*.out")
+	if err != nil {
+		return nil, fmt.Errorf("create coverage profile: %w", err)
+	}
+	profile.Close()
+	defer os.Remove(profile.Name())
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-covermode=set", "-coverprofile="+profile.Name(), pattern)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("go test failed: %w\n%s", err, bytes.TrimSpace(out))
+	}
+
+	profiles, err := cover.ParseProfiles(profile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("parse coverage profile: %w", err)
+	}
+	return profiles, nil
+}
+
+// coverageByPackage totals statement coverage per package (the directory
+// of each profiled file) and overall, as go tool cover -func does
+func coverageByPackage(profiles []*cover.Profile) ([]PackageCoverage, float64) {
+	byPkg := make(map[string]*PackageCoverage)
+	var statements, covered int
+	for _, prof := range profiles {
+		pkg := filepath.ToSlash(filepath.Dir(prof.FileName))
+		pc, ok := byPkg[pkg]
+		if !ok {
+			pc = &PackageCoverage{Package: pkg}
+			byPkg[pkg] = pc
+		}
+		for _, b := range prof.Blocks {
+			pc.Statements += b.NumStmt
+			statements += b.NumStmt
+			if b.Count > 0 {
+				pc.Covered += b.NumStmt
+				covered += b.NumStmt
+			}
+		}
+	}
+
+	pkgs := make([]PackageCoverage, 0, len(byPkg))
+	for _, pc := range byPkg {
+		pc.Percent = percentOf(pc.Covered, pc.Statements)
+		pkgs = append(pkgs, *pc)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Package < pkgs[j].Package })
+	return pkgs, percentOf(covered, statements)
+}
+
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(total)
+}
+
 func (c *LintCommands) formatCheck(ctx context.Context, params map[string]any) (engine.Result, error) {
 	pr := engine.Params(params)
 	path := pr.String("path", ".")
@@ -225,6 +677,174 @@ func (c *LintCommands) formatFix(ctx context.Context, params map[string]any) (en
 	}), nil
 }
 
+func (c *LintCommands) modCheck(ctx context.Context, params map[string]any) (engine.Result, error) {
+	dir := engine.Params(params).String("path", ".")
+
+	issues, err := checkModTidy(ctx, dir)
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+
+	message := "Module Check Results:\n"
+	if len(issues) == 0 {
+		message += "  go.mod and go.sum are tidy!"
+	} else {
+		message += fmt.Sprintf("  %d issues found:\n", len(issues))
+		for _, issue := range issues {
+			message += fmt.Sprintf("    - %s\n", issue)
+		}
+		message += "\n  Run 'go mod tidy' to fix them."
+	}
+
+	return engine.NewResultWithData(message, map[string]any{
+		"issues_count": len(issues),
+		"issues":       issues,
+	}), nil
+}
+
+// checkModTidy compares the imports of every Go file in the module at dir
+// (all build tags and platforms, as go mod tidy considers them) against the
+// requirements in go.mod, and the modules actually loaded against go.sum
+func checkModTidy(ctx context.Context, dir string) ([]string, error) {
+	goModPath := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("read go.mod: %w", err)
+	}
+	mf, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parse go.mod: %w", err)
+	}
+	sums, err := readGoSum(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err := moduleImports(dir)
+	if err != nil {
+		return nil, err
+	}
+	issues := checkRequirements(mf, imports)
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		Context: ctx,
+		Dir:     dir,
+		Tests:   true,
+	}, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, e := range pkg.Errors {
+			if strings.Contains(e.Msg, "go.sum") {
+				issues = append(issues, e.Msg)
+			}
+		}
+		// Replaced modules are hashed under their replacement, if at all
+		m := pkg.Module
+		if m == nil || m.Main || m.Replace != nil || m.Version == "" {
+			return
+		}
+		if !sums[m.Path+" "+m.Version] {
+			issues = append(issues, fmt.Sprintf("%s %s is missing from go.sum", m.Path, m.Version))
+		}
+	})
+
+	sort.Strings(issues)
+	return slices.Compact(issues), nil
+}
+
+// moduleImports returns the import paths of all Go files under dir,
+// ignoring build constraints, vendor and testdata directories
+func moduleImports(dir string) ([]string, error) {
+	seen := make(map[string]bool)
+	err := walkGoFiles(dir, func(path string, info os.FileInfo) error {
+		if slices.Contains(strings.Split(filepath.ToSlash(path), "/"), "testdata") {
+			return nil
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		for _, imp := range file.Imports {
+			seen[strings.Trim(imp.Path.Value, `"`)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	imports := make([]string, 0, len(seen))
+	for imp := range seen {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// checkRequirements reports imports no requirement provides, and direct
+// requirements whose "// indirect" marking disagrees with the imports
+func checkRequirements(mf *modfile.File, imports []string) []string {
+	var issues []string
+	imported := make(map[string]bool)
+	for _, imp := range imports {
+		first, _, _ := strings.Cut(imp, "/")
+		if !strings.Contains(first, ".") || inModule(imp, mf.Module.Mod.Path) {
+			continue // standard library or the module itself
+		}
+
+		provider := ""
+		for _, req := range mf.Require {
+			if inModule(imp, req.Mod.Path) && len(req.Mod.Path) > len(provider) {
+				provider = req.Mod.Path
+			}
+		}
+		if provider == "" {
+			issues = append(issues, fmt.Sprintf("%s is imported but no module in go.mod provides it", imp))
+			continue
+		}
+		imported[provider] = true
+	}
+
+	for _, req := range mf.Require {
+		switch {
+		case req.Indirect && imported[req.Mod.Path]:
+			issues = append(issues, fmt.Sprintf("%s is imported directly but marked // indirect", req.Mod.Path))
+		case !req.Indirect && !imported[req.Mod.Path]:
+			issues = append(issues, fmt.Sprintf("%s is not imported; remove it or mark it // indirect", req.Mod.Path))
+		}
+	}
+	return issues
+}
+
+// inModule reports whether importPath is modulePath or a package within it
+func inModule(importPath, modulePath string) bool {
+	return importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")
+}
+
+// readGoSum returns the "path version" pairs that have a module hash in
+// go.sum; a missing go.sum is treated as empty
+func readGoSum(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read go.sum: %w", err)
+	}
+
+	sums := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && !strings.HasSuffix(fields[1], "/go.mod") {
+			sums[fields[0]+" "+fields[1]] = true
+		}
+	}
+	return sums, nil
+}
+
 // walkGoFiles walks the directory tree and calls fn for each .go file,
 // skipping hidden directories and vendor.
 func walkGoFiles(root string, fn func(path string, info os.FileInfo) error) error {
@@ -233,9 +853,9 @@ func walkGoFiles(root string, fn func(path string, info os.FileInfo) error) erro
 			return err
 		}
 
-		// Skip hidden directories and vendor
+		// Skip hidden directories and vendor (but never the root, which may be "." or "..")
 		if info.IsDir() {
-			if strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" {
+			if path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor") {
 				return filepath.SkipDir
 			}
 			return nil
@@ -0,0 +1,334 @@
+package commands
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/tools/cover"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	mf, err := modfile.Parse("go.mod", []byte(`module example.com/app
+
+go 1.24
+
+require (
+	github.com/spf13/cobra v1.10.1
+	github.com/unused/lib v1.0.0
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/transitive/only v0.1.0 // indirect
+)
+`), nil)
+	if err != nil {
+		t.Fatalf("parse go.mod: %v", err)
+	}
+
+	imports := []string{
+		"context",
+		"example.com/app/internal/engine",
+		"github.com/spf13/cobra",
+		"gopkg.in/yaml.v3",
+		"github.com/missing/dep/pkg",
+	}
+
+	got := strings.Join(checkRequirements(mf, imports), "\n")
+	for _, want := range []string{
+		"github.com/missing/dep/pkg is imported but no module in go.mod provides it",
+		"gopkg.in/yaml.v3 is imported directly but marked // indirect",
+		"github.com/unused/lib is not imported; remove it or mark it // indirect",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected issue %q, got:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"cobra", "transitive/only", "context", "example.com/app"} {
+		if strings.Contains(got, unwanted) {
+			t.Fatalf("unexpected issue mentioning %s:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestModCheckTidyModule(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/tidy\n\ngo 1.24\n",
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"hi\") }\n",
+		// Build-constrained files still count, as they do for go mod tidy
+		"extra_windows.go": "//go:build windows\n\npackage main\n\nimport _ \"os\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	result, err := NewLintCommands().modCheck(context.Background(), map[string]any{"path": dir})
+	if err != nil {
+		t.Fatalf("modCheck: %v", err)
+	}
+	if data := result.Data.(map[string]any); data["issues_count"] != 0 {
+		t.Fatalf("expected tidy module, got %v", data["issues"])
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "dep.go"), []byte("package main\n\nimport _ \"github.com/nowhere/dep\"\n"), 0o644); err != nil {
+		t.Fatalf("write dep.go: %v", err)
+	}
+	result, err = NewLintCommands().modCheck(context.Background(), map[string]any{"path": dir})
+	if err != nil {
+		t.Fatalf("modCheck: %v", err)
+	}
+	if !strings.Contains(result.Message, "github.com/nowhere/dep is imported but no module in go.mod provides it") {
+		t.Fatalf("expected missing requirement, got %q", result.Message)
+	}
+}
+
+func TestWalkGoFilesFromDot(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", filepath.Join(".hidden", "b.go")} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package a\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	t.Chdir(dir)
+
+	var found []string
+	if err := walkGoFiles(".", func(path string, info os.FileInfo) error {
+		found = append(found, path)
+		return nil
+	}); err != nil {
+		t.Fatalf("walkGoFiles: %v", err)
+	}
+	if len(found) != 1 || found[0] != "a.go" {
+		t.Fatalf("expected only a.go from \".\", got %v", found)
+	}
+}
+
+func TestErrcheckReportsDiscardedErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/errs\n\ngo 1.24\n",
+		"main.go": `package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func fail() error { return nil }
+
+func pair() (int, error) { return 0, nil }
+
+func main() {
+	fail()
+	pair()
+	defer os.Remove("x")
+	go fail()
+	_ = fail()
+	if err := fail(); err != nil {
+		return
+	}
+	fmt.Println("ignored by default")
+	var b strings.Builder
+	b.WriteString("ignored by default")
+	os.Setenv("A", "B")
+	_ = error(nil)
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	t.Chdir(dir)
+
+	issues, err := runErrcheck(context.Background(), "./...", defaultErrcheckExclude)
+	if err != nil {
+		t.Fatalf("runErrcheck: %v", err)
+	}
+	got := strings.Join(issues, "\n")
+	for _, want := range []string{
+		"main.go:14:2: error return value of example.com/errs.fail is not checked",
+		"main.go:15:2: error return value of example.com/errs.pair is not checked",
+		"main.go:16:8: error return value of os.Remove is not checked",
+		"main.go:17:5: error return value of example.com/errs.fail is not checked",
+		"main.go:25:2: error return value of os.Setenv is not checked",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in:\n%s", want, got)
+		}
+	}
+	if len(issues) != 5 {
+		t.Fatalf("expected 5 issues, got %d:\n%s", len(issues), got)
+	}
+
+	issues, err = runErrcheck(context.Background(), "./...", []string{"os.Setenv", "example.com/errs.*"})
+	if err != nil {
+		t.Fatalf("runErrcheck: %v", err)
+	}
+	// A custom allowlist replaces the defaults
+	if len(issues) != 3 {
+		t.Fatalf("expected only os.Remove, fmt.Println and WriteString with a custom allowlist, got:\n%s", strings.Join(issues, "\n"))
+	}
+}
+
+func TestErrcheckExcluded(t *testing.T) {
+	exclude := []string{"fmt.Print*", "os.Setenv"}
+	for name, want := range map[string]bool{
+		"fmt.Println":      true,
+		"fmt.Printf":       true,
+		"fmt.Fprintf":      false,
+		"os.Setenv":        true,
+		"os.Setenvs":       false,
+		"os.Unsetenv":      false,
+		"(*os.File).Close": false,
+	} {
+		if got := errcheckExcluded(name, exclude); got != want {
+			t.Fatalf("errcheckExcluded(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFileComplexity(t *testing.T) {
+	src := `package p
+
+func simple() {}
+
+func branchy(a, b bool, xs []int) int {
+	if a && b {
+		return 1
+	}
+	for _, x := range xs {
+		switch x {
+		case 1, 2:
+		case 3:
+		default:
+		}
+	}
+	f := func() {
+		if a || b {
+		}
+	}
+	f()
+	return 0
+}
+
+type T[K comparable] struct{}
+
+func (t *T[K]) Method(ch chan int) {
+	select {
+	case <-ch:
+	default:
+	}
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	got := fileComplexity(fset, file)
+	want := []FunctionComplexity{
+		{Function: "simple", Position: "p.go:3", Complexity: 1},
+		// if, &&, range, two cases, and the closure's if and ||
+		{Function: "branchy", Position: "p.go:5", Complexity: 8},
+		{Function: "(*T).Method", Position: "p.go:26", Complexity: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d functions, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("function %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestComplexityThreshold(t *testing.T) {
+	dir := t.TempDir()
+	src := "package p\n\nfunc f(a, b, c bool) {\n\tif a {\n\t}\n\tif b {\n\t}\n\tif c {\n\t}\n}\n\nfunc g() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := NewLintCommands().complexity(context.Background(), map[string]any{"path": dir, "threshold": 3})
+	if err != nil {
+		t.Fatalf("complexity: %v", err)
+	}
+	functions := result.Data.(map[string]any)["functions"].([]FunctionComplexity)
+	if len(functions) != 1 || functions[0].Function != "f" || functions[0].Complexity != 4 {
+		t.Fatalf("expected only f with score 4, got %+v", functions)
+	}
+	if !strings.Contains(result.Message, "p.go:3") {
+		t.Fatalf("expected file:line in message, got %q", result.Message)
+	}
+}
+
+func TestCoverageByPackage(t *testing.T) {
+	profiles, err := cover.ParseProfilesFromReader(strings.NewReader(`mode: set
+example.com/m/a/a.go:3.10,5.2 2 1
+example.com/m/a/a.go:7.10,9.2 2 0
+example.com/m/a/b.go:3.10,5.2 1 1
+example.com/m/c/c.go:3.10,5.2 5 0
+`))
+	if err != nil {
+		t.Fatalf("parse profile: %v", err)
+	}
+
+	pkgs, total := coverageByPackage(profiles)
+	if len(pkgs) != 2 {
+		t.Fatalf("expected 2 packages, got %+v", pkgs)
+	}
+	if a := pkgs[0]; a.Package != "example.com/m/a" || a.Statements != 5 || a.Covered != 3 || a.Percent != 60 {
+		t.Fatalf("unexpected coverage for a: %+v", a)
+	}
+	if c := pkgs[1]; c.Package != "example.com/m/c" || c.Percent != 0 {
+		t.Fatalf("unexpected coverage for c: %+v", c)
+	}
+	if total != 30 {
+		t.Fatalf("expected 30%% total, got %v", total)
+	}
+}
+
+func TestCoverageGate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":      "module example.com/cov\n\ngo 1.24\n",
+		"cov.go":      "package cov\n\nfunc Half(b bool) int {\n\tif b {\n\t\treturn 1\n\t}\n\treturn 0\n}\n",
+		"cov_test.go": "package cov\n\nimport \"testing\"\n\nfunc TestHalf(t *testing.T) {\n\tif Half(true) != 1 {\n\t\tt.Fatal(\"bad\")\n\t}\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	t.Chdir(dir)
+
+	result, err := NewLintCommands().coverage(context.Background(), map[string]any{"min": 50})
+	if err != nil {
+		t.Fatalf("expected coverage above 50%%: %v\n%s", err, result.Message)
+	}
+	if total := result.Data.(map[string]any)["total"].(float64); total < 50 || total >= 100 {
+		t.Fatalf("unexpected total %v", total)
+	}
+
+	result, err = NewLintCommands().coverage(context.Background(), map[string]any{"min": 100})
+	if err == nil || result.Success {
+		t.Fatalf("expected the gate to fail at 100%%, got %+v", result)
+	}
+	if pkgs := result.Data.(map[string]any)["packages"].([]PackageCoverage); len(pkgs) != 1 || pkgs[0].Package != "example.com/cov" {
+		t.Fatalf("expected per-package data on failure, got %+v", result.Data)
+	}
+}
@@ -1,31 +1,61 @@
 package commands
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/raja-aiml/air/internal/engine"
+	"github.com/raja-aiml/air/internal/foundation/compose"
 	"github.com/raja-aiml/air/internal/foundation/httpclient"
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+	"github.com/raja-aiml/air/internal/foundation/terminal"
 )
 
+// defaultCollectorService is the compose service obs.debug tails
+const defaultCollectorService = "otel-collector"
+
+// LogStreamer streams a compose service's logs. *compose.Service
+// implements it; obs.debug needs one (see ObsCommands.WithCollectorLogs).
+type LogStreamer interface {
+	LogsStream(ctx context.Context, serviceName string, follow bool) (io.ReadCloser, error)
+}
+
+var _ LogStreamer = (*compose.Service)(nil)
+
 // ObsCommands holds dependencies for observability commands.
 type ObsCommands struct {
 	jaegerURL     string
 	prometheusURL string
+	logs          LogStreamer // collector logs for obs.debug; nil when no compose project
 }
 
 // NewObsCommands creates observability command handlers.
 func NewObsCommands() *ObsCommands {
+	return NewObsCommandsWithURLs("http://localhost:16686", "http://localhost:9090")
+}
+
+// NewObsCommandsWithURLs creates observability command handlers for custom Jaeger and Prometheus URLs.
+func NewObsCommandsWithURLs(jaegerURL, prometheusURL string) *ObsCommands {
 	return &ObsCommands{
-		jaegerURL:     "http://localhost:16686",
-		prometheusURL: "http://localhost:9090",
+		jaegerURL:     jaegerURL,
+		prometheusURL: prometheusURL,
 	}
 }
 
+// WithCollectorLogs sets where obs.debug reads the OTEL collector's logs
+func (c *ObsCommands) WithCollectorLogs(logs LogStreamer) *ObsCommands {
+	c.logs = logs
+	return c
+}
+
 // Register adds all observability commands to the registry.
 func (c *ObsCommands) Register(r *engine.Registry) {
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "obs.verify",
 		Description: "Verify observability stack is healthy (Jaeger, Prometheus)",
 		Examples: []string{
@@ -39,7 +69,7 @@ func (c *ObsCommands) Register(r *engine.Registry) {
 		Execute:    c.verify,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "obs.urls",
 		Description: "Show URLs for observability services",
 		Examples: []string{
@@ -52,7 +82,7 @@ func (c *ObsCommands) Register(r *engine.Registry) {
 		Execute:    c.urls,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "obs.services",
 		Description: "List available services in Jaeger",
 		Examples: []string{
@@ -60,11 +90,33 @@ func (c *ObsCommands) Register(r *engine.Registry) {
 			"show jaeger services",
 			"what services are being traced",
 		},
-		Parameters: []engine.Parameter{},
-		Execute:    c.services,
+		Parameters: []engine.Parameter{
+			{Name: "limit", Type: "int", Default: 50, Min: 0, Description: "Maximum services to show (0 for all)"},
+			{Name: "offset", Type: "int", Default: 0, Min: 0, Description: "Number of services to skip"},
+			{Name: "filter", Type: "string", Description: "Only show services containing this text"},
+		},
+		Execute: c.services,
+	})
+
+	r.MustRegister(&engine.Command{
+		Name:        "obs.traces",
+		Description: "List recent traces for a service in Jaeger",
+		Examples: []string{
+			"list traces",
+			"show recent traces",
+			"find traces for service",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "service", Type: "string", Required: true, Description: "Service name to list traces for"},
+			{Name: "limit", Type: "int", Default: 20, Min: 0, Description: "Maximum traces to show"},
+			{Name: "offset", Type: "int", Default: 0, Min: 0, Description: "Number of traces to skip"},
+			{Name: "filter", Type: "string", Description: "Only show traces whose root operation contains this text"},
+			{Name: "lookback", Type: "duration", Default: "1h", Description: "How far back to search"},
+		},
+		Execute: c.traces,
 	})
 
-	r.Register(&engine.Command{
+	r.MustRegister(&engine.Command{
 		Name:        "obs.metrics",
 		Description: "Query Prometheus metrics",
 		Examples: []string{
@@ -77,6 +129,23 @@ func (c *ObsCommands) Register(r *engine.Registry) {
 		},
 		Execute: c.metrics,
 	})
+
+	r.MustRegister(&engine.Command{
+		Name:        "obs.debug",
+		Description: "Show the OTEL collector's export logs, highlighting export errors",
+		Examples: []string{
+			"debug the otel collector",
+			"why are traces missing",
+			"watch exporter errors",
+			"tail collector export errors",
+		},
+		Parameters: []engine.Parameter{
+			{Name: "service", Type: "string", Default: defaultCollectorService, Description: "Compose service running the collector"},
+			{Name: "follow", Type: "bool", Default: false, Description: "Keep streaming new lines to the terminal until interrupted (CLI only)"},
+			{Name: "all", Type: "bool", Default: false, Description: "Show every collector line, not just export-related ones"},
+		},
+		Execute: c.debug,
+	})
 }
 
 func (c *ObsCommands) verify(ctx context.Context, params map[string]any) (engine.Result, error) {
@@ -131,33 +200,74 @@ func (c *ObsCommands) urls(ctx context.Context, params map[string]any) (engine.R
 }
 
 func (c *ObsCommands) services(ctx context.Context, params map[string]any) (engine.Result, error) {
-	client := httpclient.Default()
+	page := pageFromParams(engine.Params(params), 50)
 
-	var result struct {
-		Data []string `json:"data"`
-	}
-	if err := client.GetJSON(ctx, c.jaegerURL+"/api/services", &result); err != nil {
+	services, total, err := NewJaegerClient(c.jaegerURL).Services(ctx, page)
+	if err != nil {
 		err = fmt.Errorf("failed to connect to Jaeger: %w", err)
 		return engine.ErrorResult(err), err
 	}
 
 	message := "Traced Services in Jaeger:\n"
-	if len(result.Data) == 0 {
+	if total == 0 {
 		message += "  (no services found - run your application to generate traces)"
 	} else {
-		for _, svc := range result.Data {
+		for _, svc := range services {
 			message += fmt.Sprintf("  - %s\n", svc)
 		}
+		message += fmt.Sprintf("\n(%s)", pageSummary(page, len(services), total))
+	}
+
+	return engine.NewResultWithData(message, services), nil
+}
+
+func (c *ObsCommands) traces(ctx context.Context, params map[string]any) (engine.Result, error) {
+	p := engine.Params(params)
+	service, err := p.StringRequired("service")
+	if err != nil {
+		return engine.ErrorResult(err), err
+	}
+	page := pageFromParams(p, 20)
+	lookback := p.Duration("lookback", time.Hour)
+
+	traces, err := NewJaegerClient(c.jaegerURL).Traces(ctx, service, lookback, page)
+	if err != nil {
+		err = fmt.Errorf("failed to connect to Jaeger: %w", err)
+		return engine.ErrorResult(err), err
+	}
+
+	message := fmt.Sprintf("Traces for %s (last %s):\n", service, lookback)
+	if len(traces) == 0 {
+		message += "  (no traces found)"
+	} else {
+		for _, t := range traces {
+			message += fmt.Sprintf("  %s  %-30s %3d spans  %s\n", t.TraceID, t.Operation, t.Spans, t.Duration)
+		}
+		message += fmt.Sprintf("\n(showing %d-%d", page.Offset+1, page.Offset+len(traces))
+		// A short page means there are no more matches
+		if page.Limit > 0 && len(traces) == page.Limit {
+			message += fmt.Sprintf(", use --offset %d for more", page.Offset+len(traces))
+		}
+		message += ")"
 	}
 
-	return engine.NewResultWithData(message, result.Data), nil
+	return engine.NewResultWithData(message, traces), nil
+}
+
+// pageFromParams reads limit, offset and filter parameters.
+func pageFromParams(p engine.Params, defaultLimit int) Page {
+	return Page{
+		Limit:  max(p.Int("limit", defaultLimit), 0),
+		Offset: max(p.Int("offset", 0), 0),
+		Filter: p.String("filter", ""),
+	}
 }
 
 func (c *ObsCommands) metrics(ctx context.Context, params map[string]any) (engine.Result, error) {
 	p := engine.Params(params)
 	query := p.String("query", "up")
 
-	client := httpclient.Default()
+	client := newQueryClient()
 
 	url := fmt.Sprintf("%s/api/v1/query?query=%s", c.prometheusURL, query)
 	var result map[string]interface{}
@@ -176,3 +286,77 @@ func (c *ObsCommands) metrics(ctx context.Context, params map[string]any) (engin
 func (c *ObsCommands) checkEndpoint(ctx context.Context, url string) bool {
 	return httpclient.Default().CheckEndpoint(ctx, url)
 }
+
+// debug reads the collector's recent logs, keeping export-related lines
+// and marking export errors, and returns them in the Result. With follow
+// it instead streams lines to the CLI's terminal (see engine.LiveOutput)
+// until the logs end or ctx is cancelled; callers without one, such as MCP
+// clients, get an error rather than a call that never returns.
+func (c *ObsCommands) debug(ctx context.Context, params map[string]any) (engine.Result, error) {
+	if c.logs == nil {
+		err := fmt.Errorf("obs.debug needs a compose project to read collector logs from (see --compose-file)")
+		return engine.ErrorResult(err), err
+	}
+	p := engine.Params(params)
+	service := p.String("service", defaultCollectorService)
+	all := p.Bool("all", false)
+	follow := p.Bool("follow", false)
+
+	live, hasTerminal := engine.LiveOutput(ctx)
+	if follow && !hasTerminal {
+		err := fmt.Errorf("obs.debug follow streams to a terminal; run it from the air CLI, or omit follow")
+		return engine.ErrorResult(err), err
+	}
+
+	logs, err := c.logs.LogsStream(ctx, service, follow)
+	if err != nil {
+		err = fmt.Errorf("stream %s logs: %w", service, err)
+		return engine.ErrorResult(err), err
+	}
+	defer logs.Close()
+
+	color := follow && !terminal.NoColor()
+	var lines []string
+	var shown, failures int
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !all && !telemetry.IsCollectorExportLine(line) {
+			continue
+		}
+		shown++
+		if telemetry.CollectorExportError(line) != "" {
+			failures++
+			if color {
+				line = "\033[31m" + line + "\033[0m"
+			} else {
+				line = "ERROR " + line
+			}
+		}
+		if follow {
+			fmt.Fprintln(live, line)
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	// Cancelling ctx (Ctrl+C) is the normal way to stop following
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		err = fmt.Errorf("read %s logs: %w", service, err)
+		return engine.ErrorResult(err), err
+	}
+
+	summary := map[string]any{"lines": shown, "errors": failures}
+	var message strings.Builder
+	if !follow {
+		summary["log"] = lines
+		for _, line := range lines {
+			message.WriteString(line + "\n")
+		}
+	}
+	fmt.Fprintf(&message, "%d export lines from %s, %d export errors", shown, service, failures)
+	if failures > 0 {
+		message.WriteString("\nCheck that the exporter endpoints in the collector config are reachable from its container")
+	}
+	return engine.NewResultWithData(message.String(), summary), nil
+}
@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/raja-aiml/air/internal/engine"
+	"github.com/raja-aiml/air/internal/foundation/compose/fakecompose"
+)
+
+func newJaegerStub(t *testing.T, services []string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":["%s"]}`, strings.Join(services, `","`))
+	})
+	mux.HandleFunc("/api/traces", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("service") != "api" {
+			t.Errorf("unexpected service query: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"data":[
+			{"traceID":"t1","spans":[{"operationName":"ws.connection","startTime":1000,"duration":50},{"operationName":"db.query","references":[{"refType":"CHILD_OF"}],"startTime":1010,"duration":5}]},
+			{"traceID":"t2","spans":[{"operationName":"http.request","startTime":3000,"duration":20}]},
+			{"traceID":"t3","spans":[{"operationName":"ws.connection","startTime":2000,"duration":10}]}
+		]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		page Page
+		want []int
+	}{
+		{Page{}, []int{1, 2, 3, 4, 5}},
+		{Page{Limit: 2}, []int{1, 2}},
+		{Page{Limit: 2, Offset: 2}, []int{3, 4}},
+		{Page{Limit: 10, Offset: 4}, []int{5}},
+		{Page{Offset: 9}, []int{}},
+	}
+	for _, tt := range tests {
+		got := paginate(items, tt.page)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Fatalf("paginate(%+v) = %v, want %v", tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestObsServicesPagination(t *testing.T) {
+	jaeger := newJaegerStub(t, []string{"worker", "api", "gateway", "api-admin", "billing"})
+	r := engine.NewRegistry()
+	NewObsCommandsWithURLs(jaeger.URL, "").Register(r)
+
+	result, err := r.Execute(context.Background(), "obs.services", map[string]any{"limit": "2", "offset": "1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	services := result.Data.([]string)
+	if fmt.Sprint(services) != "[api-admin billing]" {
+		t.Fatalf("unexpected page: %v", services)
+	}
+	if !strings.Contains(result.Message, "showing 2-3 of 5") {
+		t.Fatalf("expected page summary, got:\n%s", result.Message)
+	}
+
+	result, err = r.Execute(context.Background(), "obs.services", map[string]any{"filter": "API"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if fmt.Sprint(result.Data) != "[api api-admin]" {
+		t.Fatalf("unexpected filtered services: %v", result.Data)
+	}
+}
+
+func TestObsTraces(t *testing.T) {
+	jaeger := newJaegerStub(t, nil)
+	r := engine.NewRegistry()
+	NewObsCommandsWithURLs(jaeger.URL, "").Register(r)
+
+	result, err := r.Execute(context.Background(), "obs.traces", map[string]any{"service": "api", "limit": 2})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	traces := result.Data.([]TraceSummary)
+	if len(traces) != 2 || traces[0].TraceID != "t2" || traces[1].TraceID != "t3" {
+		t.Fatalf("expected newest traces [t2 t3], got %+v", traces)
+	}
+
+	result, err = r.Execute(context.Background(), "obs.traces", map[string]any{"service": "api", "filter": "ws.", "offset": 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	traces = result.Data.([]TraceSummary)
+	if len(traces) != 1 || traces[0].TraceID != "t1" || traces[0].Spans != 2 {
+		t.Fatalf("expected filtered trace t1 with 2 spans, got %+v", traces)
+	}
+
+	if _, err := r.Execute(context.Background(), "obs.traces", nil); err == nil {
+		t.Fatal("expected error for missing service")
+	}
+}
+
+func TestObsTracesFilteredPagination(t *testing.T) {
+	// 30 traces, newest first; every third has a ws.connection root
+	var limits []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/traces", func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var data []string
+		for i := 0; i < min(limit, 30); i++ {
+			op := "http.request"
+			if i%3 == 0 {
+				op = "ws.connection"
+			}
+			data = append(data, fmt.Sprintf(`{"traceID":"t%d","spans":[{"operationName":%q,"startTime":%d,"duration":1}]}`, i, op, 100-i))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	})
+	jaeger := httptest.NewServer(mux)
+	defer jaeger.Close()
+	r := engine.NewRegistry()
+	NewObsCommandsWithURLs(jaeger.URL, "").Register(r)
+
+	result, err := r.Execute(context.Background(), "obs.traces", map[string]any{"service": "api", "filter": "ws.", "limit": 3, "offset": 2})
+	if err != nil {
+		t.Fatalf("obs.traces: %v", err)
+	}
+	var ids []string
+	for _, trace := range result.Data.([]TraceSummary) {
+		ids = append(ids, trace.TraceID)
+	}
+	if fmt.Sprint(ids) != "[t6 t9 t12]" {
+		t.Fatalf("expected the 3rd-5th matching traces, got %v", ids)
+	}
+	if fmt.Sprint(limits) != "[5 10 20]" {
+		t.Fatalf("expected the fetch to grow until 5 matches were found, got limits %v", limits)
+	}
+	if !strings.Contains(result.Message, "use --offset 5 for more") {
+		t.Fatalf("expected a more-results hint for a full page:\n%s", result.Message)
+	}
+
+	// The last 10 matches: a short page once Jaeger runs out
+	result, err = r.Execute(context.Background(), "obs.traces", map[string]any{"service": "api", "filter": "ws.", "limit": 4, "offset": 8})
+	if err != nil {
+		t.Fatalf("obs.traces: %v", err)
+	}
+	if traces := result.Data.([]TraceSummary); len(traces) != 2 || traces[1].TraceID != "t27" {
+		t.Fatalf("expected the last 2 matches, got %+v", traces)
+	}
+	if strings.Contains(result.Message, "for more") {
+		t.Fatalf("short page still offers more results:\n%s", result.Message)
+	}
+}
+
+func TestObsDebugFiltersExportLines(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	logs := fakecompose.New("otel-collector")
+	logs.ServiceLogs["otel-collector"] = strings.Join([]string{
+		"info	Everything is ready. Begin running and processing data.",
+		"info	exporterhelper	Exporting traces	{\"name\": \"otlp/jaeger\"}",
+		"error	exporterhelper	Exporting failed. Dropping data.	{\"error\": \"connection refused\"}",
+		"info	memorylimiter	Memory usage is within limits",
+	}, "\n")
+
+	r := engine.NewRegistry()
+	NewObsCommandsWithURLs("", "").WithCollectorLogs(logs).Register(r)
+
+	// Without follow the lines come back in the Result, never on stdout
+	result, err := r.Execute(context.Background(), "obs.debug", nil)
+	if err != nil {
+		t.Fatalf("obs.debug: %v", err)
+	}
+	want := []string{
+		"info	exporterhelper	Exporting traces	{\"name\": \"otlp/jaeger\"}",
+		"ERROR error	exporterhelper	Exporting failed. Dropping data.	{\"error\": \"connection refused\"}",
+	}
+	if got := result.Data.(map[string]any)["log"].([]string); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("log lines:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.HasPrefix(result.Message, want[0]+"\n"+want[1]+"\n") ||
+		!strings.Contains(result.Message, "2 export lines from otel-collector, 1 export errors") {
+		t.Fatalf("unexpected message: %s", result.Message)
+	}
+
+	// Following streams to the CLI's terminal instead
+	var out strings.Builder
+	ctx := engine.WithLiveOutput(context.Background(), &out)
+	result, err = r.Execute(ctx, "obs.debug", map[string]any{"follow": "true"})
+	if err != nil {
+		t.Fatalf("obs.debug follow: %v", err)
+	}
+	if out.String() != strings.Join(want, "\n")+"\n" {
+		t.Fatalf("streamed output:\n%s\nwant:\n%s", out.String(), strings.Join(want, "\n"))
+	}
+	if _, ok := result.Data.(map[string]any)["log"]; ok || strings.Contains(result.Message, "Exporting traces") {
+		t.Fatalf("followed lines were repeated in the result: %s", result.Message)
+	}
+}
+
+func TestObsDebugFollowRequiresTerminal(t *testing.T) {
+	r := engine.NewRegistry()
+	NewObsCommandsWithURLs("", "").WithCollectorLogs(fakecompose.New("otel-collector")).Register(r)
+
+	// An MCP call has no live output; following would never return
+	_, err := r.Execute(context.Background(), "obs.debug", map[string]any{"follow": true})
+	if err == nil || !strings.Contains(err.Error(), "run it from the air CLI") {
+		t.Fatalf("expected follow without a terminal to be rejected, got %v", err)
+	}
+}
+
+func TestObsDebugWithoutCompose(t *testing.T) {
+	r := engine.NewRegistry()
+	NewObsCommandsWithURLs("", "").Register(r)
+	if _, err := r.Execute(context.Background(), "obs.debug", nil); err == nil {
+		t.Fatal("obs.debug succeeded without a log source")
+	}
+}
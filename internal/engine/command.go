@@ -11,6 +11,10 @@ type Command struct {
 	// Name is the unique identifier for this command (e.g., "infra.start")
 	Name string
 
+	// Aliases are alternative names Registry.Get and Execute resolve to
+	// this command (e.g. "up" for "infra.start")
+	Aliases []string
+
 	// Description is a human-readable description for help and LLM context
 	Description string
 
@@ -31,6 +35,15 @@ type Parameter struct {
 	Required    bool
 	Default     any
 	Description string
+
+	// Enum lists the accepted values of a string or []string parameter;
+	// empty accepts any
+	Enum []string
+
+	// Min and Max bound an int (int) or duration (time.Duration)
+	// parameter, inclusive; nil leaves that side unbounded
+	Min any
+	Max any
 }
 
 // Result represents the outcome of a command execution.
@@ -96,6 +109,21 @@ func (c *Command) ParameterSchema() map[string]any {
 		if p.Default != nil {
 			prop["default"] = p.Default
 		}
+		if len(p.Enum) > 0 {
+			if p.Type == "[]string" {
+				prop["items"] = map[string]any{"type": "string", "enum": p.Enum}
+			} else {
+				prop["enum"] = p.Enum
+			}
+		}
+		if p.Type == "int" {
+			if p.Min != nil {
+				prop["minimum"] = p.Min
+			}
+			if p.Max != nil {
+				prop["maximum"] = p.Max
+			}
+		}
 
 		properties[p.Name] = prop
 
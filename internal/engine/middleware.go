@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"context"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+	"github.com/raja-aiml/air/internal/foundation/logging"
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+)
+
+// Handler executes a command with resolved parameters.
+type Handler func(ctx context.Context, params map[string]any) (Result, error)
+
+// Middleware wraps the handler of cmd. Register with Registry.Use. A
+// middleware runs code before and after next, or returns without calling
+// it to short-circuit the command.
+type Middleware func(cmd *Command, next Handler) Handler
+
+// TracingMiddleware runs each command in a span named "command <name>", so
+// CLI and MCP invocations show up in Jaeger with the spans they cause. A
+// returned error or unsuccessful Result marks the span as an error, tagged
+// with the AppError code.
+func TracingMiddleware() Middleware {
+	return func(cmd *Command, next Handler) Handler {
+		return func(ctx context.Context, params map[string]any) (Result, error) {
+			ctx, span := telemetry.StartSpanWithCorrelation(ctx, "command "+cmd.Name,
+				trace.WithAttributes(attribute.String("command", cmd.Name)),
+			)
+			defer span.End()
+
+			result, err := next(ctx, params)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetAttributes(attribute.String("error.code", apperrors.Code(err)))
+				span.SetStatus(codes.Error, err.Error())
+			case !result.Success:
+				span.SetStatus(codes.Error, result.Message)
+			}
+			return result, err
+		}
+	}
+}
+
+// RecoverMiddleware recovers panics in command handlers, records them on the
+// active span and returns an internal AppError instead of crashing the process.
+// This keeps the CLI and MCP server alive when a single command panics.
+func RecoverMiddleware() Middleware {
+	return func(cmd *Command, next Handler) Handler {
+		return func(ctx context.Context, params map[string]any) (result Result, err error) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				appErr := apperrors.FromPanic(v).
+					WithDetail("command", cmd.Name).
+					WithRequestID(telemetry.GetRequestID(ctx))
+				telemetry.LogError(ctx, "command panicked", appErr,
+					attribute.String("command", cmd.Name),
+					attribute.String("panic.stack", string(debug.Stack())),
+				)
+				logging.ContextLogger(ctx).Error().Err(appErr).Str("command", cmd.Name).Msg("command panicked")
+				result, err = ErrorResult(appErr), appErr
+			}()
+			return next(ctx, params)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+	"github.com/raja-aiml/air/pkg/spantest"
+)
+
+func TestRecoverMiddleware(t *testing.T) {
+	r := NewRegistry()
+	r.Use(RecoverMiddleware())
+	r.MustRegister(&Command{
+		Name: "test.panic",
+		Execute: func(ctx context.Context, params map[string]any) (Result, error) {
+			panic("boom")
+		},
+	})
+
+	result, err := r.Execute(context.Background(), "test.panic", nil)
+	if !apperrors.Is(err, apperrors.ErrCodeInternal) {
+		t.Fatalf("expected internal AppError, got %v", err)
+	}
+	if result.Success || !strings.Contains(result.Message, "boom") {
+		t.Fatalf("expected failed result mentioning the panic, got %+v", result)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(cmd *Command, next Handler) Handler {
+			return func(ctx context.Context, params map[string]any) (Result, error) {
+				calls = append(calls, name+":"+cmd.Name)
+				return next(ctx, params)
+			}
+		}
+	}
+
+	r := NewRegistry()
+	r.Use(trace("outer"), trace("inner"))
+	r.MustRegister(&Command{
+		Name: "test.ok",
+		Execute: func(ctx context.Context, params map[string]any) (Result, error) {
+			calls = append(calls, "execute")
+			return NewResult("ok"), nil
+		},
+	})
+
+	if _, err := r.Execute(context.Background(), "test.ok", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(calls, ",") != "outer:test.ok,inner:test.ok,execute" {
+		t.Fatalf("unexpected call order: %v", calls)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	denied := errors.New("denied")
+	var seen string
+	r := NewRegistry()
+	r.Use(func(cmd *Command, next Handler) Handler {
+		return func(ctx context.Context, params map[string]any) (Result, error) {
+			seen = cmd.Name
+			if cmd.Name == "test.blocked" {
+				return ErrorResult(denied), denied
+			}
+			return next(ctx, params)
+		}
+	})
+	executed := false
+	r.MustRegister(&Command{
+		Name: "test.blocked",
+		Execute: func(ctx context.Context, params map[string]any) (Result, error) {
+			executed = true
+			return NewResult("ran"), nil
+		},
+	})
+
+	result, err := r.Execute(context.Background(), "test.blocked", nil)
+	if !errors.Is(err, denied) || result.Success {
+		t.Fatalf("expected the middleware's error, got %+v, %v", result, err)
+	}
+	if executed {
+		t.Fatal("command ran despite the middleware short-circuiting")
+	}
+	if seen != "test.blocked" {
+		t.Fatalf("middleware saw command %q", seen)
+	}
+	if result.Duration <= 0 {
+		t.Fatal("Execute did not record a duration")
+	}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	exporter := spantest.Record(t)
+
+	r := NewRegistry()
+	r.Use(TracingMiddleware())
+	r.MustRegister(&Command{
+		Name: "test.ok",
+		Execute: func(ctx context.Context, params map[string]any) (Result, error) {
+			return NewResult("ok"), nil
+		},
+	})
+	r.MustRegister(&Command{
+		Name: "test.fail",
+		Execute: func(ctx context.Context, params map[string]any) (Result, error) {
+			err := apperrors.New(apperrors.ErrCodeNotFound, "missing")
+			return ErrorResult(err), err
+		},
+	})
+
+	_, _ = r.Execute(context.Background(), "test.ok", nil)
+	_, _ = r.Execute(context.Background(), "test.fail", nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "command test.ok" || spans[0].Status.Code == codes.Error {
+		t.Fatalf("unexpected span for test.ok: %s %v", spans[0].Name, spans[0].Status)
+	}
+	failed := spans[1]
+	if failed.Name != "command test.fail" || failed.Status.Code != codes.Error {
+		t.Fatalf("unexpected span for test.fail: %s %v", failed.Name, failed.Status)
+	}
+	spantest.AssertSpanHasAttribute(t, failed, "error.code", apperrors.ErrCodeNotFound)
+}
@@ -0,0 +1,23 @@
+package engine
+
+import (
+	"context"
+	"io"
+)
+
+// liveOutputKey is the context key WithLiveOutput stores the writer under
+type liveOutputKey struct{}
+
+// WithLiveOutput marks ctx as coming from an interactive CLI caller whose
+// terminal w may receive output while a command runs, such as obs.debug
+// --follow. Callers whose stdout is a protocol stream (the MCP server)
+// must not set it; commands then return everything in their Result.
+func WithLiveOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, liveOutputKey{}, w)
+}
+
+// LiveOutput returns the writer set by WithLiveOutput
+func LiveOutput(ctx context.Context) (io.Writer, bool) {
+	w, ok := ctx.Value(liveOutputKey{}).(io.Writer)
+	return w, ok && w != nil
+}
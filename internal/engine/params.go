@@ -1,8 +1,15 @@
 package engine
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
 )
 
 // Params wraps map[string]any with type-safe accessors.
@@ -41,6 +48,10 @@ func (p Params) Int(key string, defaultVal int) int {
 		return int(v)
 	case float64:
 		return int(v)
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
 	}
 	return defaultVal
 }
@@ -82,3 +93,183 @@ func (p Params) Has(key string) bool {
 	_, ok := p[key]
 	return ok
 }
+
+// Validate checks p against cmd's declared parameters, in place: supplied
+// values are coerced to their Parameter.Type (so "5" becomes 5 for an int
+// and "30s" a time.Duration), Enum and Min/Max are enforced, required
+// parameters must be present, and absent ones take their Default. Keys cmd
+// does not declare pass through untouched.
+//
+// Invalid input is a protocol.invalid_payload AppError naming every
+// offending parameter; its "parameters" detail maps each name to the
+// problem.
+func (p Params) Validate(cmd *Command) error {
+	var problems []string
+	invalid := make(map[string]string)
+	for _, param := range cmd.Parameters {
+		raw, ok := p[param.Name]
+		if !ok || raw == nil {
+			if param.Required {
+				problems = append(problems, param.Name+": required")
+				invalid[param.Name] = "required"
+				continue
+			}
+			if param.Default == nil {
+				continue
+			}
+			value, err := coerceParam(param, param.Default)
+			if err != nil {
+				return fmt.Errorf("command %s: default for %s: %w", cmd.Name, param.Name, err)
+			}
+			p[param.Name] = value
+			continue
+		}
+
+		value, err := coerceParam(param, raw)
+		if err == nil {
+			err = checkConstraints(param, value)
+		}
+		if err != nil {
+			problems = append(problems, param.Name+": "+err.Error())
+			invalid[param.Name] = err.Error()
+			continue
+		}
+		p[param.Name] = value
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return apperrors.InvalidPayload(fmt.Sprintf("invalid parameters for %s: %s", cmd.Name, strings.Join(problems, "; "))).
+		WithDetail("command", cmd.Name).
+		WithDetail("parameters", invalid)
+}
+
+// coerceParam converts v to the Go type of param.Type: string, bool, int,
+// time.Duration or []string. Strings (from CLI flags) are parsed, JSON
+// numbers must be whole for ints, and a string for []string is split on
+// commas. An unknown Type leaves v as it is.
+func coerceParam(param Parameter, v any) (any, error) {
+	switch param.Type {
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case "bool":
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			if parsed, err := strconv.ParseBool(b); err == nil {
+				return parsed, nil
+			}
+		}
+	case "int":
+		if n, ok := coerceInt(v); ok {
+			return n, nil
+		}
+	case "duration":
+		switch d := v.(type) {
+		case time.Duration:
+			return d, nil
+		case string:
+			if parsed, err := time.ParseDuration(d); err == nil {
+				return parsed, nil
+			}
+		}
+	case "[]string":
+		switch list := v.(type) {
+		case []string:
+			return list, nil
+		case string:
+			var out []string
+			for _, item := range strings.Split(list, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					out = append(out, item)
+				}
+			}
+			return out, nil
+		case []any:
+			out := make([]string, 0, len(list))
+			for _, item := range list {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("expected a list of strings, got %s", describeValue(item))
+				}
+				out = append(out, s)
+			}
+			return out, nil
+		}
+	default:
+		return v, nil
+	}
+	return nil, fmt.Errorf("expected %s, got %s", param.Type, describeValue(v))
+}
+
+// coerceInt accepts Go integers, whole float64s (JSON numbers) and decimal
+// strings
+func coerceInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		if n == math.Trunc(n) && n >= math.MinInt && n < math.MaxInt {
+			return int(n), true
+		}
+	case json.Number:
+		if parsed, err := strconv.Atoi(n.String()); err == nil {
+			return parsed, true
+		}
+	case string:
+		if parsed, err := strconv.Atoi(strings.TrimSpace(n)); err == nil {
+			return parsed, true
+		}
+	}
+	return 0, false
+}
+
+// checkConstraints applies param's Enum and Min/Max to a coerced value
+func checkConstraints(param Parameter, v any) error {
+	if len(param.Enum) > 0 {
+		values, _ := v.([]string)
+		if s, ok := v.(string); ok {
+			values = []string{s}
+		}
+		for _, value := range values {
+			if !slices.Contains(param.Enum, value) {
+				return fmt.Errorf("%q is not one of %s", value, strings.Join(param.Enum, ", "))
+			}
+		}
+	}
+
+	switch value := v.(type) {
+	case int:
+		if minimum, ok := param.Min.(int); ok && value < minimum {
+			return fmt.Errorf("%d is below the minimum %d", value, minimum)
+		}
+		if maximum, ok := param.Max.(int); ok && value > maximum {
+			return fmt.Errorf("%d is above the maximum %d", value, maximum)
+		}
+	case time.Duration:
+		if minimum, ok := param.Min.(time.Duration); ok && value < minimum {
+			return fmt.Errorf("%s is below the minimum %s", value, minimum)
+		}
+		if maximum, ok := param.Max.(time.Duration); ok && value > maximum {
+			return fmt.Errorf("%s is above the maximum %s", value, maximum)
+		}
+	}
+	return nil
+}
+
+// describeValue quotes strings and names the type of anything else, for
+// type mismatch messages
+func describeValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v (%T)", v, v)
+}
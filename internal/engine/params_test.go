@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+)
+
+func validatedCommand() *Command {
+	return &Command{
+		Name: "test.validate",
+		Parameters: []Parameter{
+			{Name: "version", Type: "int", Required: true, Min: 0},
+			{Name: "limit", Type: "int", Default: 20, Min: 0, Max: 100},
+			{Name: "format", Type: "string", Default: "table", Enum: []string{"table", "json", "csv"}},
+			{Name: "timeout", Type: "duration", Default: "1m", Min: time.Second},
+			{Name: "follow", Type: "bool", Default: false},
+			{Name: "exclude", Type: "[]string"},
+		},
+	}
+}
+
+func TestValidateCoercesAndAppliesDefaults(t *testing.T) {
+	p := Params{
+		"version": "3",         // CLI flag
+		"limit":   float64(50), // JSON number
+		"follow":  "true",
+		"exclude": "fmt.*, os.Exit",
+		"extra":   "kept",
+	}
+	if err := p.Validate(validatedCommand()); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if p["version"] != 3 || p["limit"] != 50 || p["follow"] != true {
+		t.Fatalf("expected coerced int and bool values, got %#v", p)
+	}
+	if p["format"] != "table" || p["timeout"] != time.Minute {
+		t.Fatalf("expected coerced defaults, got format=%#v timeout=%#v", p["format"], p["timeout"])
+	}
+	if got := p.StringSlice("exclude", nil); len(got) != 2 || got[0] != "fmt.*" || got[1] != "os.Exit" {
+		t.Fatalf("expected comma-separated list, got %#v", p["exclude"])
+	}
+	if p["extra"] != "kept" {
+		t.Fatal("expected undeclared parameters to pass through")
+	}
+}
+
+func TestValidateRejectsInvalidParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+		param  string
+		want   string
+	}{
+		{"missing required", Params{}, "version", "required"},
+		{"wrong type", Params{"version": "abc"}, "version", `expected int, got "abc"`},
+		{"fractional number", Params{"version": 1.5}, "version", "expected int, got 1.5 (float64)"},
+		{"below minimum", Params{"version": -1}, "version", "-1 is below the minimum 0"},
+		{"above maximum", Params{"version": 1, "limit": "101"}, "limit", "101 is above the maximum 100"},
+		{"duration below minimum", Params{"version": 1, "timeout": "10ms"}, "timeout", "10ms is below the minimum 1s"},
+		{"bad duration", Params{"version": 1, "timeout": "soon"}, "timeout", `expected duration, got "soon"`},
+		{"not in enum", Params{"version": 1, "format": "xml"}, "format", `"xml" is not one of table, json, csv`},
+		{"bad bool", Params{"version": 1, "follow": "maybe"}, "follow", `expected bool, got "maybe"`},
+		{"bad list item", Params{"version": 1, "exclude": []any{"fmt.*", 3}}, "exclude", "expected a list of strings"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate(validatedCommand())
+			var appErr *apperrors.AppError
+			if !errors.As(err, &appErr) || appErr.Code != apperrors.ErrCodeInvalidPayload {
+				t.Fatalf("expected a %s AppError, got %v", apperrors.ErrCodeInvalidPayload, err)
+			}
+			invalid, _ := appErr.Details["parameters"].(map[string]string)
+			if len(invalid) != 1 || !strings.Contains(invalid[tt.param], tt.want) {
+				t.Fatalf("expected %s: %q in details, got %v", tt.param, tt.want, appErr.Details)
+			}
+			if !strings.Contains(appErr.Message, "test.validate") || !strings.Contains(appErr.Message, tt.param+": ") {
+				t.Fatalf("message does not name the command and parameter: %s", appErr.Message)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryInvalidParameter(t *testing.T) {
+	err := Params{"limit": "many", "format": "xml"}.Validate(validatedCommand())
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		t.Fatalf("expected an AppError, got %v", err)
+	}
+	invalid := appErr.Details["parameters"].(map[string]string)
+	if len(invalid) != 3 {
+		t.Fatalf("expected version, limit and format to be reported, got %v", invalid)
+	}
+}
+
+func TestExecuteValidatesBeforeRunning(t *testing.T) {
+	ran := false
+	cmd := validatedCommand()
+	cmd.Execute = func(ctx context.Context, params map[string]any) (Result, error) {
+		ran = true
+		return NewResult("ok"), nil
+	}
+	r := NewRegistry()
+	r.MustRegister(cmd)
+
+	if _, err := r.Execute(context.Background(), "test.validate", map[string]any{"version": "abc"}); !apperrors.Is(err, apperrors.ErrCodeInvalidPayload) {
+		t.Fatalf("expected invalid payload error, got %v", err)
+	}
+	if ran {
+		t.Fatal("handler ran with invalid parameters")
+	}
+
+	if _, err := r.Execute(context.Background(), "test.validate", map[string]any{"version": "2"}); err != nil || !ran {
+		t.Fatalf("expected valid parameters to run the handler, got %v", err)
+	}
+}
+
+func TestParameterSchemaConstraints(t *testing.T) {
+	props := validatedCommand().ParameterSchema()["properties"].(map[string]any)
+
+	limit := props["limit"].(map[string]any)
+	if limit["minimum"] != 0 || limit["maximum"] != 100 {
+		t.Fatalf("expected limit bounds in schema, got %v", limit)
+	}
+	format := props["format"].(map[string]any)
+	if enum, _ := format["enum"].([]string); len(enum) != 3 {
+		t.Fatalf("expected format enum in schema, got %v", format)
+	}
+}
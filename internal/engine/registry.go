@@ -9,28 +9,78 @@ import (
 
 // Registry manages all registered commands.
 type Registry struct {
-	commands map[string]*Command
-	mu       sync.RWMutex
+	commands   map[string]*Command
+	aliases    map[string]string // alias -> canonical command name
+	middleware []Middleware
+	mu         sync.RWMutex
 }
 
 // NewRegistry creates a new command registry.
 func NewRegistry() *Registry {
 	return &Registry{
 		commands: make(map[string]*Command),
+		aliases:  make(map[string]string),
 	}
 }
 
-// Register adds a command to the registry.
-func (r *Registry) Register(cmd *Command) {
+// Register adds a command to the registry. A command registered under an
+// existing name replaces it, aliases included. It fails, registering
+// nothing, when the name or an alias is already taken by another command.
+func (r *Registry) Register(cmd *Command) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+
+	if owner, ok := r.aliases[cmd.Name]; ok {
+		return fmt.Errorf("register %s: name is already an alias of %s", cmd.Name, owner)
+	}
+	seen := make(map[string]bool, len(cmd.Aliases))
+	for _, alias := range cmd.Aliases {
+		switch owner, isAlias := r.aliases[alias]; {
+		case alias == cmd.Name || seen[alias]:
+			return fmt.Errorf("register %s: duplicate alias %q", cmd.Name, alias)
+		case r.commands[alias] != nil:
+			return fmt.Errorf("register %s: alias %q is already a command name", cmd.Name, alias)
+		case isAlias && owner != cmd.Name:
+			return fmt.Errorf("register %s: alias %q is already an alias of %s", cmd.Name, alias, owner)
+		}
+		seen[alias] = true
+	}
+
+	if old, ok := r.commands[cmd.Name]; ok {
+		for _, alias := range old.Aliases {
+			delete(r.aliases, alias)
+		}
+	}
 	r.commands[cmd.Name] = cmd
+	for _, alias := range cmd.Aliases {
+		r.aliases[alias] = cmd.Name
+	}
+	return nil
+}
+
+// MustRegister is Register for built-in commands, whose names are fixed; it
+// panics when a name or alias collides.
+func (r *Registry) MustRegister(cmd *Command) {
+	if err := r.Register(cmd); err != nil {
+		panic(err)
+	}
 }
 
-// Get retrieves a command by name.
+// Use appends middleware applied to every Execute call.
+// The first middleware registered is the outermost.
+func (r *Registry) Use(mw ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw...)
+}
+
+// Get retrieves a command by name or alias.
 func (r *Registry) Get(name string) (*Command, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
+	if canonical, ok := r.aliases[name]; ok {
+		name = canonical
+	}
 	cmd, ok := r.commands[name]
 	return cmd, ok
 }
@@ -46,7 +96,7 @@ func (r *Registry) All() []*Command {
 	return cmds
 }
 
-// Names returns all registered command names.
+// Names returns all registered command names, without aliases.
 func (r *Registry) Names() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -57,39 +107,40 @@ func (r *Registry) Names() []string {
 	return names
 }
 
-// Execute runs a command by name with the given parameters.
+// Execute runs a command by name or alias with the given parameters,
+// validated and coerced by Params.Validate first. Middleware always sees
+// the canonical command.
 func (r *Registry) Execute(ctx context.Context, name string, params map[string]any) (Result, error) {
 	cmd, ok := r.Get(name)
 	if !ok {
 		return Result{}, fmt.Errorf("command not found: %s", name)
 	}
 
-	// Apply defaults for missing parameters
 	if params == nil {
 		params = make(map[string]any)
 	}
-	for _, p := range cmd.Parameters {
-		if _, exists := params[p.Name]; !exists && p.Default != nil {
-			params[p.Name] = p.Default
-		}
-	}
-
-	// Validate required parameters
-	for _, p := range cmd.Parameters {
-		if p.Required {
-			if _, exists := params[p.Name]; !exists {
-				return Result{}, fmt.Errorf("missing required parameter: %s", p.Name)
-			}
-		}
+	if err := Params(params).Validate(cmd); err != nil {
+		return Result{}, err
 	}
 
 	start := time.Now()
-	result, err := cmd.Execute(ctx, params)
+	result, err := r.handler(cmd)(ctx, params)
 	result.Duration = time.Since(start)
 
 	return result, err
 }
 
+// handler wraps cmd.Execute in the registered middleware chain.
+func (r *Registry) handler(cmd *Command) Handler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h := Handler(cmd.Execute)
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		h = r.middleware[i](cmd, h)
+	}
+	return h
+}
+
 // Count returns the number of registered commands.
 func (r *Registry) Count() int {
 	r.mu.RLock()
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func namedCommand(name string, aliases ...string) *Command {
+	return &Command{
+		Name:    name,
+		Aliases: aliases,
+		Execute: func(ctx context.Context, params map[string]any) (Result, error) {
+			return NewResult(name), nil
+		},
+	}
+}
+
+func TestRegistryAliases(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(namedCommand("infra.start", "up", "start")); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	for _, name := range []string{"infra.start", "up", "start"} {
+		cmd, ok := r.Get(name)
+		if !ok || cmd.Name != "infra.start" {
+			t.Fatalf("Get(%q) = %v, %v; want infra.start", name, cmd, ok)
+		}
+	}
+
+	var seen string
+	r.Use(func(cmd *Command, next Handler) Handler {
+		seen = cmd.Name
+		return next
+	})
+	result, err := r.Execute(context.Background(), "up", nil)
+	if err != nil || result.Message != "infra.start" {
+		t.Fatalf("Execute(up) = %+v, %v", result, err)
+	}
+	if seen != "infra.start" {
+		t.Fatalf("middleware saw %q, want the canonical name", seen)
+	}
+
+	if names := r.Names(); len(names) != 1 || names[0] != "infra.start" {
+		t.Fatalf("Names() = %v, want only the canonical name", names)
+	}
+}
+
+func TestRegistryAliasCollisions(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *Command
+		want string
+	}{
+		{"alias of another command", namedCommand("db.migrate", "up"), "already an alias of infra.start"},
+		{"alias is a command name", namedCommand("db.migrate", "infra.stop"), "already a command name"},
+		{"name is an alias", namedCommand("up"), "already an alias of infra.start"},
+		{"alias repeats the name", namedCommand("db.migrate", "db.migrate"), "duplicate alias"},
+		{"alias listed twice", namedCommand("db.migrate", "migrate", "migrate"), "duplicate alias"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRegistry()
+			r.MustRegister(namedCommand("infra.start", "up"))
+			r.MustRegister(namedCommand("infra.stop", "down"))
+
+			err := r.Register(tt.cmd)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Register = %v, want error containing %q", err, tt.want)
+			}
+			// A failed registration leaves the registry untouched
+			if r.Count() != 2 {
+				t.Fatalf("Count() = %d after a failed Register, want 2", r.Count())
+			}
+			if cmd, _ := r.Get("up"); cmd.Name != "infra.start" {
+				t.Fatalf("alias up now resolves to %s", cmd.Name)
+			}
+		})
+	}
+}
+
+func TestRegistryReplaceDropsOldAliases(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(namedCommand("infra.start", "up", "start"))
+	if err := r.Register(namedCommand("infra.start", "up")); err != nil {
+		t.Fatalf("re-Register: %v", err)
+	}
+	if _, ok := r.Get("start"); ok {
+		t.Fatal("alias start survived re-registration without it")
+	}
+	if err := r.Register(namedCommand("infra.begin", "start")); err != nil {
+		t.Fatalf("Register of the freed alias: %v", err)
+	}
+}
+
+func TestMustRegisterPanicsOnCollision(t *testing.T) {
+	r := NewRegistry()
+	r.MustRegister(namedCommand("infra.start", "up"))
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustRegister did not panic on a colliding alias")
+		}
+	}()
+	r.MustRegister(namedCommand("db.migrate", "up"))
+}
@@ -1,10 +1,14 @@
 package auth
 
 import (
+	"crypto/rsa"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
 )
 
 // TokenClaims holds JWT token claims
@@ -15,10 +19,43 @@ type TokenClaims struct {
 	ExpMinutes int
 }
 
+// SigningMethod names a supported JWT signing algorithm
+type SigningMethod string
+
+const (
+	// SigningMethodHS256 signs and verifies with one shared secret
+	SigningMethodHS256 SigningMethod = "HS256"
+
+	// SigningMethodRS256 signs with an RSA private key; anyone holding the
+	// public key (for example from a JWKS endpoint) can verify
+	SigningMethodRS256 SigningMethod = "RS256"
+)
+
 // GenerateToken generates a JWT token with the given claims and secret
 func GenerateToken(claims TokenClaims, secret string) (string, error) {
-	now := time.Now()
+	return SignToken(claims, SigningMethodHS256, []byte(secret))
+}
+
+// GenerateTokenRS256 generates a JWT token signed with an RSA private key
+func GenerateTokenRS256(claims TokenClaims, privateKey *rsa.PrivateKey) (string, error) {
+	return SignToken(claims, SigningMethodRS256, privateKey)
+}
+
+// ParseAndVerifyRS256 verifies an RS256 token against publicKey and returns
+// its claims. Tokens signed with any other algorithm are rejected.
+func ParseAndVerifyRS256(token string, publicKey *rsa.PublicKey, opts ...ParseOptions) (TokenClaims, error) {
+	return VerifyToken(token, SigningMethodRS256, publicKey, opts...)
+}
+
+// SignToken signs claims with method. key is the secret ([]byte) for HS256
+// or the *rsa.PrivateKey for RS256.
+func SignToken(claims TokenClaims, method SigningMethod, key any) (string, error) {
+	signer, err := jwtMethod(method)
+	if err != nil {
+		return "", err
+	}
 
+	now := time.Now()
 	jwtClaims := jwt.MapClaims{
 		"sub": claims.Subject,
 		"iss": claims.Issuer,
@@ -27,11 +64,126 @@ func GenerateToken(claims TokenClaims, secret string) (string, error) {
 		"exp": now.Add(time.Duration(claims.ExpMinutes) * time.Minute).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
-	signed, err := token.SignedString([]byte(secret))
+	token := jwt.NewWithClaims(signer, jwtClaims)
+	signed, err := token.SignedString(key)
 	if err != nil {
 		return "", fmt.Errorf("sign token: %w", err)
 	}
-
 	return signed, nil
 }
+
+// ParseOptions are the optional checks ParseToken and VerifyToken apply
+// beyond the signature, exp and nbf
+type ParseOptions struct {
+	Issuer   string        // Required iss claim; empty accepts any issuer
+	Audience string        // Required aud entry; empty accepts any audience
+	Leeway   time.Duration // Clock skew tolerated on exp and nbf
+}
+
+// ParseToken verifies an HS256 token signed with secret and returns its
+// claims. At most one ParseOptions may be given. Failures are AppErrors
+// coded ErrCodeTokenMissing, ErrCodeTokenExpired or ErrCodeInvalidToken.
+func ParseToken(token, secret string, opts ...ParseOptions) (TokenClaims, error) {
+	return VerifyToken(token, SigningMethodHS256, []byte(secret), opts...)
+}
+
+// VerifyToken checks token's signature, exp and nbf (plus any ParseOptions)
+// and returns its claims. Only method is accepted, so an RS256 verifier
+// cannot be fooled by an HS256 token signed with the public key. key is the
+// secret ([]byte) for HS256 or the *rsa.PublicKey for RS256.
+func VerifyToken(token string, method SigningMethod, key any, opts ...ParseOptions) (TokenClaims, error) {
+	if _, err := jwtMethod(method); err != nil {
+		return TokenClaims{}, err
+	}
+	if token == "" {
+		return TokenClaims{}, apperrors.TokenMissing()
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{string(method)}),
+		jwt.WithExpirationRequired(),
+	}
+	for _, o := range opts {
+		if o.Issuer != "" {
+			parserOpts = append(parserOpts, jwt.WithIssuer(o.Issuer))
+		}
+		if o.Audience != "" {
+			parserOpts = append(parserOpts, jwt.WithAudience(o.Audience))
+		}
+		if o.Leeway > 0 {
+			parserOpts = append(parserOpts, jwt.WithLeeway(o.Leeway))
+		}
+	}
+
+	parsed, err := jwt.Parse(token, func(*jwt.Token) (any, error) { return key, nil }, parserOpts...)
+	if err != nil {
+		return TokenClaims{}, tokenError(err)
+	}
+	claims, err := tokenClaims(parsed.Claims.(jwt.MapClaims))
+	if err != nil {
+		return TokenClaims{}, apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "invalid token claims")
+	}
+	return claims, nil
+}
+
+// tokenError maps a jwt validation error to an AppError
+func tokenError(err error) *apperrors.AppError {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return apperrors.Wrap(err, apperrors.ErrCodeTokenExpired, "token expired")
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "token not valid yet")
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "unexpected token issuer")
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "unexpected token audience")
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "invalid token signature")
+	default:
+		return apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "invalid token")
+	}
+}
+
+func jwtMethod(method SigningMethod) (jwt.SigningMethod, error) {
+	switch method {
+	case SigningMethodHS256:
+		return jwt.SigningMethodHS256, nil
+	case SigningMethodRS256:
+		return jwt.SigningMethodRS256, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing method %q", method)
+	}
+}
+
+// tokenClaims converts verified registered claims back to TokenClaims.
+// ExpMinutes is the token's lifetime, from iat to exp.
+func tokenClaims(mc jwt.MapClaims) (TokenClaims, error) {
+	var claims TokenClaims
+	var err error
+	if claims.Subject, err = mc.GetSubject(); err != nil {
+		return TokenClaims{}, fmt.Errorf("read sub claim: %w", err)
+	}
+	if claims.Issuer, err = mc.GetIssuer(); err != nil {
+		return TokenClaims{}, fmt.Errorf("read iss claim: %w", err)
+	}
+	aud, err := mc.GetAudience()
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("read aud claim: %w", err)
+	}
+	if len(aud) > 0 {
+		claims.Audience = aud[0]
+	}
+
+	exp, err := mc.GetExpirationTime()
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("read exp claim: %w", err)
+	}
+	iat, err := mc.GetIssuedAt()
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("read iat claim: %w", err)
+	}
+	if exp != nil && iat != nil {
+		claims.ExpMinutes = int(exp.Sub(iat.Time) / time.Minute)
+	}
+	return claims, nil
+}
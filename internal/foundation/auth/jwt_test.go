@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	return key
+}
+
+func TestRS256RoundTrip(t *testing.T) {
+	key := generateKey(t)
+	want := TokenClaims{Subject: "user-1", Issuer: "air", Audience: "air-app", ExpMinutes: 15}
+
+	token, err := GenerateTokenRS256(want, key)
+	if err != nil {
+		t.Fatalf("GenerateTokenRS256: %v", err)
+	}
+	got, err := ParseAndVerifyRS256(token, &key.PublicKey)
+	if err != nil {
+		t.Fatalf("ParseAndVerifyRS256: %v", err)
+	}
+	if got != want {
+		t.Fatalf("claims = %+v, want %+v", got, want)
+	}
+}
+
+func TestRS256RejectsTampering(t *testing.T) {
+	key := generateKey(t)
+	token, err := GenerateTokenRS256(TokenClaims{Subject: "user-1", ExpMinutes: 5}, key)
+	if err != nil {
+		t.Fatalf("GenerateTokenRS256: %v", err)
+	}
+
+	// Swap the payload for one claiming another subject, keeping the signature
+	parts := strings.Split(token, ".")
+	forged, err := GenerateTokenRS256(TokenClaims{Subject: "admin", ExpMinutes: 5}, key)
+	if err != nil {
+		t.Fatalf("GenerateTokenRS256: %v", err)
+	}
+	parts[1] = strings.Split(forged, ".")[1]
+	if _, err := ParseAndVerifyRS256(strings.Join(parts, "."), &key.PublicKey); err == nil {
+		t.Fatalf("expected a tampered payload to fail verification")
+	}
+
+	if _, err := ParseAndVerifyRS256(token, &generateKey(t).PublicKey); err == nil {
+		t.Fatalf("expected verification with another public key to fail")
+	}
+}
+
+func TestRS256RejectsOtherMethods(t *testing.T) {
+	key := generateKey(t)
+
+	// An HS256 token keyed with the public key must not pass as RS256
+	pub := base64.StdEncoding.EncodeToString(key.PublicKey.N.Bytes())
+	hs, err := GenerateToken(TokenClaims{Subject: "user-1", ExpMinutes: 5}, pub)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := ParseAndVerifyRS256(hs, &key.PublicKey); err == nil || !strings.Contains(err.Error(), "signing method") {
+		t.Fatalf("expected signing method error, got %v", err)
+	}
+
+	if _, err := SignToken(TokenClaims{}, "none", nil); err == nil {
+		t.Fatalf("expected unsupported method error")
+	}
+}
+
+func TestVerifyTokenHS256(t *testing.T) {
+	token, err := GenerateToken(TokenClaims{Subject: "user-1", ExpMinutes: 5}, "secret")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if claims, err := VerifyToken(token, SigningMethodHS256, []byte("secret")); err != nil || claims.Subject != "user-1" {
+		t.Fatalf("VerifyToken = %+v, %v", claims, err)
+	}
+	if _, err := VerifyToken(token, SigningMethodHS256, []byte("other")); err == nil {
+		t.Fatalf("expected wrong secret to fail verification")
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	claims := TokenClaims{Subject: "user-1", Issuer: "skill-flow", Audience: "skill-flow-app", ExpMinutes: 10}
+	valid, err := GenerateToken(claims, "secret")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	expired, err := GenerateToken(TokenClaims{Subject: "user-1", ExpMinutes: -5}, "secret")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	notYet, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "user-1",
+		"nbf": time.Now().Add(time.Hour).Unix(),
+		"exp": time.Now().Add(2 * time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign nbf token: %v", err)
+	}
+
+	opts := ParseOptions{Issuer: "skill-flow", Audience: "skill-flow-app"}
+	got, err := ParseToken(valid, "secret", opts)
+	if err != nil {
+		t.Fatalf("ParseToken: %v", err)
+	}
+	if got != claims {
+		t.Fatalf("claims = %+v, want %+v", got, claims)
+	}
+
+	cases := []struct {
+		name   string
+		token  string
+		secret string
+		opts   ParseOptions
+		code   string
+	}{
+		{"expired", expired, "secret", ParseOptions{}, apperrors.ErrCodeTokenExpired},
+		{"not yet valid", notYet, "secret", ParseOptions{}, apperrors.ErrCodeInvalidToken},
+		{"wrong issuer", valid, "secret", ParseOptions{Issuer: "someone-else"}, apperrors.ErrCodeInvalidToken},
+		{"wrong audience", valid, "secret", ParseOptions{Audience: "other-app"}, apperrors.ErrCodeInvalidToken},
+		{"bad signature", valid, "wrong-secret", opts, apperrors.ErrCodeInvalidToken},
+		{"malformed", "not.a.token", "secret", opts, apperrors.ErrCodeInvalidToken},
+		{"missing", "", "secret", opts, apperrors.ErrCodeTokenMissing},
+	}
+	for _, tc := range cases {
+		_, err := ParseToken(tc.token, tc.secret, tc.opts)
+		if code := apperrors.Code(err); code != tc.code {
+			t.Fatalf("%s: code = %q (err %v), want %q", tc.name, code, err, tc.code)
+		}
+	}
+
+	// Expiry within the leeway still passes
+	if _, err := ParseToken(expired, "secret", ParseOptions{Leeway: 10 * time.Minute}); err != nil {
+		t.Fatalf("expected leeway to accept a recently expired token, got %v", err)
+	}
+}
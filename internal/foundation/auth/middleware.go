@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+)
+
+// claimsKey is the context key Middleware stores verified claims under
+type claimsKey struct{}
+
+// Middleware requires a valid HS256 Bearer token (see ParseToken) on every
+// request. Verified claims are available to the handler through
+// ClaimsFromContext, and the subject becomes the context's user ID (and the
+// span's user.id). A missing or invalid token gets a 401 with the AppError
+// as the JSON body.
+func Middleware(secret string, opts ParseOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := ParseToken(bearerToken(r), secret, opts)
+			if err != nil {
+				writeUnauthorized(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+			ctx = telemetry.EnrichContext(ctx, claims.Subject, telemetry.GetSessionID(ctx), telemetry.GetRequestID(ctx))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClaimsFromContext returns the claims Middleware verified for this request
+func ClaimsFromContext(ctx context.Context) (TokenClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(TokenClaims)
+	return claims, ok
+}
+
+// bearerToken returns the token from an "Authorization: Bearer" header, or
+// "" when there is none
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// writeUnauthorized sends err as a 401 JSON AppError tagged with the
+// request's ID. A request without credentials gets a bare Bearer challenge;
+// only a rejected token is flagged invalid_token (RFC 6750 section 3.1).
+func writeUnauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr *apperrors.AppError
+	if !errors.As(err, &appErr) {
+		appErr = apperrors.Wrap(err, apperrors.ErrCodeInvalidToken, "invalid token")
+	}
+	if requestID := telemetry.GetRequestID(r.Context()); requestID != "" && appErr.RequestID == "" {
+		appErr.WithRequestID(requestID)
+	}
+	telemetry.LogWarn(r.Context(), "authentication failed", attribute.String("error.code", appErr.Code))
+
+	w.Header().Set("Content-Type", "application/json")
+	challenge := `Bearer error="invalid_token"`
+	if appErr.Code == apperrors.ErrCodeTokenMissing {
+		challenge = "Bearer"
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(w).Encode(appErr)
+}
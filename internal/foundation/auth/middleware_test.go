@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+)
+
+func TestMiddleware(t *testing.T) {
+	opts := ParseOptions{Issuer: "skill-flow", Audience: "skill-flow-app"}
+	var gotClaims TokenClaims
+	var gotUser string
+	handler := Middleware("secret", opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFromContext(r.Context())
+		if !ok {
+			t.Errorf("expected claims in the request context")
+		}
+		gotClaims, gotUser = claims, telemetry.GetUserID(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/questions", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	claims := TokenClaims{Subject: "user-1", Issuer: "skill-flow", Audience: "skill-flow-app", ExpMinutes: 10}
+	valid, err := GenerateToken(claims, "secret")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if rec := serve("Bearer " + valid); rec.Code != http.StatusNoContent {
+		t.Fatalf("valid token: status %d, body %s", rec.Code, rec.Body)
+	}
+	if gotClaims != claims || gotUser != "user-1" {
+		t.Fatalf("handler saw claims %+v and user %q", gotClaims, gotUser)
+	}
+
+	expired, err := GenerateToken(TokenClaims{Subject: "user-1", Issuer: "skill-flow", Audience: "skill-flow-app", ExpMinutes: -1}, "secret")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	cases := []struct {
+		name          string
+		authorization string
+		code          string
+		challenge     string
+	}{
+		{"missing header", "", apperrors.ErrCodeTokenMissing, "Bearer"},
+		{"other scheme", "Basic dXNlcjpwYXNz", apperrors.ErrCodeTokenMissing, "Bearer"},
+		{"expired", "Bearer " + expired, apperrors.ErrCodeTokenExpired, `Bearer error="invalid_token"`},
+		{"bad signature", "Bearer " + valid + "x", apperrors.ErrCodeInvalidToken, `Bearer error="invalid_token"`},
+	}
+	for _, tc := range cases {
+		rec := serve(tc.authorization)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: status %d, want 401", tc.name, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("%s: Content-Type %q", tc.name, ct)
+		}
+		if challenge := rec.Header().Get("WWW-Authenticate"); challenge != tc.challenge {
+			t.Fatalf("%s: WWW-Authenticate %q, want %q", tc.name, challenge, tc.challenge)
+		}
+		appErr, err := apperrors.FromJSON(rec.Body.Bytes())
+		if err != nil {
+			t.Fatalf("%s: decode body %q: %v", tc.name, rec.Body, err)
+		}
+		if appErr.Code != tc.code {
+			t.Fatalf("%s: code %q, want %q", tc.name, appErr.Code, tc.code)
+		}
+	}
+}
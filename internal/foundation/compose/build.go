@@ -0,0 +1,118 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/moby/go-archive"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+// ============================================================================
+// IMAGE PREPARATION
+// ============================================================================
+
+// ensureImage makes the service image available locally and returns the
+// reference to create the container from. Services with a build section are
+// built from their context; all others are pulled when missing.
+func (s *Service) ensureImage(ctx context.Context, svc composetypes.ServiceConfig) (string, error) {
+	if svc.Build != nil {
+		return s.buildImage(ctx, svc)
+	}
+
+	if _, _, err := s.cli.ImageInspectWithRaw(ctx, svc.Image); err != nil {
+		reader, err := s.cli.ImagePull(ctx, svc.Image, image.PullOptions{})
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %w", ErrImagePull, svc.Image, err)
+		}
+		defer reader.Close()
+		io.Copy(io.Discard, reader) // Consume pull output
+	}
+	return svc.Image, nil
+}
+
+// buildImage builds a service image from its compose build section and
+// returns the tag it was built under
+func (s *Service) buildImage(ctx context.Context, svc composetypes.ServiceConfig) (string, error) {
+	tag := buildTag(s.projectName, svc)
+	contextDir := buildContextDir(s.project.WorkingDir, svc.Build)
+
+	buildContext, err := tarBuildContext(contextDir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrImageBuild, svc.Name, err)
+	}
+	defer buildContext.Close()
+
+	resp, err := s.cli.ImageBuild(ctx, buildContext, build.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  svc.Build.Dockerfile,
+		BuildArgs:   map[string]*string(svc.Build.Args),
+		Target:      svc.Build.Target,
+		Labels:      svc.Build.Labels,
+		NoCache:     svc.Build.NoCache,
+		PullParent:  svc.Build.Pull,
+		NetworkMode: svc.Build.Network,
+		Remove:      true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrImageBuild, svc.Name, err)
+	}
+	defer resp.Body.Close()
+
+	// The daemon reports build failures inside the progress stream
+	if err := jsonmessage.DisplayJSONMessagesStream(resp.Body, io.Discard, 0, false, nil); err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrImageBuild, svc.Name, err)
+	}
+	return tag, nil
+}
+
+// buildTag returns the tag for a built image: the service's image name when
+// set, otherwise "<project>-<service>" as docker compose does
+func buildTag(projectName string, svc composetypes.ServiceConfig) string {
+	if svc.Image != "" {
+		return svc.Image
+	}
+	return fmt.Sprintf("%s-%s", projectName, svc.Name)
+}
+
+// buildContextDir resolves the build context against the project directory
+func buildContextDir(workingDir string, cfg *composetypes.BuildConfig) string {
+	dir := cfg.Context
+	if dir == "" {
+		dir = "."
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(workingDir, dir)
+	}
+	return dir
+}
+
+// tarBuildContext archives dir for the build API, honouring .dockerignore
+func tarBuildContext(dir string) (io.ReadCloser, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("build context: %w", err)
+	}
+
+	var excludes []string
+	f, err := os.Open(filepath.Join(dir, ".dockerignore"))
+	switch {
+	case err == nil:
+		excludes, err = ignorefile.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read .dockerignore: %w", err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("open .dockerignore: %w", err)
+	}
+
+	return archive.TarWithOptions(dir, &archive.TarOptions{ExcludePatterns: excludes})
+}
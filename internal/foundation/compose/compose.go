@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,10 +15,10 @@ import (
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
-	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -42,6 +44,9 @@ const (
 	// healthCheckPollInterval is the interval for polling service health
 	healthCheckPollInterval = 2 * time.Second
 
+	// dependencyHealthyTimeout bounds the wait for a service_healthy dependency
+	dependencyHealthyTimeout = 2 * time.Minute
+
 	// dockerPingTimeout is the timeout for Docker daemon ping
 	dockerPingTimeout = 5 * time.Second
 
@@ -89,13 +94,17 @@ type Service struct {
 	cli         *client.Client
 	project     *composetypes.Project
 	projectName string
-	networkIDs  map[string]string // network name -> network ID
-	volumeNames []string          // list of created volumes
+	networkIDs  map[string]string   // network name -> network ID
+	volumeNames map[string]struct{} // set of created or reused volumes
+
+	randomizePorts bool              // publish ports on Docker-assigned host ports
+	imageDigests   map[string]string // service name -> expected registry digest
 }
 
 // ServiceStatus represents the status of compose services
 type ServiceStatus struct {
 	Services map[string]ServiceInfo `json:"services"`
+	Missing  []string               `json:"missing,omitempty"` // Project services without a container, sorted
 }
 
 // ServiceInfo contains info about a single service
@@ -108,11 +117,70 @@ type ServiceInfo struct {
 	ContainerID string   `json:"container_id"`
 }
 
+// Ready reports whether every project service has a container and all of them are healthy.
+func (s *ServiceStatus) Ready() bool {
+	return len(s.Services) > 0 && len(s.Missing) == 0 && s.Healthy()
+}
+
+// Healthy reports whether every service is running and, if it has a healthcheck, healthy.
+func (s *ServiceStatus) Healthy() bool {
+	for _, svc := range s.Services {
+		// Container must be running
+		if svc.State != "running" {
+			return false
+		}
+
+		// If container has a healthcheck, it must be healthy
+		// Health values: "healthy", "unhealthy", "starting", "none"
+		if svc.Health != "none" && svc.Health != "healthy" {
+			return false
+		}
+	}
+	return true
+}
+
+// HostPort returns the host port a service's container port is published on,
+// as read from its port mappings (so it reflects Docker-assigned ports).
+func (s *ServiceStatus) HostPort(service string, containerPort int) (int, error) {
+	info, ok := s.Services[service]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s has no container", ErrServiceNotFound, service)
+	}
+	for _, mapping := range info.Ports {
+		// "<ip>:<public>-><private>/<proto>"; the IP may itself contain colons
+		host, target, ok := strings.Cut(mapping, "->")
+		if !ok {
+			continue
+		}
+		private, _, _ := strings.Cut(target, "/")
+		if private != strconv.Itoa(containerPort) {
+			continue
+		}
+		public, err := strconv.Atoi(host[strings.LastIndex(host, ":")+1:])
+		if err != nil {
+			return 0, fmt.Errorf("parse port mapping %q: %w", mapping, err)
+		}
+		return public, nil
+	}
+	return 0, fmt.Errorf("service %s does not publish port %d", service, containerPort)
+}
+
 // Config holds configuration for compose operations
 type Config struct {
 	ComposeFilePath string            // Path to docker-compose.yml
 	ProjectName     string            // Docker Compose project name
 	Env             map[string]string // Environment variables
+
+	// RandomizePorts publishes every service port on a Docker-assigned host
+	// port instead of the one in the compose file, so parallel projects do
+	// not collide; read the actual ports back with ServiceStatus.HostPort.
+	RandomizePorts bool
+
+	// ImageDigests maps service names to the registry digest
+	// ("sha256:...") their image must resolve to, for references that are
+	// only tagged. A digest pinned in the compose file ("image@sha256:...")
+	// takes precedence. See VerifyImageDigests.
+	ImageDigests map[string]string
 }
 
 // New creates a new compose service manager using Docker SDK
@@ -137,36 +205,77 @@ func New(cfg Config) (*Service, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), dockerPingTimeout)
 	defer cancel()
 
-	if _, err := cli.Ping(ctx); err != nil {
+	if err := ping(ctx, cli); err != nil {
 		cli.Close()
-		return nil, fmt.Errorf("docker daemon not reachable (is Docker Desktop running?): %w", err)
+		return nil, err
+	}
+
+	project, err := loadProject(context.Background(), absPath, cfg)
+	if err != nil {
+		cli.Close()
+		return nil, err
+	}
+
+	return &Service{
+		cli:         cli,
+		project:     project,
+		projectName: cfg.ProjectName,
+		networkIDs:  make(map[string]string),
+		volumeNames: make(map[string]struct{}),
+
+		randomizePorts: cfg.RandomizePorts,
+		imageDigests:   cfg.ImageDigests,
+	}, nil
+}
+
+// PingDocker checks that the Docker daemon configured by the environment is reachable
+func PingDocker(ctx context.Context) error {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("create docker client: %w", err)
 	}
+	defer cli.Close()
 
-	// Load compose file using compose-spec
-	configFiles := []composetypes.ConfigFile{
-		{Filename: absPath},
+	ctx, cancel := context.WithTimeout(ctx, dockerPingTimeout)
+	defer cancel()
+	return ping(ctx, cli)
+}
+
+// ValidateFile loads and validates a compose file without contacting Docker
+func ValidateFile(ctx context.Context, cfg Config) error {
+	absPath, err := filepath.Abs(cfg.ComposeFilePath)
+	if err != nil {
+		return fmt.Errorf("resolve compose file path: %w", err)
 	}
+	if _, err := os.Stat(absPath); err != nil {
+		return fmt.Errorf("compose file: %w", err)
+	}
+	_, err = loadProject(ctx, absPath, cfg)
+	return err
+}
+
+func ping(ctx context.Context, cli *client.Client) error {
+	if _, err := cli.Ping(ctx); err != nil {
+		return fmt.Errorf("%w (is Docker Desktop running?): %w", ErrDockerUnavailable, err)
+	}
+	return nil
+}
 
+// loadProject parses the compose file using compose-spec
+func loadProject(ctx context.Context, absPath string, cfg Config) (*composetypes.Project, error) {
 	configDetails := composetypes.ConfigDetails{
-		ConfigFiles: configFiles,
+		ConfigFiles: []composetypes.ConfigFile{{Filename: absPath}},
 		WorkingDir:  filepath.Dir(absPath),
 		Environment: cfg.Env,
 	}
 
-	project, err := loader.LoadWithContext(context.Background(), configDetails, func(options *loader.Options) {
+	project, err := loader.LoadWithContext(ctx, configDetails, func(options *loader.Options) {
 		options.SetProjectName(cfg.ProjectName, true)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("load compose file: %w", err)
 	}
-
-	return &Service{
-		cli:         cli,
-		project:     project,
-		projectName: cfg.ProjectName,
-		networkIDs:  make(map[string]string),
-		volumeNames: make([]string, 0),
-	}, nil
+	return project, nil
 }
 
 // ============================================================================
@@ -187,6 +296,101 @@ func (s *Service) Start(ctx context.Context) error {
 	}
 	defer cleanup()
 
+	// 1-2. Create networks and volumes
+	if err := s.createResources(ctx); err != nil {
+		startErr = err
+		return startErr
+	}
+
+	// 3. Start services in dependency order
+	orderedServices, err := s.sortServicesByDependency()
+	if err != nil {
+		startErr = err
+		return startErr
+	}
+	if err := startInOrder(ctx, orderedServices, s.startService, s.waitServiceHealthy); err != nil {
+		startErr = err
+		return startErr
+	}
+
+	return nil
+}
+
+// startInOrder starts services in the given (dependency) order. Before a
+// service starts, every depends_on entry with condition service_healthy is
+// waited on, so dependents never race a dependency that is still booting.
+func startInOrder(
+	ctx context.Context,
+	ordered []composetypes.ServiceConfig,
+	start func(context.Context, composetypes.ServiceConfig) error,
+	waitHealthy func(ctx context.Context, name string) error,
+) error {
+	for _, svc := range ordered {
+		deps := make([]string, 0, len(svc.DependsOn))
+		for dep, cfg := range svc.DependsOn {
+			if cfg.Condition == composetypes.ServiceConditionHealthy {
+				deps = append(deps, dep)
+			}
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if err := waitHealthy(ctx, dep); err != nil {
+				return fmt.Errorf("start service %s: dependency %s: %w", svc.Name, dep, err)
+			}
+		}
+
+		if err := start(ctx, svc); err != nil {
+			return fmt.Errorf("start service %s: %w", svc.Name, err)
+		}
+	}
+	return nil
+}
+
+// waitServiceHealthy polls a service's container until Docker reports it
+// healthy, failing if it turns unhealthy, has no healthcheck or
+// dependencyHealthyTimeout elapses.
+func (s *Service) waitServiceHealthy(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, dependencyHealthyTimeout)
+	defer cancel()
+
+	for {
+		containers, err := s.cli.ContainerList(ctx, container.ListOptions{
+			Filters: filters.NewArgs(
+				filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", s.projectName)),
+				filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", name)),
+			),
+		})
+		if err != nil {
+			return fmt.Errorf("list containers: %w", err)
+		}
+		if len(containers) > 0 {
+			inspect, err := s.cli.ContainerInspect(ctx, containers[0].ID)
+			if err != nil {
+				return fmt.Errorf("inspect container: %w", err)
+			}
+			if inspect.State == nil || inspect.State.Health == nil {
+				return fmt.Errorf("service %s has no healthcheck", name)
+			}
+			switch inspect.State.Health.Status {
+			case "healthy":
+				return nil
+			case "unhealthy":
+				return fmt.Errorf("service %s is unhealthy", name)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s to become healthy: %w", name, ctx.Err())
+		case <-time.After(healthCheckPollInterval):
+		}
+	}
+}
+
+// createResources creates the project's networks and volumes, reusing existing
+// ones. Both are tracked by name, so repeated calls (one per StartService)
+// record each resource once.
+func (s *Service) createResources(ctx context.Context) error {
 	// 1. Create networks
 	for netName, netConfig := range s.project.Networks {
 		fullName := fmt.Sprintf("%s_%s", s.projectName, netName)
@@ -196,8 +400,7 @@ func (s *Service) Start(ctx context.Context) error {
 			Filters: filters.NewArgs(filters.Arg("name", fmt.Sprintf("^%s$", fullName))),
 		})
 		if err != nil {
-			startErr = fmt.Errorf("list networks: %w", err)
-			return startErr
+			return fmt.Errorf("list networks: %w", err)
 		}
 
 		// Double-check exact name match (Docker filter may still do substring match)
@@ -230,8 +433,7 @@ func (s *Service) Start(ctx context.Context) error {
 			}
 			resp, err := s.cli.NetworkCreate(ctx, fullName, opts)
 			if err != nil {
-				startErr = fmt.Errorf("create network %s: %w", netName, err)
-				return startErr
+				return fmt.Errorf("create network %s: %w", netName, err)
 			}
 			netID = resp.ID
 		}
@@ -247,8 +449,7 @@ func (s *Service) Start(ctx context.Context) error {
 			Filters: filters.NewArgs(filters.Arg("name", fmt.Sprintf("^%s$", fullName))),
 		})
 		if err != nil {
-			startErr = fmt.Errorf("list volumes: %w", err)
-			return startErr
+			return fmt.Errorf("list volumes: %w", err)
 		}
 
 		// Double-check exact name match
@@ -275,27 +476,66 @@ func (s *Service) Start(ctx context.Context) error {
 				Labels: labels,
 			})
 			if err != nil {
-				startErr = fmt.Errorf("create volume %s: %w", volName, err)
-				return startErr
+				return fmt.Errorf("create volume %s: %w", volName, err)
 			}
 		}
-		s.volumeNames = append(s.volumeNames, fullName)
+		s.volumeNames[fullName] = struct{}{}
 	}
 
-	// 3. Start services in dependency order
-	orderedServices := s.sortServicesByDependency()
-	for _, svc := range orderedServices {
-		if err := s.startService(ctx, svc); err != nil {
-			startErr = fmt.Errorf("start service %s: %w", svc.Name, err)
-			return startErr
+	return nil
+}
+
+// StartService brings up a single service, starting any of its depends_on
+// services that are not already running first. Other services are untouched.
+func (s *Service) StartService(ctx context.Context, name string) error {
+	ordered, err := s.serviceWithDependencies(name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.createResources(ctx); err != nil {
+		return err
+	}
+
+	// startService leaves running containers alone
+	return startInOrder(ctx, ordered, s.startService, s.waitServiceHealthy)
+}
+
+// serviceWithDependencies returns name and its transitive dependencies in start order
+func (s *Service) serviceWithDependencies(name string) ([]composetypes.ServiceConfig, error) {
+	if _, ok := s.project.Services[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	needed := make(map[string]bool)
+	var visit func(string)
+	visit = func(n string) {
+		if needed[n] {
+			return
+		}
+		needed[n] = true
+		for dep := range s.project.Services[n].DependsOn {
+			visit(dep)
 		}
 	}
+	visit(name)
 
-	return nil
+	ordered, err := s.sortServicesByDependency()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]composetypes.ServiceConfig, 0, len(needed))
+	for _, svc := range ordered {
+		if needed[svc.Name] {
+			result = append(result, svc)
+		}
+	}
+	return result, nil
 }
 
-// sortServicesByDependency returns services sorted so dependencies start first
-func (s *Service) sortServicesByDependency() []composetypes.ServiceConfig {
+// sortServicesByDependency returns services sorted so dependencies start first.
+// It returns ErrCircularDependency if the dependency graph contains a cycle.
+func (s *Service) sortServicesByDependency() ([]composetypes.ServiceConfig, error) {
 	// Build dependency graph
 	services := make(map[string]composetypes.ServiceConfig)
 	for name, svc := range s.project.Services {
@@ -339,29 +579,36 @@ func (s *Service) sortServicesByDependency() []composetypes.ServiceConfig {
 			}
 		}
 
-		// If no progress was made, there might be a circular dependency
-		// Add remaining services anyway to avoid infinite loop
+		// If no progress was made, the remaining services form a cycle
 		if !progress {
-			for name, svc := range services {
+			remaining := make([]string, 0)
+			for name := range services {
 				if !added[name] {
-					result = append(result, svc)
-					added[name] = true
+					remaining = append(remaining, name)
 				}
 			}
-			break
+			sort.Strings(remaining)
+			return nil, fmt.Errorf("%w: %s", ErrCircularDependency, strings.Join(remaining, ", "))
 		}
 	}
 
-	return result
+	return result, nil
+}
+
+// containerName is the name svc's container is created under: its
+// container_name, or <project>-<service>-1 as docker compose names it. With
+// RandomizePorts container_name is ignored, since a fixed name collides
+// between parallel projects just like a fixed host port.
+func (s *Service) containerName(svc composetypes.ServiceConfig) string {
+	if svc.ContainerName != "" && !s.randomizePorts {
+		return svc.ContainerName
+	}
+	return fmt.Sprintf("%s-%s-1", s.projectName, svc.Name)
 }
 
 // startService starts a single service container
 func (s *Service) startService(ctx context.Context, svc composetypes.ServiceConfig) error {
-	// Use container_name from compose file if specified, otherwise use default naming
-	containerName := svc.ContainerName
-	if containerName == "" {
-		containerName = fmt.Sprintf("%s-%s-1", s.projectName, svc.Name)
-	}
+	containerName := s.containerName(svc)
 
 	// Check if container already exists
 	containers, err := s.cli.ContainerList(ctx, container.ListOptions{
@@ -378,27 +625,27 @@ func (s *Service) startService(ctx context.Context, svc composetypes.ServiceConf
 		// Start if stopped
 		if containers[0].State != "running" {
 			if err := s.cli.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+				if isPortConflict(err) {
+					return fmt.Errorf("start existing container: %w: %w", ErrPortInUse, err)
+				}
 				return fmt.Errorf("start existing container: %w", err)
 			}
 		}
 		return nil
 	}
 
-	// Pull image if needed
-	if _, _, err := s.cli.ImageInspectWithRaw(ctx, svc.Image); err != nil {
-		reader, err := s.cli.ImagePull(ctx, svc.Image, image.PullOptions{})
-		if err != nil {
-			return fmt.Errorf("pull image %s: %w", svc.Image, err)
-		}
-		defer reader.Close()
-		io.Copy(io.Discard, reader) // Consume pull output
+	// Pull or build the image
+	imageRef, err := s.ensureImage(ctx, svc)
+	if err != nil {
+		return err
 	}
 
 	// Build container config
 	containerConfig := &container.Config{
-		Image:  svc.Image,
-		Env:    buildEnvList(svc.Environment),
-		Labels: svc.Labels,
+		Image:       imageRef,
+		Env:         buildEnvList(svc.Environment),
+		Labels:      svc.Labels,
+		Healthcheck: buildHealthcheck(svc.HealthCheck),
 	}
 
 	// Add command if specified
@@ -421,10 +668,14 @@ func (s *Service) startService(ctx context.Context, svc composetypes.ServiceConf
 		exposedPorts[containerPort] = struct{}{}
 
 		if port.Published != "" {
+			hostPort := port.Published
+			if s.randomizePorts {
+				hostPort = "" // Docker assigns a free port
+			}
 			portBindings[containerPort] = []nat.PortBinding{
 				{
 					HostIP:   "0.0.0.0",
-					HostPort: port.Published,
+					HostPort: hostPort,
 				},
 			}
 		}
@@ -476,6 +727,9 @@ func (s *Service) startService(ctx context.Context, svc composetypes.ServiceConf
 	}
 
 	if err := s.cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		if isPortConflict(err) {
+			return fmt.Errorf("start container: %w: %w", ErrPortInUse, err)
+		}
 		return fmt.Errorf("start container: %w", err)
 	}
 
@@ -507,6 +761,78 @@ func (s *Service) Stop(ctx context.Context) error {
 	return nil
 }
 
+// VerifyCleanup checks that no containers, networks or volumes carrying the
+// project label remain, as after a complete Stop. Leaks are reported as a
+// *LeakError.
+func (s *Service) VerifyCleanup(ctx context.Context) error {
+	project := filters.NewArgs(
+		filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", s.projectName)),
+	)
+	leaks := &LeakError{Project: s.projectName}
+
+	containers, err := s.cli.ContainerList(ctx, container.ListOptions{All: true, Filters: project})
+	if err != nil {
+		return fmt.Errorf("list containers: %w", err)
+	}
+	for _, c := range containers {
+		name := c.ID[:12]
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		leaks.Containers = append(leaks.Containers, name)
+	}
+
+	networks, err := s.cli.NetworkList(ctx, network.ListOptions{Filters: project})
+	if err != nil {
+		return fmt.Errorf("list networks: %w", err)
+	}
+	for _, n := range networks {
+		leaks.Networks = append(leaks.Networks, n.Name)
+	}
+
+	volumes, err := s.cli.VolumeList(ctx, volume.ListOptions{Filters: project})
+	if err != nil {
+		return fmt.Errorf("list volumes: %w", err)
+	}
+	for _, v := range volumes.Volumes {
+		leaks.Volumes = append(leaks.Volumes, v.Name)
+	}
+
+	if len(leaks.Containers)+len(leaks.Networks)+len(leaks.Volumes) > 0 {
+		sort.Strings(leaks.Containers)
+		sort.Strings(leaks.Networks)
+		sort.Strings(leaks.Volumes)
+		return leaks
+	}
+	return nil
+}
+
+// StopService stops the containers of a single service, leaving them in place
+// so StartService can restart them. Networks, volumes and dependents are untouched.
+func (s *Service) StopService(ctx context.Context, name string) error {
+	if _, ok := s.project.Services[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	containers, err := s.cli.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", s.projectName)),
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", name)),
+		),
+	})
+	if err != nil {
+		return fmt.Errorf("list containers: %w", err)
+	}
+
+	timeout := containerStopTimeoutSeconds
+	for _, c := range containers {
+		if err := s.cli.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeout}); err != nil {
+			return fmt.Errorf("stop service %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // stopContainers stops and removes all project containers with retry logic
 func (s *Service) stopContainers(ctx context.Context) error {
 	listOpts := container.ListOptions{
@@ -632,7 +958,7 @@ func (s *Service) removeVolumes(ctx context.Context) error {
 	}
 
 	// Clear in-memory tracking
-	s.volumeNames = s.volumeNames[:0]
+	clear(s.volumeNames)
 
 	return lastErr
 }
@@ -694,11 +1020,70 @@ func (s *Service) Status(ctx context.Context) (*ServiceStatus, error) {
 		}
 	}
 
+	for name := range s.project.Services {
+		if _, ok := status.Services[name]; !ok {
+			status.Missing = append(status.Missing, name)
+		}
+	}
+	sort.Strings(status.Missing)
+
 	return status, nil
 }
 
-// Logs retrieves logs from a specific service
+// Logs returns the last log lines of a service's container, stdout and stderr combined
 func (s *Service) Logs(ctx context.Context, serviceName string) (string, error) {
+	reader, err := s.LogsStream(ctx, serviceName, false)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	logs, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read logs: %w", err)
+	}
+
+	return string(logs), nil
+}
+
+// LogsStream returns the last log lines of a service's container with stdout
+// and stderr demultiplexed into one reader. With follow the reader stays open
+// for new output until the container stops, ctx is cancelled or it is closed;
+// cancellation ends the stream with io.EOF.
+func (s *Service) LogsStream(ctx context.Context, serviceName string, follow bool) (io.ReadCloser, error) {
+	return s.LogsWithOptions(ctx, serviceName, LogOptions{Follow: follow})
+}
+
+// LogOptions selects which of a container's log lines to return
+type LogOptions struct {
+	Since  time.Time // Only lines written at or after Since (zero: no lower bound)
+	Until  time.Time // Only lines written before Until (zero: no upper bound)
+	Follow bool      // Keep streaming new output (see LogsStream)
+}
+
+// dockerOptions maps o to the Docker API's options. Without a window only
+// the last logTailLines lines are returned; with one, every line inside it.
+func (o LogOptions) dockerOptions() container.LogsOptions {
+	opts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     o.Follow,
+		Tail:       logTailLines,
+	}
+	if !o.Since.IsZero() {
+		opts.Since = dockerTimestamp(o.Since)
+		opts.Tail = "all"
+	}
+	if !o.Until.IsZero() {
+		opts.Until = dockerTimestamp(o.Until)
+		opts.Tail = "all"
+	}
+	return opts
+}
+
+// LogsWithOptions is LogsStream for the time window in opts, for example
+// the minutes around a failed verification
+func (s *Service) LogsWithOptions(ctx context.Context, serviceName string, opts LogOptions) (io.ReadCloser, error) {
 	// Find container for service
 	listOpts := container.ListOptions{
 		All: true,
@@ -710,29 +1095,56 @@ func (s *Service) Logs(ctx context.Context, serviceName string) (string, error)
 
 	containers, err := s.cli.ContainerList(ctx, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("list containers: %w", err)
+		return nil, fmt.Errorf("list containers: %w", err)
 	}
 
 	if len(containers) == 0 {
-		return "", fmt.Errorf("service %s not found", serviceName)
+		return nil, fmt.Errorf("service %s not found", serviceName)
 	}
 
-	reader, err := s.cli.ContainerLogs(ctx, containers[0].ID, container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Tail:       logTailLines,
-	})
+	// TTY containers produce a raw stream; all others are multiplexed
+	inspect, err := s.cli.ContainerInspect(ctx, containers[0].ID)
 	if err != nil {
-		return "", fmt.Errorf("get logs: %w", err)
+		return nil, fmt.Errorf("inspect container: %w", err)
 	}
-	defer reader.Close()
 
-	logs, err := io.ReadAll(reader)
+	reader, err := s.cli.ContainerLogs(ctx, containers[0].ID, opts.dockerOptions())
 	if err != nil {
-		return "", fmt.Errorf("read logs: %w", err)
+		return nil, fmt.Errorf("get logs: %w", err)
 	}
 
-	return string(logs), nil
+	tty := inspect.Config != nil && inspect.Config.Tty
+	return demuxLogs(ctx, reader, tty), nil
+}
+
+// logStream is a demultiplexed log reader that also closes the raw stream
+type logStream struct {
+	*io.PipeReader
+	raw io.ReadCloser
+}
+
+// Close stops the demux goroutine and releases the Docker connection
+func (l *logStream) Close() error {
+	_ = l.PipeReader.Close()
+	return l.raw.Close()
+}
+
+// demuxLogs splits Docker's multiplexed stdout/stderr framing into plain
+// output. A stream interrupted by ctx cancellation ends cleanly with io.EOF.
+func demuxLogs(ctx context.Context, raw io.ReadCloser, tty bool) io.ReadCloser {
+	if tty {
+		return raw
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, raw)
+		if ctx.Err() != nil {
+			err = nil
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return &logStream{PipeReader: pr, raw: raw}
 }
 
 // WaitForHealthy waits for all services to be running and healthy
@@ -750,23 +1162,7 @@ func (s *Service) WaitForHealthy(ctx context.Context, timeout time.Duration) err
 			return err
 		}
 
-		allHealthy := true
-		for _, svc := range status.Services {
-			// Container must be running
-			if svc.State != "running" {
-				allHealthy = false
-				break
-			}
-
-			// If container has a healthcheck, it must be healthy
-			// Health values: "healthy", "unhealthy", "starting", "none"
-			if svc.Health != "none" && svc.Health != "healthy" {
-				allHealthy = false
-				break
-			}
-		}
-
-		if allHealthy && len(status.Services) == len(s.project.Services) {
+		if status.Ready() {
 			return nil
 		}
 
@@ -824,6 +1220,36 @@ func buildEnvList(envMap composetypes.MappingWithEquals) []string {
 	return result
 }
 
+// buildHealthcheck translates a compose healthcheck block into the container
+// config, so Docker reports health for services whose image declares none.
+// A nil result keeps the image's own HEALTHCHECK.
+func buildHealthcheck(hc *composetypes.HealthCheckConfig) *container.HealthConfig {
+	if hc == nil {
+		return nil
+	}
+	if hc.Disable {
+		return &container.HealthConfig{Test: []string{"NONE"}}
+	}
+
+	cfg := &container.HealthConfig{Test: []string(hc.Test)}
+	if hc.Interval != nil {
+		cfg.Interval = time.Duration(*hc.Interval)
+	}
+	if hc.Timeout != nil {
+		cfg.Timeout = time.Duration(*hc.Timeout)
+	}
+	if hc.StartPeriod != nil {
+		cfg.StartPeriod = time.Duration(*hc.StartPeriod)
+	}
+	if hc.StartInterval != nil {
+		cfg.StartInterval = time.Duration(*hc.StartInterval)
+	}
+	if hc.Retries != nil {
+		cfg.Retries = int(*hc.Retries)
+	}
+	return cfg
+}
+
 func deriveHealthURL(serviceName string, ports []string) string {
 	if len(ports) == 0 {
 		return ""
@@ -0,0 +1,345 @@
+package compose
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func newTestService(services composetypes.Services) *Service {
+	return &Service{
+		project:     &composetypes.Project{Services: services},
+		projectName: "test",
+		networkIDs:  make(map[string]string),
+	}
+}
+
+func TestSortServicesByDependency(t *testing.T) {
+	s := newTestService(composetypes.Services{
+		"app": {Name: "app", DependsOn: composetypes.DependsOnConfig{"db": {}}},
+		"db":  {Name: "db"},
+	})
+
+	ordered, err := s.sortServicesByDependency()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(ordered) != 2 {
+		t.Fatalf("expected 2 services, got %d", len(ordered))
+	}
+	if ordered[0].Name != "db" || ordered[1].Name != "app" {
+		t.Fatalf("expected db before app, got %s, %s", ordered[0].Name, ordered[1].Name)
+	}
+}
+
+func TestSortServicesByDependencyCycle(t *testing.T) {
+	s := newTestService(composetypes.Services{
+		"a": {Name: "a", DependsOn: composetypes.DependsOnConfig{"b": {}}},
+		"b": {Name: "b", DependsOn: composetypes.DependsOnConfig{"a": {}}},
+	})
+
+	_, err := s.sortServicesByDependency()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("expected ErrCircularDependency, got %v", err)
+	}
+}
+
+func TestIsPortConflict(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("Bind for 0.0.0.0:5432 failed: port is already allocated"), true},
+		{errors.New("listen tcp 0.0.0.0:8080: bind: address already in use"), true},
+		{errors.New("no such image"), false},
+	}
+	for _, tc := range cases {
+		if got := isPortConflict(tc.err); got != tc.want {
+			t.Fatalf("isPortConflict(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestDemuxLogs(t *testing.T) {
+	var raw bytes.Buffer
+	_, _ = stdcopy.NewStdWriter(&raw, stdcopy.Stdout).Write([]byte("out line\n"))
+	_, _ = stdcopy.NewStdWriter(&raw, stdcopy.Stderr).Write([]byte("err line\n"))
+
+	reader := demuxLogs(context.Background(), io.NopCloser(&raw), false)
+	defer reader.Close()
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != "out line\nerr line\n" {
+		t.Fatalf("expected demultiplexed output, got %q", got)
+	}
+}
+
+func TestDemuxLogsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pr, pw := io.Pipe()
+
+	reader := demuxLogs(ctx, pr, false)
+	defer reader.Close()
+
+	// Simulate the Docker client aborting the follow request on cancellation
+	cancel()
+	_ = pw.CloseWithError(context.Canceled)
+
+	if _, err := io.ReadAll(reader); err != nil {
+		t.Fatalf("expected clean EOF after cancellation, got %v", err)
+	}
+}
+
+func TestServiceWithDependencies(t *testing.T) {
+	s := newTestService(composetypes.Services{
+		"app":    {Name: "app", DependsOn: composetypes.DependsOnConfig{"api": {}}},
+		"api":    {Name: "api", DependsOn: composetypes.DependsOnConfig{"db": {}}},
+		"db":     {Name: "db"},
+		"jaeger": {Name: "jaeger"},
+	})
+
+	ordered, err := s.serviceWithDependencies("api")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(ordered) != 2 || ordered[0].Name != "db" || ordered[1].Name != "api" {
+		t.Fatalf("expected [db api], got %v", ordered)
+	}
+
+	if _, err := s.serviceWithDependencies("missing"); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestBuildHealthcheck(t *testing.T) {
+	if buildHealthcheck(nil) != nil {
+		t.Fatal("expected nil healthcheck to keep the image default")
+	}
+
+	interval := composetypes.Duration(5 * time.Second)
+	timeout := composetypes.Duration(3 * time.Second)
+	startPeriod := composetypes.Duration(10 * time.Second)
+	retries := uint64(5)
+	got := buildHealthcheck(&composetypes.HealthCheckConfig{
+		Test:        composetypes.HealthCheckTest{"CMD-SHELL", "pg_isready -U skillflow"},
+		Interval:    &interval,
+		Timeout:     &timeout,
+		StartPeriod: &startPeriod,
+		Retries:     &retries,
+	})
+	if len(got.Test) != 2 || got.Test[1] != "pg_isready -U skillflow" {
+		t.Fatalf("unexpected test command: %v", got.Test)
+	}
+	if got.Interval != 5*time.Second || got.Timeout != 3*time.Second || got.StartPeriod != 10*time.Second || got.Retries != 5 {
+		t.Fatalf("unexpected timings: %+v", got)
+	}
+
+	if disabled := buildHealthcheck(&composetypes.HealthCheckConfig{Disable: true}); disabled.Test[0] != "NONE" {
+		t.Fatalf("expected NONE for a disabled healthcheck, got %v", disabled.Test)
+	}
+}
+
+func TestStartInOrderWaitsForHealthyDependency(t *testing.T) {
+	ordered := []composetypes.ServiceConfig{
+		{Name: "postgres"},
+		{Name: "jaeger"},
+		{Name: "server", DependsOn: composetypes.DependsOnConfig{
+			"postgres": {Condition: composetypes.ServiceConditionHealthy},
+			"jaeger":   {Condition: composetypes.ServiceConditionStarted},
+		}},
+	}
+
+	var events []string
+	healthy := false
+	start := func(ctx context.Context, svc composetypes.ServiceConfig) error {
+		if svc.Name == "server" && !healthy {
+			t.Fatal("server created before postgres reported healthy")
+		}
+		events = append(events, "start "+svc.Name)
+		return nil
+	}
+	waitHealthy := func(ctx context.Context, name string) error {
+		events = append(events, "wait "+name)
+		healthy = true
+		return nil
+	}
+
+	if err := startInOrder(context.Background(), ordered, start, waitHealthy); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "start postgres,start jaeger,wait postgres,start server"
+	if got := strings.Join(events, ","); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func TestStartInOrderHealthyDependencyFails(t *testing.T) {
+	ordered := []composetypes.ServiceConfig{
+		{Name: "postgres"},
+		{Name: "server", DependsOn: composetypes.DependsOnConfig{
+			"postgres": {Condition: composetypes.ServiceConditionHealthy},
+		}},
+	}
+
+	started := map[string]bool{}
+	start := func(ctx context.Context, svc composetypes.ServiceConfig) error {
+		started[svc.Name] = true
+		return nil
+	}
+	waitHealthy := func(ctx context.Context, name string) error {
+		return errors.New("service postgres is unhealthy")
+	}
+
+	err := startInOrder(context.Background(), ordered, start, waitHealthy)
+	if err == nil || !strings.Contains(err.Error(), "dependency postgres") {
+		t.Fatalf("expected dependency error, got %v", err)
+	}
+	if started["server"] {
+		t.Fatal("expected server not to be created")
+	}
+}
+
+func TestBuildTagAndContext(t *testing.T) {
+	svc := composetypes.ServiceConfig{Name: "api", Build: &composetypes.BuildConfig{Context: "./api"}}
+	if got := buildTag("proj", svc); got != "proj-api" {
+		t.Fatalf("expected default tag proj-api, got %q", got)
+	}
+	svc.Image = "example/api:dev"
+	if got := buildTag("proj", svc); got != "example/api:dev" {
+		t.Fatalf("expected image name as tag, got %q", got)
+	}
+
+	if got := buildContextDir("/work", svc.Build); got != filepath.Join("/work", "api") {
+		t.Fatalf("expected relative context resolved against project dir, got %q", got)
+	}
+	if got := buildContextDir("/work", &composetypes.BuildConfig{}); got != "/work" {
+		t.Fatalf("expected empty context to mean project dir, got %q", got)
+	}
+	if got := buildContextDir("/work", &composetypes.BuildConfig{Context: "/abs/ctx"}); got != "/abs/ctx" {
+		t.Fatalf("expected absolute context unchanged, got %q", got)
+	}
+}
+
+func TestTarBuildContextHonoursDockerignore(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Dockerfile":    "FROM scratch\n",
+		"secret.env":    "TOKEN=x\n",
+		".dockerignore": "*.env\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	rc, err := tarBuildContext(dir)
+	if err != nil {
+		t.Fatalf("tarBuildContext: %v", err)
+	}
+	defer rc.Close()
+
+	names := map[string]bool{}
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read tar: %v", err)
+		}
+		names[hdr.Name] = true
+	}
+	if !names["Dockerfile"] || names["secret.env"] {
+		t.Fatalf("expected Dockerfile included and secret.env excluded, got %v", names)
+	}
+
+	if _, err := tarBuildContext(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected error for missing context dir")
+	}
+}
+
+func TestServiceStatusHostPort(t *testing.T) {
+	status := &ServiceStatus{Services: map[string]ServiceInfo{
+		"postgres": {Ports: []string{"0.0.0.0:49153->5432/tcp", ":::49153->5432/tcp"}},
+		"otel":     {Ports: []string{"0.0.0.0:4317->4317/tcp", "0.0.0.0:55001->13133/tcp"}},
+	}}
+
+	if port, err := status.HostPort("postgres", 5432); err != nil || port != 49153 {
+		t.Fatalf("expected 49153, got %d (%v)", port, err)
+	}
+	if port, err := status.HostPort("otel", 13133); err != nil || port != 55001 {
+		t.Fatalf("expected 55001, got %d (%v)", port, err)
+	}
+	if _, err := status.HostPort("otel", 8888); err == nil {
+		t.Fatal("expected error for unpublished port")
+	}
+	if _, err := status.HostPort("jaeger", 16686); !errors.Is(err, ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got %v", err)
+	}
+}
+
+func TestLeakError(t *testing.T) {
+	err := error(&LeakError{
+		Project:    "air-ci",
+		Containers: []string{"air-ci-jaeger-1", "air-ci-postgres-1"},
+		Volumes:    []string{"air-ci_pgdata"},
+	})
+	if !errors.Is(err, ErrResourcesLeaked) {
+		t.Fatalf("expected errors.Is ErrResourcesLeaked")
+	}
+	want := "project resources remain after cleanup: project air-ci: 2 containers (air-ci-jaeger-1, air-ci-postgres-1), 1 volume (air-ci_pgdata)"
+	if err.Error() != want {
+		t.Fatalf("Error() = %q\nwant      %q", err.Error(), want)
+	}
+}
+
+func TestContainerName(t *testing.T) {
+	s := newTestService(nil)
+	named := composetypes.ServiceConfig{Name: "postgres", ContainerName: "postgres"}
+	if got := s.containerName(named); got != "postgres" {
+		t.Fatalf("expected container_name to be used, got %q", got)
+	}
+	if got := s.containerName(composetypes.ServiceConfig{Name: "jaeger"}); got != "test-jaeger-1" {
+		t.Fatalf("expected compose default naming, got %q", got)
+	}
+
+	// Parallel projects must not share a fixed name
+	s.randomizePorts = true
+	if got := s.containerName(named); got != "test-postgres-1" {
+		t.Fatalf("expected a project-scoped name with RandomizePorts, got %q", got)
+	}
+}
+
+func TestServiceStatusReady(t *testing.T) {
+	running := map[string]ServiceInfo{"postgres": {Name: "postgres", State: "running", Health: "healthy"}}
+	tests := []struct {
+		name   string
+		status ServiceStatus
+		want   bool
+	}{
+		{"all present and healthy", ServiceStatus{Services: running}, true},
+		{"service missing", ServiceStatus{Services: running, Missing: []string{"jaeger"}}, false},
+		{"no services", ServiceStatus{Services: map[string]ServiceInfo{}}, false},
+		{"starting", ServiceStatus{Services: map[string]ServiceInfo{"postgres": {State: "running", Health: "starting"}}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.Ready(); got != tt.want {
+			t.Fatalf("%s: expected Ready()=%v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ImageDigest records which registry digest a service's container runs
+type ImageDigest struct {
+	Service  string
+	Image    string // Image reference from the compose file
+	Expected string // Pinned digest; empty when the image is only tagged
+	Actual   string // Registry digest of the running image; empty if it has none (never pulled)
+}
+
+// Matches reports whether the running image is the expected one. An image
+// without an expected digest always matches.
+func (d ImageDigest) Matches() bool {
+	return d.Expected == "" || d.Actual == d.Expected
+}
+
+// VerifyImageDigests compares the image each started container runs against
+// its expected digest: the one pinned in the compose file, else the service's
+// entry in Config.ImageDigests. This catches a stale local ":latest" that a
+// tag comparison cannot. Services built from source and services without a
+// container are skipped. The result covers every checked service; the error
+// is a *DigestError when any of them runs something else.
+func (s *Service) VerifyImageDigests(ctx context.Context) ([]ImageDigest, error) {
+	var digests, mismatches []ImageDigest
+	for _, svc := range s.project.Services {
+		if svc.Build != nil || svc.Image == "" {
+			continue
+		}
+		expected, err := expectedDigest(svc.Image, s.imageDigests[svc.Name])
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", svc.Name, err)
+		}
+
+		containers, err := s.cli.ContainerList(ctx, container.ListOptions{
+			Filters: filters.NewArgs(
+				filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", s.projectName)),
+				filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", svc.Name)),
+			),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list containers: %w", err)
+		}
+		if len(containers) == 0 {
+			continue
+		}
+
+		// The container's image ID is immutable even if the tag has since moved
+		inspect, err := s.cli.ContainerInspect(ctx, containers[0].ID)
+		if err != nil {
+			return nil, fmt.Errorf("inspect container %s: %w", svc.Name, err)
+		}
+		img, err := s.cli.ImageInspect(ctx, inspect.Image)
+		if err != nil {
+			return nil, fmt.Errorf("inspect image %s: %w", svc.Image, err)
+		}
+
+		d := ImageDigest{
+			Service:  svc.Name,
+			Image:    svc.Image,
+			Expected: expected,
+			Actual:   repoDigest(svc.Image, img.RepoDigests, expected),
+		}
+		digests = append(digests, d)
+		if !d.Matches() {
+			mismatches = append(mismatches, d)
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return digests, &DigestError{Mismatches: mismatches}
+	}
+	return digests, nil
+}
+
+// expectedDigest returns the digest pinned in ref, falling back to
+// configured. A ref pinned to a different digest than configured is an error.
+func expectedDigest(ref, configured string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", fmt.Errorf("parse image reference %q: %w", ref, err)
+	}
+	canonical, ok := named.(reference.Canonical)
+	if !ok {
+		return configured, nil
+	}
+	pinned := canonical.Digest().String()
+	if configured != "" && configured != pinned {
+		return "", fmt.Errorf("image %s is pinned to %s but ImageDigests expects %s", ref, pinned, configured)
+	}
+	return pinned, nil
+}
+
+// repoDigest picks the digest of ref's repository from an image's
+// RepoDigests. A multi-platform image can carry several; expected wins when
+// present, so an index and its platform manifest both count as a match.
+func repoDigest(ref string, repoDigests []string, expected string) string {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ""
+	}
+
+	var found string
+	for _, rd := range repoDigests {
+		parsed, err := reference.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		canonical, ok := parsed.(reference.Canonical)
+		if !ok || parsed.Name() != named.Name() {
+			continue
+		}
+		digest := canonical.Digest().String()
+		if digest == expected {
+			return digest
+		}
+		if found == "" {
+			found = digest
+		}
+	}
+	return found
+}
@@ -0,0 +1,83 @@
+package compose
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func TestExpectedDigest(t *testing.T) {
+	tests := []struct {
+		ref, configured string
+		want            string
+		wantErr         bool
+	}{
+		{ref: "postgres:16", want: ""},
+		{ref: "postgres:latest", configured: digestA, want: digestA},
+		{ref: "postgres:16@" + digestA, want: digestA},
+		{ref: "postgres@" + digestA, configured: digestA, want: digestA},
+		{ref: "postgres@" + digestA, configured: digestB, wantErr: true},
+		{ref: "Not A Ref", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := expectedDigest(tt.ref, tt.configured)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expectedDigest(%q, %q) succeeded, want error", tt.ref, tt.configured)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("expectedDigest(%q, %q) = %q, %v; want %q", tt.ref, tt.configured, got, err, tt.want)
+		}
+	}
+}
+
+func TestRepoDigest(t *testing.T) {
+	repoDigests := []string{
+		"otel/opentelemetry-collector@" + digestB,
+		"postgres@" + digestB,
+		"docker.io/library/postgres@" + digestA,
+	}
+	// Name normalization makes "postgres" and "docker.io/library/postgres" one repository
+	if got := repoDigest("postgres:latest", repoDigests, digestA); got != digestA {
+		t.Fatalf("repoDigest with expected present = %q, want %s", got, digestA)
+	}
+	if got := repoDigest("postgres:latest", repoDigests, ""); got != digestB {
+		t.Fatalf("repoDigest without expected = %q, want first match %s", got, digestB)
+	}
+	if got := repoDigest("redis:7", repoDigests, ""); got != "" {
+		t.Fatalf("repoDigest for another repository = %q, want empty", got)
+	}
+	if got := repoDigest("postgres:latest", nil, digestA); got != "" {
+		t.Fatalf("repoDigest for a never-pulled image = %q, want empty", got)
+	}
+}
+
+func TestDigestError(t *testing.T) {
+	err := error(&DigestError{Mismatches: []ImageDigest{
+		{Service: "db", Image: "postgres:latest", Expected: digestA, Actual: digestB},
+		{Service: "cache", Image: "redis:7", Expected: digestB},
+	}})
+	if !errors.Is(err, ErrImageDigestMismatch) {
+		t.Fatal("DigestError does not unwrap to ErrImageDigestMismatch")
+	}
+	msg := err.Error()
+	for _, want := range []string{"db (postgres:latest): want " + digestA + ", running " + digestB, "cache (redis:7)", "no registry digest"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("error %q does not mention %q", msg, want)
+		}
+	}
+
+	if !(ImageDigest{Actual: digestA}).Matches() {
+		t.Fatal("an image without an expected digest should match")
+	}
+	if (ImageDigest{Expected: digestA}).Matches() {
+		t.Fatal("an image without a registry digest should not match a pinned one")
+	}
+}
@@ -0,0 +1,102 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sentinel errors returned (wrapped) by compose operations.
+// Use errors.Is to distinguish failure classes programmatically.
+var (
+	// ErrDockerUnavailable indicates the Docker daemon could not be reached
+	ErrDockerUnavailable = errors.New("docker daemon unavailable")
+
+	// ErrImagePull indicates a service image could not be pulled
+	ErrImagePull = errors.New("image pull failed")
+
+	// ErrImageBuild indicates a service image could not be built from its build section
+	ErrImageBuild = errors.New("image build failed")
+
+	// ErrPortInUse indicates a published host port is already allocated
+	ErrPortInUse = errors.New("port already in use")
+
+	// ErrCircularDependency indicates services depend on each other in a cycle
+	ErrCircularDependency = errors.New("circular service dependency")
+
+	// ErrServiceNotFound indicates a service name is not defined in the compose project
+	ErrServiceNotFound = errors.New("service not defined in compose project")
+
+	// ErrResourcesLeaked indicates project resources survived cleanup (see LeakError)
+	ErrResourcesLeaked = errors.New("project resources remain after cleanup")
+
+	// ErrImageDigestMismatch indicates a container runs an image other than
+	// the pinned digest (see DigestError)
+	ErrImageDigestMismatch = errors.New("image digest mismatch")
+)
+
+// LeakError lists the labeled project resources VerifyCleanup found
+type LeakError struct {
+	Project    string
+	Containers []string
+	Networks   []string
+	Volumes    []string
+}
+
+// Error enumerates the leaked resources by kind
+func (e *LeakError) Error() string {
+	var kinds []string
+	for _, k := range []struct {
+		kind  string
+		names []string
+	}{{"container", e.Containers}, {"network", e.Networks}, {"volume", e.Volumes}} {
+		if len(k.names) == 0 {
+			continue
+		}
+		kind := k.kind
+		if len(k.names) > 1 {
+			kind += "s"
+		}
+		kinds = append(kinds, fmt.Sprintf("%d %s (%s)", len(k.names), kind, strings.Join(k.names, ", ")))
+	}
+	return fmt.Sprintf("%v: project %s: %s", ErrResourcesLeaked, e.Project, strings.Join(kinds, ", "))
+}
+
+// Unwrap returns ErrResourcesLeaked for errors.Is
+func (e *LeakError) Unwrap() error {
+	return ErrResourcesLeaked
+}
+
+// DigestError lists the services VerifyImageDigests found running an
+// unexpected image
+type DigestError struct {
+	Mismatches []ImageDigest
+}
+
+// Error names each service with the digest it wanted and the one it runs
+func (e *DigestError) Error() string {
+	parts := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		actual := m.Actual
+		if actual == "" {
+			actual = "no registry digest"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s): want %s, running %s", m.Service, m.Image, m.Expected, actual))
+	}
+	return fmt.Sprintf("%v: %s", ErrImageDigestMismatch, strings.Join(parts, "; "))
+}
+
+// Unwrap returns ErrImageDigestMismatch for errors.Is
+func (e *DigestError) Unwrap() error {
+	return ErrImageDigestMismatch
+}
+
+// isPortConflict reports whether a Docker API error was caused by a host port conflict.
+func isPortConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "port is already allocated") ||
+		strings.Contains(msg, "address already in use")
+}
@@ -0,0 +1,141 @@
+// Package fakecompose provides an in-memory test double for compose.Service.
+package fakecompose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/compose"
+)
+
+// Service is a fake compose manager that records calls and returns canned results.
+// The zero value is ready to use: every operation succeeds and Status reports no services.
+type Service struct {
+	mu sync.Mutex
+
+	// Canned errors returned by the corresponding methods
+	StartErr          error
+	StopErr           error
+	StartServiceErr   error
+	StopServiceErr    error
+	StatusErr         error
+	LogsErr           error
+	WaitForHealthyErr error
+	CloseErr          error
+
+	// StatusResult is returned by Status (an empty status if nil)
+	StatusResult *compose.ServiceStatus
+
+	// ServiceLogs maps service name -> logs returned by Logs
+	ServiceLogs map[string]string
+
+	calls []string
+}
+
+// New creates a fake reporting the given services as running and healthy.
+func New(serviceNames ...string) *Service {
+	status := &compose.ServiceStatus{Services: make(map[string]compose.ServiceInfo)}
+	for _, name := range serviceNames {
+		status.Services[name] = compose.ServiceInfo{
+			Name:   name,
+			State:  "running",
+			Health: "healthy",
+		}
+	}
+	return &Service{
+		StatusResult: status,
+		ServiceLogs:  make(map[string]string),
+	}
+}
+
+// Start records the call and returns StartErr.
+func (s *Service) Start(ctx context.Context) error {
+	s.record("Start")
+	return s.StartErr
+}
+
+// Stop records the call and returns StopErr.
+func (s *Service) Stop(ctx context.Context) error {
+	s.record("Stop")
+	return s.StopErr
+}
+
+// StartService records the call and returns StartServiceErr.
+func (s *Service) StartService(ctx context.Context, name string) error {
+	s.record("StartService")
+	return s.StartServiceErr
+}
+
+// StopService records the call and returns StopServiceErr.
+func (s *Service) StopService(ctx context.Context, name string) error {
+	s.record("StopService")
+	return s.StopServiceErr
+}
+
+// Status records the call and returns StatusResult or StatusErr.
+func (s *Service) Status(ctx context.Context) (*compose.ServiceStatus, error) {
+	s.record("Status")
+	if s.StatusErr != nil {
+		return nil, s.StatusErr
+	}
+	if s.StatusResult == nil {
+		return &compose.ServiceStatus{Services: make(map[string]compose.ServiceInfo)}, nil
+	}
+	return s.StatusResult, nil
+}
+
+// Logs records the call and returns the canned logs for serviceName.
+func (s *Service) Logs(ctx context.Context, serviceName string) (string, error) {
+	s.record("Logs")
+	if s.LogsErr != nil {
+		return "", s.LogsErr
+	}
+	logs, ok := s.ServiceLogs[serviceName]
+	if !ok {
+		return "", fmt.Errorf("service %s not found", serviceName)
+	}
+	return logs, nil
+}
+
+// LogsStream records the call and returns the canned logs for serviceName as a reader.
+// follow is ignored: the reader ends after the canned logs.
+func (s *Service) LogsStream(ctx context.Context, serviceName string, follow bool) (io.ReadCloser, error) {
+	s.record("LogsStream")
+	if s.LogsErr != nil {
+		return nil, s.LogsErr
+	}
+	logs, ok := s.ServiceLogs[serviceName]
+	if !ok {
+		return nil, fmt.Errorf("service %s not found", serviceName)
+	}
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+// WaitForHealthy records the call and returns WaitForHealthyErr.
+func (s *Service) WaitForHealthy(ctx context.Context, timeout time.Duration) error {
+	s.record("WaitForHealthy")
+	return s.WaitForHealthyErr
+}
+
+// Close records the call and returns CloseErr.
+func (s *Service) Close() error {
+	s.record("Close")
+	return s.CloseErr
+}
+
+// Calls returns the method names invoked so far, in order.
+func (s *Service) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+func (s *Service) record(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, name)
+}
@@ -0,0 +1,161 @@
+//go:build integration
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+)
+
+const healthcheckCompose = `services:
+  postgres:
+    image: postgres:16-alpine
+    environment:
+      POSTGRES_USER: air
+      POSTGRES_PASSWORD: air
+      POSTGRES_DB: air
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U air"]
+      interval: 1s
+      timeout: 3s
+      retries: 30
+      start_period: 2s
+`
+
+// writeCompose writes content to a compose file in a temp dir and returns its path
+func writeCompose(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "docker-compose.yml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write compose file: %v", err)
+	}
+	return path
+}
+
+// newIntegrationService starts a compose project and stops it when the test ends
+func newIntegrationService(t *testing.T, content string) *Service {
+	t.Helper()
+	svc, err := New(Config{
+		ComposeFilePath: writeCompose(t, content),
+		ProjectName:     fmt.Sprintf("air-it-%d", time.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+		defer cancel()
+		_ = svc.Stop(ctx)
+		if err := svc.VerifyCleanup(ctx); err != nil {
+			t.Errorf("VerifyCleanup: %v", err)
+		}
+		_ = svc.Close()
+	})
+	return svc
+}
+
+// containerHealth returns the Docker health status of a service's container
+func containerHealth(ctx context.Context, t *testing.T, svc *Service, name string) string {
+	t.Helper()
+	status, err := svc.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	info, ok := status.Services[name]
+	if !ok {
+		t.Fatalf("service %s has no container", name)
+	}
+	return info.Health
+}
+
+func TestComposeHealthcheckApplied(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	svc := newIntegrationService(t, healthcheckCompose)
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	if got := containerHealth(ctx, t, svc, "postgres"); got != "starting" {
+		t.Fatalf("expected health starting right after start, got %q", got)
+	}
+
+	if err := svc.WaitForHealthy(ctx, 2*time.Minute); err != nil {
+		t.Fatalf("WaitForHealthy: %v", err)
+	}
+	if got := containerHealth(ctx, t, svc, "postgres"); got != "healthy" {
+		t.Fatalf("expected healthy, got %q", got)
+	}
+}
+
+func TestComposeBuildSection(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	composePath := writeCompose(t, `services:
+  app:
+    build:
+      context: ./app
+    command: ["sleep", "300"]
+`)
+	appDir := filepath.Join(filepath.Dir(composePath), "app")
+	if err := os.MkdirAll(appDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(appDir, "Dockerfile"), []byte("FROM alpine:3.20\nRUN echo built > /built\n"), 0o644); err != nil {
+		t.Fatalf("write Dockerfile: %v", err)
+	}
+
+	svc, err := New(Config{ComposeFilePath: composePath, ProjectName: fmt.Sprintf("air-it-%d", time.Now().UnixNano())})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+		defer cancel()
+		_ = svc.Stop(ctx)
+		_, _ = svc.cli.ImageRemove(ctx, buildTag(svc.projectName, svc.project.Services["app"]), image.RemoveOptions{Force: true})
+		_ = svc.Close()
+	})
+
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	status, err := svc.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if info, ok := status.Services["app"]; !ok || info.State != "running" {
+		t.Fatalf("expected built service running, got %+v", status.Services)
+	}
+}
+
+func TestCreateResourcesTracksEachResourceOnce(t *testing.T) {
+	svc := newIntegrationService(t, `services:
+  cache:
+    image: redis:7-alpine
+    volumes:
+      - data:/data
+volumes:
+  data:
+`)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// StartService runs createResources for every service it starts
+	for i := 0; i < 2; i++ {
+		if err := svc.createResources(ctx); err != nil {
+			t.Fatalf("createResources: %v", err)
+		}
+	}
+	if len(svc.volumeNames) != 1 || len(svc.networkIDs) != len(svc.project.Networks) {
+		t.Fatalf("expected each resource tracked once, got volumes %v networks %v", svc.volumeNames, svc.networkIDs)
+	}
+}
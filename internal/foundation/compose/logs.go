@@ -0,0 +1,193 @@
+package compose
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// LogLine is one timestamped line of a service's output
+type LogLine struct {
+	Service string
+	Time    time.Time
+	Text    string
+}
+
+// serviceContainer is a project container whose logs AllLogs reads
+type serviceContainer struct {
+	service string
+	id      string
+	tty     bool
+}
+
+// AllLogs passes the last log lines of every project container to fn,
+// merged in timestamp order. With follow it then streams new lines from all
+// containers, in arrival order, until they stop or ctx is cancelled. fn is
+// never called concurrently.
+func (s *Service) AllLogs(ctx context.Context, follow bool, fn func(LogLine)) error {
+	containers, err := s.serviceContainers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var backlog []LogLine
+	since := make(map[string]time.Time, len(containers))
+	for _, c := range containers {
+		fetched := time.Now()
+		lines, err := s.containerLogLines(ctx, c, container.LogsOptions{Tail: logTailLines})
+		if err != nil {
+			return err
+		}
+		// Resume just after the last line so the follow stream neither
+		// repeats nor drops output
+		since[c.id] = fetched
+		if len(lines) > 0 {
+			since[c.id] = lines[len(lines)-1].Time.Add(time.Nanosecond)
+		}
+		backlog = append(backlog, lines...)
+	}
+	for _, line := range mergeLogLines(backlog) {
+		fn(line)
+	}
+	if !follow {
+		return nil
+	}
+
+	out := make(chan LogLine)
+	errs := make(chan error, len(containers))
+	var wg sync.WaitGroup
+	for _, c := range containers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opts := container.LogsOptions{Follow: true, Since: dockerTimestamp(since[c.id])}
+			errs <- s.streamLogLines(ctx, c, opts, func(line LogLine) {
+				select {
+				case out <- line:
+				case <-ctx.Done():
+				}
+			})
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+		close(errs)
+	}()
+
+	for line := range out {
+		fn(line)
+	}
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serviceContainers lists the project's containers, sorted by service name
+func (s *Service) serviceContainers(ctx context.Context) ([]serviceContainer, error) {
+	listOpts := container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", s.projectName)),
+		),
+	}
+	list, err := s.cli.ContainerList(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+
+	var containers []serviceContainer
+	for _, c := range list {
+		name := c.Labels["com.docker.compose.service"]
+		if name == "" {
+			continue
+		}
+		inspect, err := s.cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("inspect container %s: %w", name, err)
+		}
+		containers = append(containers, serviceContainer{
+			service: name,
+			id:      c.ID,
+			tty:     inspect.Config != nil && inspect.Config.Tty,
+		})
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("no containers found for project %s", s.projectName)
+	}
+	sort.Slice(containers, func(i, j int) bool { return containers[i].service < containers[j].service })
+	return containers, nil
+}
+
+// containerLogLines reads a container's (non-following) logs into lines
+func (s *Service) containerLogLines(ctx context.Context, c serviceContainer, opts container.LogsOptions) ([]LogLine, error) {
+	var lines []LogLine
+	err := s.streamLogLines(ctx, c, opts, func(line LogLine) { lines = append(lines, line) })
+	return lines, err
+}
+
+// streamLogLines reads a container's stdout and stderr with Docker
+// timestamps and passes each line to fn
+func (s *Service) streamLogLines(ctx context.Context, c serviceContainer, opts container.LogsOptions, fn func(LogLine)) error {
+	opts.ShowStdout = true
+	opts.ShowStderr = true
+	opts.Timestamps = true
+	raw, err := s.cli.ContainerLogs(ctx, c.id, opts)
+	if err != nil {
+		return fmt.Errorf("get %s logs: %w", c.service, err)
+	}
+	logs := demuxLogs(ctx, raw, c.tty)
+	defer logs.Close()
+	return scanLogLines(ctx, logs, c.service, fn)
+}
+
+// scanLogLines splits r into LogLines; a cancelled ctx ends the scan cleanly
+func scanLogLines(ctx context.Context, r io.Reader, service string, fn func(LogLine)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fn(parseLogLine(service, scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("read %s logs: %w", service, err)
+	}
+	return nil
+}
+
+// parseLogLine splits the RFC 3339 timestamp Docker prefixes each line with
+// (Timestamps option) from the text. A line without one keeps a zero Time.
+func parseLogLine(service, raw string) LogLine {
+	raw = strings.TrimSuffix(raw, "\r")
+	stamp, text, ok := strings.Cut(raw, " ")
+	if ok {
+		if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+			return LogLine{Service: service, Time: t, Text: text}
+		}
+	}
+	return LogLine{Service: service, Text: raw}
+}
+
+// mergeLogLines orders lines by timestamp, keeping each service's own order
+// for equal timestamps
+func mergeLogLines(lines []LogLine) []LogLine {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	return lines
+}
+
+// dockerTimestamp formats t as the seconds.nanoseconds form the Docker API
+// accepts for log Since and Until
+func dockerTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10) + "." + fmt.Sprintf("%09d", t.Nanosecond())
+}
@@ -0,0 +1,66 @@
+package compose
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	line := parseLogLine("postgres", "2024-05-01T10:00:00.123456789Z database system is ready\r")
+	want := time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC)
+	if line.Service != "postgres" || !line.Time.Equal(want) || line.Text != "database system is ready" {
+		t.Fatalf("parseLogLine = %+v", line)
+	}
+
+	line = parseLogLine("jaeger", "no timestamp here")
+	if !line.Time.IsZero() || line.Text != "no timestamp here" {
+		t.Fatalf("expected untimestamped line kept whole, got %+v", line)
+	}
+}
+
+func TestMergeLogLines(t *testing.T) {
+	var lines []LogLine
+	add := func(service, logs string) {
+		if err := scanLogLines(context.Background(), strings.NewReader(logs), service, func(l LogLine) { lines = append(lines, l) }); err != nil {
+			t.Fatalf("scanLogLines: %v", err)
+		}
+	}
+	add("backend", "2024-05-01T10:00:01Z handled request\n2024-05-01T10:00:03Z handled request\n")
+	add("otel-collector", "2024-05-01T10:00:02Z export failed\n2024-05-01T10:00:03Z retrying\n")
+
+	var got []string
+	for _, l := range mergeLogLines(lines) {
+		got = append(got, l.Service+": "+l.Text)
+	}
+	want := []string{
+		"backend: handled request",
+		"otel-collector: export failed",
+		"backend: handled request",
+		"otel-collector: retrying",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("merged order:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDockerTimestamp(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 5, time.UTC)
+	if got := dockerTimestamp(ts); got != "1714557600.000000005" {
+		t.Fatalf("dockerTimestamp = %q", got)
+	}
+}
+
+func TestLogOptionsDockerOptions(t *testing.T) {
+	opts := LogOptions{Follow: true}.dockerOptions()
+	if opts.Tail != logTailLines || opts.Since != "" || opts.Until != "" || !opts.Follow || !opts.ShowStdout || !opts.ShowStderr {
+		t.Fatalf("default options = %+v", opts)
+	}
+
+	failedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	opts = LogOptions{Since: failedAt.Add(-time.Minute), Until: failedAt.Add(time.Minute)}.dockerOptions()
+	if opts.Since != "1714557540.000000000" || opts.Until != "1714557660.000000000" || opts.Tail != "all" {
+		t.Fatalf("window options = %+v", opts)
+	}
+}
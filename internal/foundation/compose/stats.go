@@ -0,0 +1,112 @@
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// statsSampleInterval separates the two samples CPU usage is measured over
+// when Docker's one-shot stats carry no previous reading
+const statsSampleInterval = 500 * time.Millisecond
+
+// ContainerStats is a point-in-time resource reading for a service's container
+type ContainerStats struct {
+	Service       string
+	CPUPercent    float64 // Share of one CPU, so a busy 4-core container can read 400
+	MemoryUsage   uint64  // Bytes in use, excluding reclaimable page cache
+	MemoryLimit   uint64  // Bytes available to the container (host memory if unlimited)
+	MemoryPercent float64
+}
+
+// Stats reads the CPU and memory usage of a running service's container,
+// computed the way docker stats does
+func (s *Service) Stats(ctx context.Context, serviceName string) (ContainerStats, error) {
+	containers, err := s.cli.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.project=%s", s.projectName)),
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", serviceName)),
+		),
+	})
+	if err != nil {
+		return ContainerStats{}, fmt.Errorf("list containers: %w", err)
+	}
+	if len(containers) == 0 {
+		return ContainerStats{}, fmt.Errorf("%w: %s has no running container", ErrServiceNotFound, serviceName)
+	}
+	id := containers[0].ID
+
+	sample, err := s.statsSample(ctx, id)
+	if err != nil {
+		return ContainerStats{}, fmt.Errorf("stats for %s: %w", serviceName, err)
+	}
+	// One-shot readings skip Docker's second sample, so take our own
+	if sample.PreCPUStats.SystemUsage == 0 {
+		select {
+		case <-ctx.Done():
+			return ContainerStats{}, fmt.Errorf("stats for %s: %w", serviceName, ctx.Err())
+		case <-time.After(statsSampleInterval):
+		}
+		next, err := s.statsSample(ctx, id)
+		if err != nil {
+			return ContainerStats{}, fmt.Errorf("stats for %s: %w", serviceName, err)
+		}
+		next.PreCPUStats = sample.CPUStats
+		sample = next
+	}
+
+	stats := containerStats(sample)
+	stats.Service = serviceName
+	return stats, nil
+}
+
+func (s *Service) statsSample(ctx context.Context, id string) (container.StatsResponse, error) {
+	resp, err := s.cli.ContainerStatsOneShot(ctx, id)
+	if err != nil {
+		return container.StatsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var sample container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sample); err != nil {
+		return container.StatsResponse{}, fmt.Errorf("decode stats: %w", err)
+	}
+	return sample, nil
+}
+
+// containerStats derives CPU and memory figures from a stats reading whose
+// PreCPUStats holds the previous sample
+func containerStats(sample container.StatsResponse) ContainerStats {
+	var stats ContainerStats
+
+	cpuDelta := float64(sample.CPUStats.CPUUsage.TotalUsage) - float64(sample.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(sample.CPUStats.SystemUsage) - float64(sample.PreCPUStats.SystemUsage)
+	cpus := float64(sample.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(sample.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	// Page cache is reclaimable; cgroup v1 reports it as total_inactive_file
+	// and v2 as inactive_file
+	mem := sample.MemoryStats
+	stats.MemoryUsage = mem.Usage
+	cache, ok := mem.Stats["total_inactive_file"]
+	if !ok {
+		cache = mem.Stats["inactive_file"]
+	}
+	if cache < stats.MemoryUsage {
+		stats.MemoryUsage -= cache
+	}
+	stats.MemoryLimit = mem.Limit
+	if mem.Limit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(mem.Limit) * 100
+	}
+	return stats
+}
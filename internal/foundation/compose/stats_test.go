@@ -0,0 +1,37 @@
+package compose
+
+import (
+	"math"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestContainerStats(t *testing.T) {
+	var sample container.StatsResponse
+	sample.PreCPUStats.CPUUsage.TotalUsage = 1_000_000_000
+	sample.PreCPUStats.SystemUsage = 10_000_000_000
+	sample.CPUStats.CPUUsage.TotalUsage = 1_500_000_000
+	sample.CPUStats.SystemUsage = 14_000_000_000
+	sample.CPUStats.OnlineCPUs = 4
+	sample.MemoryStats.Usage = 300 << 20
+	sample.MemoryStats.Limit = 1 << 30
+	sample.MemoryStats.Stats = map[string]uint64{"inactive_file": 44 << 20}
+
+	stats := containerStats(sample)
+	// 0.5s of CPU over 4s of system time across 4 CPUs
+	if math.Abs(stats.CPUPercent-50) > 1e-9 {
+		t.Fatalf("CPUPercent = %v, want 50", stats.CPUPercent)
+	}
+	if stats.MemoryUsage != 256<<20 || stats.MemoryLimit != 1<<30 || stats.MemoryPercent != 25 {
+		t.Fatalf("memory = %d / %d (%v%%), want 256MiB / 1GiB (25%%)", stats.MemoryUsage, stats.MemoryLimit, stats.MemoryPercent)
+	}
+
+	// cgroup v1 names the cache total_inactive_file; no delta means no CPU reading
+	var idle container.StatsResponse
+	idle.MemoryStats.Usage = 100
+	idle.MemoryStats.Stats = map[string]uint64{"total_inactive_file": 40}
+	if stats := containerStats(idle); stats.CPUPercent != 0 || stats.MemoryUsage != 60 || stats.MemoryPercent != 0 {
+		t.Fatalf("idle stats = %+v", stats)
+	}
+}
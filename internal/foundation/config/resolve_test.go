@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "air.yaml")
+	content := "database-url: postgres://file\nproject-name: from-file\ncompose-file: from-file.yml\n"
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	t.Setenv("AIR_CONFIG", "")
+	t.Setenv("DATABASE_URL", "postgres://env")
+	t.Setenv("AIR_PROJECT_NAME", "from-env")
+	t.Setenv("AIR_COMPOSE_FILE", "")
+
+	resolved, err := ResolveConfig(ResolveOptions{
+		Flags:      map[string]string{KeyDatabaseURL: "postgres://flag"},
+		ConfigFile: file,
+	})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  Value
+		want Value
+	}{
+		{"flag beats env and file", resolved.DatabaseURL, Value{"postgres://flag", SourceFlag}},
+		{"env beats file", resolved.ProjectName, Value{"from-env", SourceEnv}},
+		{"file beats default", resolved.ComposeFile, Value{"from-file.yml", SourceFile}},
+		{"config file", resolved.ConfigFile, Value{file, SourceFlag}},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Fatalf("%s: expected %+v, got %+v", c.name, c.want, c.got)
+		}
+	}
+}
+
+func TestResolveConfigDefaults(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, key := range []string{"AIR_CONFIG", "DATABASE_URL", "AIR_COMPOSE_FILE", "AIR_PROJECT_NAME"} {
+		t.Setenv(key, "")
+	}
+
+	resolved, err := ResolveConfig(ResolveOptions{})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	if resolved.DatabaseURL != (Value{DefaultDatabaseURL, SourceDefault}) {
+		t.Fatalf("expected default database URL, got %+v", resolved.DatabaseURL)
+	}
+	if resolved.ProjectName != (Value{DefaultProjectName, SourceDefault}) {
+		t.Fatalf("expected default project name, got %+v", resolved.ProjectName)
+	}
+	if resolved.ConfigFile.Value != "" {
+		t.Fatalf("expected no config file, got %+v", resolved.ConfigFile)
+	}
+	if resolved.ComposeFile.Value != "" {
+		t.Fatalf("expected no compose file outside the repo, got %+v", resolved.ComposeFile)
+	}
+}
+
+func TestResolveConfigMissingExplicitFile(t *testing.T) {
+	if _, err := ResolveConfig(ResolveOptions{ConfigFile: filepath.Join(t.TempDir(), "missing.yaml")}); err == nil {
+		t.Fatal("expected error for missing explicit config file")
+	}
+}
+
+func TestResolvedEntriesMaskSecrets(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("AIR_CONFIG", "")
+	resolved, err := ResolveConfig(ResolveOptions{
+		Flags: map[string]string{KeyDatabaseURL: "postgres://air:s3cret@db:5432/air"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+
+	entries := resolved.Entries()
+	if entries[0].Key != KeyDatabaseURL {
+		t.Fatalf("expected %s first, got %+v", KeyDatabaseURL, entries[0])
+	}
+	want := Entry{Key: KeyDatabaseURL, Env: "DATABASE_URL", Value: "postgres://air:xxxxx@db:5432/air", Source: SourceFlag}
+	if entries[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, entries[0])
+	}
+}
+
+func TestMaskURL(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"postgres://air:s3cret@db:5432/air", "postgres://air:xxxxx@db:5432/air"},
+		{"postgres://air@db:5432/air?sslmode=require&password=s3cret", "postgres://air@db:5432/air?sslmode=require&password=xxxxx"},
+		{"postgres://db/air?PASSWORD=a&sslpassword=b&application_name=air", "postgres://db/air?PASSWORD=xxxxx&sslpassword=xxxxx&application_name=air"},
+		{"postgres://air@db/air?sslmode=disable", "postgres://air@db/air?sslmode=disable"},
+		{"localhost:4317", "localhost:4317"},
+	}
+	for _, tt := range tests {
+		if got := MaskURL(tt.raw); got != tt.want {
+			t.Fatalf("MaskURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
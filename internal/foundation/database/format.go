@@ -0,0 +1,218 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rivo/uniseg"
+)
+
+// QueryResult holds the result of a SQL query.
+// ColumnTypes holds the PostgreSQL type name of each column (e.g. "int4",
+// "timestamptz"), or "" where the type is unknown.
+type QueryResult struct {
+	Columns      []string `json:"columns"`
+	ColumnTypes  []string `json:"column_types,omitempty"`
+	Rows         [][]any  `json:"rows"`
+	RowsAffected int64    `json:"rows_affected"`
+}
+
+// columnType returns the type name of column i, or "" if unknown
+func (r *QueryResult) columnType(i int) string {
+	if i < len(r.ColumnTypes) {
+		return r.ColumnTypes[i]
+	}
+	return ""
+}
+
+// FormatTable renders a query result as an aligned text table.
+// Statements without result columns render as "Query OK, N rows affected";
+// NULL values render as "NULL" so they are distinguishable from empty strings.
+func FormatTable(w io.Writer, result *QueryResult) error {
+	if len(result.Columns) == 0 {
+		_, err := fmt.Fprintf(w, "Query OK, %d rows affected\n", result.RowsAffected)
+		return err
+	}
+
+	// Render cells once and measure their display width in terminal
+	// columns, so wide (CJK, emoji) and combining characters align
+	cells := make([][]string, len(result.Rows))
+	widths := make([]int, len(result.Columns))
+	for i, col := range result.Columns {
+		widths[i] = uniseg.StringWidth(col)
+	}
+	for r, row := range result.Rows {
+		cells[r] = make([]string, len(result.Columns))
+		for i := range result.Columns {
+			switch {
+			case i >= len(row):
+			case isNull(row[i]):
+				cells[r][i] = nullDisplay
+			default:
+				cells[r][i] = formatValue(row[i], result.columnType(i))
+			}
+			if n := uniseg.StringWidth(cells[r][i]); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var sb strings.Builder
+
+	// Header
+	for i, col := range result.Columns {
+		writePadded(&sb, col, widths[i])
+	}
+	sb.WriteString("\n")
+
+	// Separator
+	for i := range result.Columns {
+		sb.WriteString(strings.Repeat("-", widths[i]) + "  ")
+	}
+	sb.WriteString("\n")
+
+	// Rows
+	for _, row := range cells {
+		for i, val := range row {
+			writePadded(&sb, val, widths[i])
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "(%d rows)\n", len(result.Rows))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writePadded writes s left-aligned in a column width terminal cells wide,
+// plus the two-space column gap. fmt's %-*s pads by rune count, which
+// misaligns wide characters.
+func writePadded(sb *strings.Builder, s string, width int) {
+	sb.WriteString(s)
+	sb.WriteString(strings.Repeat(" ", max(width-uniseg.StringWidth(s), 0)+2))
+}
+
+// FormatJSON renders a query result as indented JSON.
+func FormatJSON(w io.Writer, result *QueryResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		return fmt.Errorf("encode query result: %w", err)
+	}
+	return nil
+}
+
+// FormatCSV renders a query result as CSV with a header row.
+// NULL is written as an empty unquoted field and an empty string as "".
+func FormatCSV(w io.Writer, result *QueryResult) error {
+	var sb strings.Builder
+	for i, col := range result.Columns {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(csvField(col))
+	}
+	sb.WriteByte('\n')
+
+	for _, row := range result.Rows {
+		for i := range result.Columns {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			if i < len(row) && !isNull(row[i]) {
+				sb.WriteString(csvField(formatValue(row[i], result.columnType(i))))
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("write csv: %w", err)
+	}
+	return nil
+}
+
+// csvField quotes a non-NULL CSV value when it is empty or contains
+// characters that would otherwise be ambiguous (RFC 4180)
+func csvField(v string) string {
+	if v != "" && !strings.ContainsAny(v, ",\"\r\n") && v[0] != ' ' && v[0] != '\t' {
+		return v
+	}
+	return `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+}
+
+// Format renders a query result in the named format ("table", "json" or "csv").
+func Format(w io.Writer, result *QueryResult, format string) error {
+	switch strings.ToLower(format) {
+	case "", "table":
+		return FormatTable(w, result)
+	case "json":
+		return FormatJSON(w, result)
+	case "csv":
+		return FormatCSV(w, result)
+	default:
+		return fmt.Errorf("unknown output format: %s (use table, json or csv)", format)
+	}
+}
+
+// nullDisplay is how FormatTable renders SQL NULL
+const nullDisplay = "NULL"
+
+// isNull reports whether v is SQL NULL, either nil or a pgtype value that
+// is not Valid
+func isNull(v any) bool {
+	if v == nil {
+		return true
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		inner, err := valuer.Value()
+		return err == nil && inner == nil
+	}
+	return false
+}
+
+// formatValue converts a column value to its display form, using the column
+// type to render times and binary data the way psql does.
+func formatValue(v any, typ string) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		if typ == "bytea" {
+			return `\x` + hex.EncodeToString(val)
+		}
+		return string(val)
+	case string:
+		return val
+	case time.Time:
+		switch typ {
+		case "date":
+			return val.Format("2006-01-02")
+		case "timestamp":
+			return val.Format("2006-01-02 15:04:05.999999")
+		default:
+			return val.Format("2006-01-02 15:04:05.999999Z07:00")
+		}
+	case [16]byte:
+		if typ == "uuid" {
+			return fmt.Sprintf("%x-%x-%x-%x-%x", val[0:4], val[4:6], val[6:8], val[8:10], val[10:16])
+		}
+		return fmt.Sprintf("%v", val)
+	case driver.Valuer:
+		// pgtype values such as Numeric and Interval render via their SQL form
+		if inner, err := val.Value(); err == nil {
+			if _, again := inner.(driver.Valuer); !again {
+				return formatValue(inner, typ)
+			}
+		}
+		return fmt.Sprintf("%v", val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
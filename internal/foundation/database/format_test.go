@@ -0,0 +1,250 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestFormatTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *QueryResult
+		want   string
+	}{
+		{
+			name:   "no columns",
+			result: &QueryResult{RowsAffected: 3},
+			want:   "Query OK, 3 rows affected\n",
+		},
+		{
+			name: "basic",
+			result: &QueryResult{
+				Columns: []string{"id", "name"},
+				Rows:    [][]any{{1, "alice"}, {2, "bob"}},
+			},
+			want: "id  name   \n" +
+				"--  -----  \n" +
+				"1   alice  \n" +
+				"2   bob    \n" +
+				"(2 rows)\n",
+		},
+		{
+			name: "nil values",
+			result: &QueryResult{
+				Columns: []string{"id", "note"},
+				Rows:    [][]any{{1, nil}, {nil, []byte("x")}},
+			},
+			want: "id    note  \n" +
+				"----  ----  \n" +
+				"1     NULL  \n" +
+				"NULL  x     \n" +
+				"(2 rows)\n",
+		},
+		{
+			name: "null versus empty string",
+			result: &QueryResult{
+				Columns: []string{"note"},
+				Rows:    [][]any{{""}, {nil}},
+			},
+			want: "note  \n" +
+				"----  \n" +
+				"      \n" +
+				"NULL  \n" +
+				"(2 rows)\n",
+		},
+		{
+			name: "wide columns",
+			result: &QueryResult{
+				Columns: []string{"a"},
+				Rows:    [][]any{{strings.Repeat("w", 12)}},
+			},
+			want: "a             \n" +
+				"------------  \n" +
+				"wwwwwwwwwwww  \n" +
+				"(1 rows)\n",
+		},
+		{
+			name: "unicode",
+			result: &QueryResult{
+				Columns: []string{"city", "n"},
+				Rows:    [][]any{{"Zürich", 1}, {"東京", 2}},
+			},
+			// 東京 is four terminal cells wide, not two runes
+			want: "city    n  \n" +
+				"------  -  \n" +
+				"Zürich  1  \n" +
+				"東京    2  \n" +
+				"(2 rows)\n",
+		},
+		{
+			name: "wide header",
+			result: &QueryResult{
+				Columns: []string{"名前", "id"},
+				Rows:    [][]any{{"a", 1}},
+			},
+			want: "名前  id  \n" +
+				"----  --  \n" +
+				"a     1   \n" +
+				"(1 rows)\n",
+		},
+		{
+			name: "short row",
+			result: &QueryResult{
+				Columns: []string{"a", "b"},
+				Rows:    [][]any{{"x"}},
+			},
+			want: "a  b  \n" +
+				"-  -  \n" +
+				"x     \n" +
+				"(1 rows)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := FormatTable(&buf, tt.result); err != nil {
+				t.Fatalf("FormatTable error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("unexpected table:\ngot:\n%q\nwant:\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *QueryResult
+		want   string
+	}{
+		{
+			name: "basic",
+			result: &QueryResult{
+				Columns: []string{"id", "name"},
+				Rows:    [][]any{{1, "alice"}},
+			},
+			want: "id,name\n1,alice\n",
+		},
+		{
+			name: "nil and quoting",
+			result: &QueryResult{
+				Columns: []string{"id", "note"},
+				Rows:    [][]any{{nil, "a,b"}, {2, `say "hi"`}},
+			},
+			want: "id,note\n,\"a,b\"\n2,\"say \"\"hi\"\"\"\n",
+		},
+		{
+			name: "null versus empty string",
+			result: &QueryResult{
+				Columns: []string{"a", "b"},
+				Rows:    [][]any{{nil, ""}, {" padded", "line\nbreak"}},
+			},
+			want: "a,b\n,\"\"\n\" padded\",\"line\nbreak\"\n",
+		},
+		{
+			name: "unicode",
+			result: &QueryResult{
+				Columns: []string{"city"},
+				Rows:    [][]any{{"東京"}},
+			},
+			want: "city\n東京\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := FormatCSV(&buf, tt.result); err != nil {
+				t.Fatalf("FormatCSV error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("unexpected csv:\ngot:  %q\nwant: %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	result := &QueryResult{
+		Columns:      []string{"id", "note"},
+		Rows:         [][]any{{1, nil}},
+		RowsAffected: 1,
+	}
+
+	var buf bytes.Buffer
+	if err := FormatJSON(&buf, result); err != nil {
+		t.Fatalf("FormatJSON error: %v", err)
+	}
+
+	var decoded QueryResult
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded.Columns) != 2 || len(decoded.Rows) != 1 || decoded.RowsAffected != 1 {
+		t.Fatalf("unexpected decoded result: %+v", decoded)
+	}
+	if !strings.Contains(buf.String(), "null") || decoded.Rows[0][1] != nil {
+		t.Fatalf("expected null to round-trip as nil, got %v", decoded.Rows[0][1])
+	}
+}
+
+func TestFormat(t *testing.T) {
+	result := &QueryResult{Columns: []string{"id"}, Rows: [][]any{{1}}}
+
+	for _, format := range []string{"", "table", "JSON", "csv"} {
+		var buf bytes.Buffer
+		if err := Format(&buf, result, format); err != nil {
+			t.Fatalf("Format(%q) error: %v", format, err)
+		}
+		if buf.Len() == 0 {
+			t.Fatalf("Format(%q) produced no output", format)
+		}
+	}
+
+	if err := Format(&bytes.Buffer{}, result, "yaml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestFormatValueUsesColumnType(t *testing.T) {
+	ts := time.Date(2024, 3, 5, 14, 30, 0, 500000000, time.UTC)
+	var numeric pgtype.Numeric
+	if err := numeric.Scan("12.50"); err != nil {
+		t.Fatalf("scan numeric: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		v    any
+		typ  string
+		want string
+	}{
+		{name: "bytea", v: []byte{0xde, 0xad}, typ: "bytea", want: `\xdead`},
+		{name: "text bytes", v: []byte("hi"), typ: "", want: "hi"},
+		{name: "date", v: ts, typ: "date", want: "2024-03-05"},
+		{name: "timestamp", v: ts, typ: "timestamp", want: "2024-03-05 14:30:00.5"},
+		{name: "timestamptz", v: ts, typ: "timestamptz", want: "2024-03-05 14:30:00.5Z"},
+		{name: "uuid", v: [16]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 1, 2, 3, 4, 5, 6, 7, 8}, typ: "uuid", want: "12345678-9abc-def0-0102-030405060708"},
+		{name: "numeric", v: numeric, typ: "numeric", want: "12.50"},
+		{name: "int", v: int32(7), typ: "int4", want: "7"},
+	}
+
+	for _, tt := range tests {
+		if got := formatValue(tt.v, tt.typ); got != tt.want {
+			t.Fatalf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	result := &QueryResult{Columns: []string{"data"}, ColumnTypes: []string{"bytea"}, Rows: [][]any{{[]byte{0x01}}}}
+	if err := FormatCSV(&buf, result); err != nil || !strings.Contains(buf.String(), `\x01`) {
+		t.Fatalf("expected CSV to render bytea as hex, got %q (%v)", buf.String(), err)
+	}
+}
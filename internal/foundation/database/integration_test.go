@@ -0,0 +1,59 @@
+//go:build integration
+
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+func TestConcurrentMigrationsSerialize(t *testing.T) {
+	ctx := context.Background()
+
+	infra, err := containers.StartEmbedded(ctx, containers.DefaultConfig())
+	if err != nil {
+		t.Fatalf("start embedded postgres: %v", err)
+	}
+	defer containers.CleanupInfrastructure(infra)
+
+	pool, err := NewPool(ctx, infra.PostgresURL)
+	if err != nil {
+		t.Fatalf("open pool: %v", err)
+	}
+	defer pool.Close()
+
+	// Plain CREATE TABLE fails if two runners apply it
+	fsys := fstest.MapFS{
+		"migrations/001_first.sql":  {Data: []byte(`CREATE TABLE first (id INT)`)},
+		"migrations/002_second.sql": {Data: []byte(`CREATE TABLE second (id INT); SELECT pg_sleep(0.2)`)},
+	}
+
+	const runners = 4
+	errs := make([]error, runners)
+	var wg sync.WaitGroup
+	for i := range runners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = RunMigrationsFS(ctx, pool, fsys, "migrations")
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("runner %d: %v", i, err)
+		}
+	}
+
+	var count int
+	if err := pool.QueryRow(ctx, `SELECT count(*) FROM schema_migrations`).Scan(&count); err != nil {
+		t.Fatalf("count schema_migrations: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 recorded migrations, got %d", count)
+	}
+}
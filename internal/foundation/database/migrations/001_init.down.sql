@@ -0,0 +1,6 @@
+-- Revert 001_init. The vector extension is left installed; other schemas may use it.
+DROP TABLE IF EXISTS curriculum;
+DROP TABLE IF EXISTS user_progress;
+DROP TABLE IF EXISTS concept_mastery;
+DROP TABLE IF EXISTS question_bank;
+DROP TABLE IF EXISTS users;
@@ -3,22 +3,31 @@ package db
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
-// NewPool creates a pgx connection pool with sane defaults.
+// NewPool creates a pgx connection pool with sane defaults. Every query on
+// the pool emits a db.query span.
 func NewPool(ctx context.Context, url string) (*pgxpool.Pool, error) {
 	cfg, err := pgxpool.ParseConfig(url)
 	if err != nil {
@@ -29,12 +38,45 @@ func NewPool(ctx context.Context, url string) (*pgxpool.Pool, error) {
 	cfg.MaxConnLifetime = 55 * time.Minute
 	cfg.MaxConnIdleTime = 5 * time.Minute
 	cfg.HealthCheckPeriod = 30 * time.Second
+	cfg.ConnConfig.Tracer = telemetry.NewQueryTracer()
 	return pgxpool.NewWithConfig(ctx, cfg)
 }
 
 // RunMigrations applies embedded SQL migrations in order.
 func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	migrations, err := loadMigrations()
+	return RunMigrationsFS(ctx, pool, migrationFiles, "migrations")
+}
+
+// RunMigrationsFS applies the NNN_name.sql migrations in dir of fsys with
+// DefaultMigrationTimeout; see Migrate. Every set shares schema_migrations
+// with the embedded migrations, so its versions must not overlap theirs (or
+// another set's); a version already recorded is skipped as applied. Use
+// RollbackMigrationFS and MigrationsFS with the same fsys and dir.
+func RunMigrationsFS(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, dir string) error {
+	return Migrate(ctx, pool, MigrationConfig{FS: fsys, Dir: dir, StatementTimeout: DefaultMigrationTimeout})
+}
+
+// DefaultMigrationTimeout bounds each migration run by RunMigrations and
+// RunMigrationsFS
+const DefaultMigrationTimeout = 5 * time.Minute
+
+// MigrationConfig selects the migrations Migrate applies
+type MigrationConfig struct {
+	FS               fs.FS         // Source of NNN_name.sql files; nil uses the embedded migrations
+	Dir              string        // Directory within FS ("." for its root)
+	StatementTimeout time.Duration // Per-migration statement_timeout; zero keeps the server's setting
+}
+
+// Migrate applies the migrations in cfg, in version order, within one
+// transaction. Versions already recorded in schema_migrations are skipped,
+// so callers can ship their own migration sets with the same tracking as the
+// embedded defaults. Concurrent runners (such as replicas starting together)
+// serialize on an advisory lock. Each migration runs in a db.migration span.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, cfg MigrationConfig) error {
+	if cfg.FS == nil {
+		cfg.FS, cfg.Dir = migrationFiles, "migrations"
+	}
+	migrations, err := loadMigrationsFS(cfg.FS, cfg.Dir)
 	if err != nil {
 		return err
 	}
@@ -42,11 +84,21 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 		return nil
 	}
 
-	if err := ensureSchemaTable(ctx, pool); err != nil {
-		return err
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin migration tx: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	applied, err := appliedVersions(ctx, pool)
+	// Read the applied set only once the lock is held, so a runner that
+	// waited on another skips the versions it just applied
+	if err := lockMigrations(ctx, tx); err != nil {
+		return err
+	}
+	if err := ensureSchemaTable(ctx, tx); err != nil {
+		return err
+	}
+	applied, err := appliedVersions(ctx, tx)
 	if err != nil {
 		return err
 	}
@@ -55,21 +107,34 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 		appliedSet[v] = struct{}{}
 	}
 
-	tx, err := pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("begin migration tx: %w", err)
+	// Set after the lock so waiting on another runner is not bounded
+	if cfg.StatementTimeout > 0 {
+		ms := strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+		if _, err := tx.Exec(ctx, `SELECT set_config('statement_timeout', $1, true)`, ms); err != nil {
+			return fmt.Errorf("set migration timeout: %w", err)
+		}
 	}
-	defer tx.Rollback(ctx)
 
+	dbTracer := telemetry.NewDBTracer()
 	for _, m := range migrations {
 		if _, seen := appliedSet[m.Version]; seen {
 			continue
 		}
-		if _, err := tx.Exec(ctx, m.Content); err != nil {
-			return fmt.Errorf("apply migration %d: %w", m.Version, err)
-		}
-		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.Version); err != nil {
-			return fmt.Errorf("record migration %d: %w", m.Version, err)
+		err := dbTracer.TraceMigration(ctx, m.Version, func(ctx context.Context) error {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("db.migration.name", m.Name))
+			if _, err := tx.Exec(ctx, m.Content); err != nil {
+				if isQueryCanceled(err) && cfg.StatementTimeout > 0 {
+					return fmt.Errorf("apply migration %d: exceeded %s timeout: %w", m.Version, cfg.StatementTimeout, err)
+				}
+				return fmt.Errorf("apply migration %d: %w", m.Version, err)
+			}
+			if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, m.Version); err != nil {
+				return fmt.Errorf("record migration %d: %w", m.Version, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 	}
 
@@ -79,22 +144,189 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 	return nil
 }
 
+// queryCanceled is the SQLSTATE Postgres reports when statement_timeout fires
+const queryCanceled = "57014"
+
+func isQueryCanceled(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == queryCanceled
+}
+
+// RollbackMigration reverts applied migrations newer than toVersion, newest
+// first, by running their NNN_name.down.sql scripts in one transaction.
+// toVersion must be 0 (revert everything) or an applied version, and every
+// version to revert must have a down script; otherwise nothing is changed.
+func RollbackMigration(ctx context.Context, pool *pgxpool.Pool, toVersion int) error {
+	return RollbackMigrationFS(ctx, pool, migrationFiles, "migrations", toVersion)
+}
+
+// RollbackMigrationFS is RollbackMigration for the migrations in dir of fsys,
+// as applied by RunMigrationsFS. Every applied version above toVersion must
+// belong to that set, so a later version from another set stops the rollback.
+func RollbackMigrationFS(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, dir string, toVersion int) error {
+	migrations, err := loadMigrationsFS(fsys, dir)
+	if err != nil {
+		return err
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin rollback tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockMigrations(ctx, tx); err != nil {
+		return err
+	}
+	if err := ensureSchemaTable(ctx, tx); err != nil {
+		return err
+	}
+	applied, err := appliedVersions(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := rollbackPlan(migrations, applied, toVersion)
+	if err != nil {
+		return err
+	}
+	if len(plan) == 0 {
+		return nil
+	}
+
+	for _, m := range plan {
+		if _, err := tx.Exec(ctx, m.Down); err != nil {
+			return fmt.Errorf("roll back migration %d: %w", m.Version, err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+			return fmt.Errorf("unrecord migration %d: %w", m.Version, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit rollback: %w", err)
+	}
+	return nil
+}
+
+// rollbackPlan returns the applied migrations above toVersion, newest first
+func rollbackPlan(migrations []migration, applied []int, toVersion int) ([]migration, error) {
+	if toVersion < 0 {
+		return nil, fmt.Errorf("invalid rollback target %d", toVersion)
+	}
+	byVersion := make(map[int]migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	if toVersion > 0 && !slices.Contains(applied, toVersion) {
+		return nil, fmt.Errorf("cannot roll back to migration %d: it has not been applied", toVersion)
+	}
+
+	var plan []migration
+	for _, v := range applied {
+		if v <= toVersion {
+			continue
+		}
+		m, ok := byVersion[v]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %d has no migration file in this set", v)
+		}
+		if m.Down == "" {
+			return nil, fmt.Errorf("migration %d has no down script (%s.down.sql)", v, m.Name)
+		}
+		plan = append(plan, m)
+	}
+	sort.Slice(plan, func(i, j int) bool { return plan[i].Version > plan[j].Version })
+	return plan, nil
+}
+
+// MigrationInfo identifies an embedded migration.
+type MigrationInfo struct {
+	Version int
+	Name    string
+}
+
+// Migrations returns the embedded migrations in ascending version order.
+func Migrations() ([]MigrationInfo, error) {
+	return MigrationsFS(migrationFiles, "migrations")
+}
+
+// MigrationsFS returns the migrations in dir of fsys in ascending version order.
+func MigrationsFS(fsys fs.FS, dir string) ([]MigrationInfo, error) {
+	migrations, err := loadMigrationsFS(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]MigrationInfo, len(migrations))
+	for i, m := range migrations {
+		infos[i] = MigrationInfo{Version: m.Version, Name: m.Name}
+	}
+	return infos, nil
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"` // nil while pending
+}
+
+// Applied reports whether the migration is recorded in schema_migrations.
+func (s MigrationStatus) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// CompareMigrations pairs known migrations with the applied_at times recorded
+// in schema_migrations, in ascending version order. Applied versions with no
+// migration file are included with an empty Name.
+func CompareMigrations(migrations []MigrationInfo, applied map[int]time.Time) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	known := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = true
+		st := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := applied[m.Version]; ok {
+			st.AppliedAt = &at
+		}
+		statuses = append(statuses, st)
+	}
+	for v, at := range applied {
+		if !known[v] {
+			statuses = append(statuses, MigrationStatus{Version: v, AppliedAt: &at})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
 type migration struct {
 	Version int
 	Name    string
 	Content string
+	Down    string // contents of NNN_name.down.sql, if present
 }
 
 var versionPattern = regexp.MustCompile(`^(\d+)_?.*\.sql$`)
 
+// downSuffix marks the rollback script paired with an up migration
+const downSuffix = ".down.sql"
+
 func loadMigrations() ([]migration, error) {
-	files, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	return loadMigrationsFS(migrationFiles, "migrations")
+}
+
+// loadMigrationsFS reads NNN_name.sql up migrations and their optional
+// NNN_name.down.sql rollbacks from dir ("." for the root of fsys), sorted
+// by version
+func loadMigrationsFS(fsys fs.FS, dir string) ([]migration, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
 	if err != nil {
 		return nil, fmt.Errorf("list migrations: %w", err)
 	}
-	var migrations []migration
-	for _, path := range files {
-		base := filepath.Base(path)
+
+	byVersion := make(map[int]*migration)
+	downs := make(map[int]string)
+	for _, name := range files {
+		base := filepath.Base(name)
 		m := versionPattern.FindStringSubmatch(base)
 		if len(m) < 2 {
 			return nil, fmt.Errorf("invalid migration filename: %s", base)
@@ -103,22 +335,56 @@ func loadMigrations() ([]migration, error) {
 		if err != nil {
 			return nil, fmt.Errorf("parse version from %s: %w", base, err)
 		}
-		body, err := migrationFiles.ReadFile(path)
+		body, err := fs.ReadFile(fsys, name)
 		if err != nil {
 			return nil, fmt.Errorf("read migration %s: %w", base, err)
 		}
-		migrations = append(migrations, migration{
+
+		if strings.HasSuffix(base, downSuffix) {
+			downs[ver] = string(body)
+			continue
+		}
+		if _, dup := byVersion[ver]; dup {
+			return nil, fmt.Errorf("duplicate migration version %d: %s", ver, base)
+		}
+		byVersion[ver] = &migration{
 			Version: ver,
 			Name:    strings.TrimSuffix(base, ".sql"),
 			Content: string(body),
-		})
+		}
+	}
+
+	for ver, down := range downs {
+		m, ok := byVersion[ver]
+		if !ok {
+			return nil, fmt.Errorf("down migration %d has no matching up migration", ver)
+		}
+		m.Down = down
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
 	}
 	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
 	return migrations, nil
 }
 
-func ensureSchemaTable(ctx context.Context, pool *pgxpool.Pool) error {
-	_, err := pool.Exec(ctx, `
+// migrationLockKey identifies the advisory lock that serializes migration
+// runners across processes sharing a database
+const migrationLockKey int64 = 0x6169725f6d6967 // "air_mig"
+
+// lockMigrations blocks until tx holds the migration advisory lock; it is
+// released when tx commits or rolls back
+func lockMigrations(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	return nil
+}
+
+func ensureSchemaTable(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			version INT PRIMARY KEY,
 			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
@@ -130,8 +396,8 @@ func ensureSchemaTable(ctx context.Context, pool *pgxpool.Pool) error {
 	return nil
 }
 
-func appliedVersions(ctx context.Context, pool *pgxpool.Pool) ([]int, error) {
-	rows, err := pool.Query(ctx, `SELECT version FROM schema_migrations`)
+func appliedVersions(ctx context.Context, tx pgx.Tx) ([]int, error) {
+	rows, err := tx.Query(ctx, `SELECT version FROM schema_migrations`)
 	if err != nil {
 		return nil, fmt.Errorf("select schema_migrations: %w", err)
 	}
@@ -1,6 +1,11 @@
 package db
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
 
 func TestLoadMigrations(t *testing.T) {
 	migs, err := loadMigrations()
@@ -22,3 +27,134 @@ func TestLoadMigrations(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadMigrationsPairsDownScripts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/001_init.sql":       {Data: []byte("CREATE TABLE a ();")},
+		"m/001_init.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"m/002_more.sql":       {Data: []byte("CREATE TABLE b ();")},
+		"m/003_extra.down.sql": {Data: []byte("DROP TABLE c;")},
+	}
+	if _, err := loadMigrationsFS(fsys, "m"); err == nil || !strings.Contains(err.Error(), "no matching up migration") {
+		t.Fatalf("expected orphan down script error, got %v", err)
+	}
+
+	delete(fsys, "m/003_extra.down.sql")
+	migs, err := loadMigrationsFS(fsys, "m")
+	if err != nil {
+		t.Fatalf("loadMigrationsFS error: %v", err)
+	}
+	if len(migs) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migs))
+	}
+	if migs[0].Name != "001_init" || migs[0].Down != "DROP TABLE a;" {
+		t.Fatalf("unexpected first migration: %+v", migs[0])
+	}
+	if migs[1].Down != "" {
+		t.Fatalf("expected no down script for 002, got %q", migs[1].Down)
+	}
+}
+
+func TestLoadMigrationsFSRoot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_more.sql":     {Data: []byte("CREATE TABLE b ();")},
+		"001_init.sql":     {Data: []byte("CREATE TABLE a ();")},
+		"README.md":        {Data: []byte("not a migration")},
+		"nested/003_x.sql": {Data: []byte("CREATE TABLE c ();")},
+	}
+	for _, dir := range []string{".", ""} {
+		migs, err := loadMigrationsFS(fsys, dir)
+		if err != nil {
+			t.Fatalf("loadMigrationsFS(%q) error: %v", dir, err)
+		}
+		if len(migs) != 2 || migs[0].Version != 1 || migs[1].Version != 2 {
+			t.Fatalf("loadMigrationsFS(%q) = %+v, want versions 1 and 2", dir, migs)
+		}
+	}
+}
+
+func TestMigrationsFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/101_users.sql":      {Data: []byte("CREATE TABLE users ();")},
+		"app/101_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"app/102_orders.sql":     {Data: []byte("CREATE TABLE orders ();")},
+	}
+	infos, err := MigrationsFS(fsys, "app")
+	if err != nil {
+		t.Fatalf("MigrationsFS error: %v", err)
+	}
+	want := []MigrationInfo{{Version: 101, Name: "101_users"}, {Version: 102, Name: "102_orders"}}
+	if len(infos) != len(want) || infos[0] != want[0] || infos[1] != want[1] {
+		t.Fatalf("MigrationsFS = %+v, want %+v", infos, want)
+	}
+}
+
+func TestEmbeddedMigrationsHaveDownScripts(t *testing.T) {
+	migs, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations error: %v", err)
+	}
+	for _, m := range migs {
+		if m.Down == "" {
+			t.Fatalf("migration %s has no down script", m.Name)
+		}
+	}
+}
+
+func TestRollbackPlan(t *testing.T) {
+	migs := []migration{
+		{Version: 1, Name: "001_a", Down: "down 1"},
+		{Version: 2, Name: "002_b", Down: "down 2"},
+		{Version: 3, Name: "003_c"},
+	}
+
+	plan, err := rollbackPlan(migs, []int{1, 2}, 0)
+	if err != nil {
+		t.Fatalf("rollbackPlan error: %v", err)
+	}
+	if len(plan) != 2 || plan[0].Version != 2 || plan[1].Version != 1 {
+		t.Fatalf("expected descending [2 1], got %+v", plan)
+	}
+
+	plan, err = rollbackPlan(migs, []int{1, 2}, 2)
+	if err != nil || len(plan) != 0 {
+		t.Fatalf("expected empty plan at current version, got %+v, %v", plan, err)
+	}
+
+	cases := []struct {
+		name    string
+		applied []int
+		to      int
+		want    string
+	}{
+		{"never applied", []int{1}, 2, "has not been applied"},
+		{"negative", []int{1}, -1, "invalid rollback target"},
+		{"missing down", []int{1, 2, 3}, 1, "no down script"},
+		{"unknown applied", []int{1, 4}, 1, "no migration file"},
+	}
+	for _, tc := range cases {
+		if _, err := rollbackPlan(migs, tc.applied, tc.to); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestCompareMigrations(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	statuses := CompareMigrations(
+		[]MigrationInfo{{Version: 2, Name: "002_b"}, {Version: 1, Name: "001_a"}},
+		map[int]time.Time{1: at, 7: at},
+	)
+	if len(statuses) != 3 {
+		t.Fatalf("expected 3 statuses, got %+v", statuses)
+	}
+	if statuses[0].Version != 1 || !statuses[0].Applied() || !statuses[0].AppliedAt.Equal(at) {
+		t.Fatalf("expected 001 applied at %v, got %+v", at, statuses[0])
+	}
+	if statuses[1].Version != 2 || statuses[1].Applied() {
+		t.Fatalf("expected 002 pending, got %+v", statuses[1])
+	}
+	if statuses[2].Version != 7 || statuses[2].Name != "" || !statuses[2].Applied() {
+		t.Fatalf("expected unknown applied version 7, got %+v", statuses[2])
+	}
+}
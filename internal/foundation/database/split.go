@@ -0,0 +1,146 @@
+package db
+
+import (
+	"strings"
+)
+
+// Statement is one SQL statement from a script.
+type Statement struct {
+	SQL  string // statement text without the terminating semicolon
+	Line int    // 1-based line where the statement starts
+}
+
+// SplitStatements splits a SQL script on top-level semicolons. Semicolons
+// inside quoted strings and identifiers (including E-prefixed strings with
+// backslash escapes), dollar-quoted bodies ($$ or $tag$) and comments do not
+// end a statement. Statements containing only whitespace and comments are
+// dropped.
+func SplitStatements(script string) []Statement {
+	var (
+		statements []Statement
+		start      int  // byte offset of the current statement's first code byte
+		startLine  int  // line of that byte
+		hasCode    bool // current statement contains more than comments
+		line       = 1
+	)
+
+	var i int
+	markCode := func() {
+		if !hasCode {
+			hasCode = true
+			start, startLine = i, line
+		}
+	}
+
+	for i = 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '\n':
+			line++
+
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				i = len(script)
+				continue
+			}
+			i += end - 1 // the newline is counted on the next iteration
+
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			// Block comments nest in PostgreSQL
+			depth := 1
+			i += 2
+			for ; i < len(script) && depth > 0; i++ {
+				switch {
+				case script[i] == '\n':
+					line++
+				case strings.HasPrefix(script[i:], "/*"):
+					depth++
+					i++
+				case strings.HasPrefix(script[i:], "*/"):
+					depth--
+					i++
+				}
+			}
+			i--
+
+		case c == '\'' || c == '"':
+			markCode()
+			backslashEscapes := c == '\'' && i > 0 && (script[i-1] == 'E' || script[i-1] == 'e') &&
+				(i < 2 || !isIdentByte(script[i-2]))
+			for i++; i < len(script); i++ {
+				ch := script[i]
+				if ch == '\n' {
+					line++
+				} else if backslashEscapes && ch == '\\' {
+					i++
+					if i < len(script) && script[i] == '\n' {
+						line++
+					}
+				} else if ch == c {
+					// A doubled quote is an escaped quote
+					if i+1 < len(script) && script[i+1] == c {
+						i++
+						continue
+					}
+					break
+				}
+			}
+
+		case c == '$':
+			markCode()
+			tag, ok := dollarTag(script[i:])
+			if !ok || (i > 0 && isIdentByte(script[i-1])) {
+				continue
+			}
+			end := strings.Index(script[i+len(tag):], tag)
+			if end < 0 {
+				end = len(script) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			line += strings.Count(script[i:i+len(tag)+end], "\n")
+			i += len(tag) + end - 1
+
+		case c == ';':
+			if hasCode {
+				statements = append(statements, Statement{
+					SQL:  strings.TrimSpace(script[start:i]),
+					Line: startLine,
+				})
+			}
+			hasCode = false
+
+		case c == ' ' || c == '\t' || c == '\r':
+
+		default:
+			markCode()
+		}
+	}
+
+	if hasCode {
+		statements = append(statements, Statement{
+			SQL:  strings.TrimSpace(script[start:]),
+			Line: startLine,
+		})
+	}
+	return statements
+}
+
+// dollarTag returns the opening "$tag$" at the start of s, if s starts one
+func dollarTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		switch {
+		case s[j] == '$':
+			return s[:j+1], true
+		case !isIdentByte(s[j]) || (j == 1 && s[j] >= '0' && s[j] <= '9'):
+			// $1 parameters and other uses of $ are not quotes
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
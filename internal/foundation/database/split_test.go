@@ -0,0 +1,60 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []Statement
+	}{
+		{
+			name:   "simple",
+			script: "SELECT 1;\nSELECT 2;",
+			want:   []Statement{{SQL: "SELECT 1", Line: 1}, {SQL: "SELECT 2", Line: 2}},
+		},
+		{
+			name:   "trailing statement without semicolon",
+			script: "UPDATE t SET a = 1;\n\nDELETE FROM t",
+			want:   []Statement{{SQL: "UPDATE t SET a = 1", Line: 1}, {SQL: "DELETE FROM t", Line: 3}},
+		},
+		{
+			name:   "semicolons in strings and identifiers",
+			script: `INSERT INTO "odd;name" VALUES ('a;b', 'it''s;', E'x\';y');SELECT 1`,
+			want: []Statement{
+				{SQL: `INSERT INTO "odd;name" VALUES ('a;b', 'it''s;', E'x\';y')`, Line: 1},
+				{SQL: "SELECT 1", Line: 1},
+			},
+		},
+		{
+			name:   "comments",
+			script: "-- setup; not a statement\n/* block; /* nested; */ still comment; */\nSELECT 1; -- trailing;\n-- only a comment;\n",
+			want:   []Statement{{SQL: "SELECT 1", Line: 3}},
+		},
+		{
+			name: "dollar quoted function body",
+			script: "CREATE FUNCTION f() RETURNS int AS $body$\nBEGIN\n  RETURN 1;\nEND;\n$body$ LANGUAGE plpgsql;\n" +
+				"SELECT $1::int, $$a;b$$;",
+			want: []Statement{
+				{SQL: "CREATE FUNCTION f() RETURNS int AS $body$\nBEGIN\n  RETURN 1;\nEND;\n$body$ LANGUAGE plpgsql", Line: 1},
+				{SQL: "SELECT $1::int, $$a;b$$", Line: 6},
+			},
+		},
+		{
+			name:   "empty",
+			script: " ;\n;  -- nothing\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("unexpected statements:\ngot:  %#v\nwant: %#v", got, tt.want)
+			}
+		})
+	}
+}
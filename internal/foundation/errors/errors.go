@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 )
@@ -98,6 +99,30 @@ func Is(err error, code string) bool {
 	return false
 }
 
+// Code returns the code of the first AppError in err's chain, or
+// ErrCodeInternal when there is none
+func Code(err error) string {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ErrCodeInternal
+}
+
+// FromJSON reconstructs an AppError from its JSON form, such as an error
+// payload received from a server. The underlying error is never serialized,
+// so the result has none; numeric detail values decode as float64.
+func FromJSON(b []byte) (*AppError, error) {
+	var appErr AppError
+	if err := json.Unmarshal(b, &appErr); err != nil {
+		return nil, fmt.Errorf("decode app error: %w", err)
+	}
+	if appErr.Code == "" {
+		return nil, fmt.Errorf("decode app error: missing code")
+	}
+	return &appErr, nil
+}
+
 // Common error constructors
 
 func InvalidEnvelope(message string) *AppError {
@@ -162,3 +187,12 @@ func RateLimited(resource string) *AppError {
 func Internal(err error) *AppError {
 	return Wrap(err, ErrCodeInternal, "internal server error")
 }
+
+// FromPanic converts a recovered panic value into an internal error.
+// The panic value is kept as the underlying error, which is not serialized to JSON.
+func FromPanic(v interface{}) *AppError {
+	if err, ok := v.(error); ok {
+		return Internal(fmt.Errorf("panic: %w", err))
+	}
+	return Internal(fmt.Errorf("panic: %v", v))
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromJSONRoundTrip(t *testing.T) {
+	cause := errors.New("connection refused: 10.0.0.5:5432")
+	original := DatabaseQuery(cause, "SELECT 1").
+		WithDetail("attempts", 3).
+		WithRequestID("req-42")
+
+	b, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(b), "connection refused") {
+		t.Fatalf("underlying error leaked into JSON: %s", b)
+	}
+
+	decoded, err := FromJSON(b)
+	if err != nil {
+		t.Fatalf("FromJSON: %v", err)
+	}
+	if decoded.Code != ErrCodeDatabaseQuery || decoded.Message != original.Message || decoded.RequestID != "req-42" {
+		t.Fatalf("decoded = %+v, want code, message and request ID of %+v", decoded, original)
+	}
+	wantDetails := map[string]interface{}{"query": "SELECT 1", "attempts": float64(3)}
+	if !reflect.DeepEqual(decoded.Details, wantDetails) {
+		t.Fatalf("details = %v, want %v", decoded.Details, wantDetails)
+	}
+	if decoded.Unwrap() != nil {
+		t.Fatalf("expected no underlying error after decoding, got %v", decoded.Unwrap())
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("re-marshal: %v", err)
+	}
+	if string(again) != string(b) {
+		t.Fatalf("round trip changed payload:\n got %s\nwant %s", again, b)
+	}
+}
+
+func TestFromJSONInvalid(t *testing.T) {
+	for _, payload := range []string{`not json`, `{"message":"no code"}`} {
+		if _, err := FromJSON([]byte(payload)); err == nil {
+			t.Fatalf("FromJSON(%s): expected error", payload)
+		}
+	}
+}
+
+func TestCode(t *testing.T) {
+	wrapped := fmt.Errorf("handle ping: %w", TokenMissing())
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{NotFound("user"), ErrCodeNotFound},
+		{wrapped, ErrCodeTokenMissing},
+		{Wrap(RateLimited("api"), ErrCodeUnauthorized, "outer"), ErrCodeUnauthorized},
+		{errors.New("plain"), ErrCodeInternal},
+		{nil, ErrCodeInternal},
+	}
+	for _, tc := range cases {
+		if got := Code(tc.err); got != tc.want {
+			t.Fatalf("Code(%v) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+}
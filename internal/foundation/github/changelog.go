@@ -0,0 +1,135 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// GenerateChangelog lists the commits reachable from toRef but not from
+// fromTag (git log fromTag..toRef), newest first by committer time, as
+// "- subject (shorthash)" lines. Commits merged in from a branch that forked
+// before fromTag are included; anything fromTag already contains is not. An
+// empty fromTag includes the whole history; toRef defaults to HEAD.
+func GenerateChangelog(repo *git.Repository, fromTag, toRef string) (string, error) {
+	if toRef == "" {
+		toRef = "HEAD"
+	}
+	to, err := repo.ResolveRevision(plumbing.Revision(toRef))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", toRef, err)
+	}
+
+	released := map[plumbing.Hash]bool{}
+	if fromTag != "" {
+		tagged, err := tagCommit(repo, fromTag)
+		if err != nil {
+			return "", err
+		}
+		if tagged.IsZero() {
+			return "", fmt.Errorf("tag %s not found", fromTag)
+		}
+		if released, err = ancestors(repo, tagged); err != nil {
+			return "", err
+		}
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: *to, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	var b strings.Builder
+	err = iter.ForEach(func(c *object.Commit) error {
+		if released[c.Hash] {
+			return nil
+		}
+		subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		fmt.Fprintf(&b, "- %s (%s)\n", subject, c.Hash.String()[:7])
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to walk history: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// ancestors returns from and every commit reachable from it, along all
+// parents
+func ancestors(repo *git.Repository, from plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	iter, err := repo.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	seen := make(map[plumbing.Hash]bool)
+	err = iter.ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk history: %w", err)
+	}
+	return seen, nil
+}
+
+// PreviousTag returns the most recent tag reachable from HEAD other than
+// exclude (typically the tag being released), or "" if there is none
+func PreviousTag(repo *git.Repository, exclude string) (string, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	byCommit := make(map[plumbing.Hash]string)
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if name == exclude {
+			return nil
+		}
+		commit, err := tagCommit(repo, name)
+		if err != nil {
+			return err
+		}
+		byCommit[commit] = name
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(byCommit) == 0 {
+		return "", nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	// Newest first, so a tag on a merged branch beats an older first-parent one
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return "", fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	var previous string
+	err = iter.ForEach(func(c *object.Commit) error {
+		if name, ok := byCommit[c.Hash]; ok {
+			previous = name
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return "", fmt.Errorf("failed to walk history: %w", err)
+	}
+	return previous, nil
+}
@@ -0,0 +1,135 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGenerateChangelog(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	p := &Publisher{repo: repo}
+
+	commitFile(t, repo, dir, "Initial import")
+	if err := p.CreateTag(ReleaseConfig{Tag: "v0.1.0", Message: "first", AuthorName: "test", AuthorEmail: "test@example.com"}); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	fix := commitFile(t, repo, dir, "Fix login redirect\n\nLonger body that is not included")
+	feat := commitFile(t, repo, dir, "Add search")
+
+	previous, err := PreviousTag(repo, "v0.2.0")
+	if err != nil || previous != "v0.1.0" {
+		t.Fatalf("expected previous tag v0.1.0, got %q (%v)", previous, err)
+	}
+
+	got, err := GenerateChangelog(repo, previous, "HEAD")
+	if err != nil {
+		t.Fatalf("GenerateChangelog: %v", err)
+	}
+	want := "- Add search (" + feat.String()[:7] + ")\n- Fix login redirect (" + fix.String()[:7] + ")\n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	all, err := GenerateChangelog(repo, "", "")
+	if err != nil || !strings.Contains(all, "Initial import") {
+		t.Fatalf("expected full history without fromTag, got %q (%v)", all, err)
+	}
+
+	if _, err := GenerateChangelog(repo, "v9.9.9", "HEAD"); err == nil {
+		t.Fatal("expected error for unknown tag")
+	}
+}
+
+// storeCommit writes a commit with an empty tree and the given parents
+func storeCommit(t *testing.T, repo *git.Repository, message string, when time.Time, parents ...plumbing.Hash) plumbing.Hash {
+	t.Helper()
+	tree := repo.Storer.NewEncodedObject()
+	if err := (&object.Tree{}).Encode(tree); err != nil {
+		t.Fatalf("encode tree: %v", err)
+	}
+	treeHash, err := repo.Storer.SetEncodedObject(tree)
+	if err != nil {
+		t.Fatalf("store tree: %v", err)
+	}
+
+	sig := object.Signature{Name: "test", Email: "test@example.com", When: when}
+	commit := &object.Commit{Author: sig, Committer: sig, Message: message, TreeHash: treeHash, ParentHashes: parents}
+	obj := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		t.Fatalf("encode commit: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("store commit: %v", err)
+	}
+	return hash
+}
+
+func TestGenerateChangelogIncludesMergedBranches(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	// base - tagged(v1) ------------- merge (HEAD)
+	//  \                              /
+	//   branch (forked before v1) ---
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := storeCommit(t, repo, "Initial import", start)
+	tagged := storeCommit(t, repo, "Release prep", start.Add(time.Hour), base)
+	branch := storeCommit(t, repo, "Add export", start.Add(2*time.Hour), base)
+	merge := storeCommit(t, repo, "Merge export", start.Add(3*time.Hour), tagged, branch)
+	if _, err := repo.CreateTag("v1", tagged, nil); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+
+	got, err := GenerateChangelog(repo, "v1", merge.String())
+	if err != nil {
+		t.Fatalf("GenerateChangelog: %v", err)
+	}
+	want := "- Merge export (" + merge.String()[:7] + ")\n- Add export (" + branch.String()[:7] + ")\n"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestPreviousTagPrefersNewestAcrossMerges(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	// old(v1) - main ---------- merge (HEAD)
+	//   \                     /
+	//    branch(v2, newer) --
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := storeCommit(t, repo, "Initial import", start)
+	main := storeCommit(t, repo, "Main work", start.Add(time.Hour), old)
+	branch := storeCommit(t, repo, "Branch release", start.Add(2*time.Hour), old)
+	merge := storeCommit(t, repo, "Merge branch", start.Add(3*time.Hour), main, branch)
+	for name, hash := range map[string]plumbing.Hash{"v1": old, "v2": branch} {
+		if _, err := repo.CreateTag(name, hash, nil); err != nil {
+			t.Fatalf("tag %s: %v", name, err)
+		}
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("master"), merge)); err != nil {
+		t.Fatalf("set HEAD: %v", err)
+	}
+
+	got, err := PreviousTag(repo, "v3")
+	if err != nil {
+		t.Fatalf("PreviousTag: %v", err)
+	}
+	if got != "v2" {
+		t.Fatalf("expected the newest reachable tag v2, got %q", got)
+	}
+}
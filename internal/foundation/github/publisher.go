@@ -3,18 +3,32 @@ package github
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
 
+// ErrTagExists is returned by CreateTag when the tag already points at a
+// different commit and ReleaseConfig.ForceTag is not set
+var ErrTagExists = errors.New("tag already exists")
+
 // RepositoryConfig contains configuration for creating a GitHub repository
 type RepositoryConfig struct {
-	Owner       string
+	Owner       string // Defaults to the remote URL's owner, then the authenticated user
 	Name        string
 	Description string
 	Private     bool
@@ -26,15 +40,38 @@ type RepositoryConfig struct {
 // ReleaseConfig contains configuration for creating a release
 type ReleaseConfig struct {
 	Tag         string
-	Message     string
+	Message     string // Defaults to a changelog of the commits since the previous tag
 	AuthorName  string
 	AuthorEmail string
+
+	// GitHub release; Publish fills Owner and Repo from the repository config
+	Owner      string
+	Repo       string
+	Name       string // Release title; defaults to Tag
+	Body       string // Release notes; defaults to Message
+	Draft      bool
+	Prerelease bool
+
+	// ForceTag moves an existing tag to the current HEAD (delete + recreate)
+	// and force-pushes it. Without it an existing tag on another commit is an error.
+	ForceTag bool
+}
+
+// AuthOptions selects credentials for git pushes. Token is used for HTTPS
+// remotes and SSHKeyPath for SSH remotes; when the matching one is empty
+// go-git's defaults apply (ssh-agent for SSH, anonymous for HTTPS).
+type AuthOptions struct {
+	Token            string // GitHub token sent as HTTP basic auth
+	SSHKeyPath       string // Private key file, e.g. ~/.ssh/id_ed25519
+	SSHKeyPassphrase string
 }
 
 // Publisher handles GitHub repository publishing operations
 type Publisher struct {
-	client *api.RESTClient
-	repo   *git.Repository
+	client  *api.RESTClient
+	uploads *http.Client // Authenticated client for release asset uploads, which are not JSON
+	repo    *git.Repository
+	auth    transport.AuthMethod
 }
 
 // NewPublisher creates a new GitHub publisher
@@ -50,13 +87,105 @@ func NewPublisher(repoPath string) (*Publisher, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitHub client (authenticate with: gh auth login): %w", err)
 	}
+	uploads, err := api.DefaultHTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client (authenticate with: gh auth login): %w", err)
+	}
 
 	return &Publisher{
-		client: client,
-		repo:   repo,
+		client:  client,
+		uploads: uploads,
+		repo:    repo,
 	}, nil
 }
 
+// CurrentUser returns the login of the authenticated GitHub user
+func (p *Publisher) CurrentUser() (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := p.client.Get("user", &user); err != nil {
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+	if user.Login == "" {
+		return "", fmt.Errorf("failed to get authenticated user: empty login")
+	}
+	return user.Login, nil
+}
+
+// RemoteRepository returns the owner and repository name of a git remote's URL
+func (p *Publisher) RemoteRepository(remote string) (owner, name string, err error) {
+	r, err := p.repo.Remote(remote)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get remote %s: %w", remote, err)
+	}
+	urls := r.Config().URLs
+	if len(urls) == 0 {
+		return "", "", fmt.Errorf("remote %s has no URL", remote)
+	}
+	return ParseRemoteURL(urls[0])
+}
+
+// ResolveOwner picks the repository owner from the remote URL, falling back
+// to the authenticated user when the remote does not exist yet
+func (p *Publisher) ResolveOwner(remote string) (string, error) {
+	if owner, _, err := p.RemoteRepository(remote); err == nil {
+		return owner, nil
+	}
+	return p.CurrentUser()
+}
+
+// EnsureRemote adds remote pointing at github.com/owner/name if it does not exist
+func (p *Publisher) EnsureRemote(remote, owner, name string) error {
+	if _, err := p.repo.Remote(remote); err == nil {
+		return nil
+	} else if !errors.Is(err, git.ErrRemoteNotFound) {
+		return fmt.Errorf("failed to get remote %s: %w", remote, err)
+	}
+
+	_, err := p.repo.CreateRemote(&config.RemoteConfig{
+		Name: remote,
+		URLs: []string{fmt.Sprintf("https://github.com/%s/%s.git", owner, name)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create remote %s: %w", remote, err)
+	}
+	return nil
+}
+
+// ParseRemoteURL extracts owner and repository name from a GitHub remote URL.
+// HTTPS (https://github.com/o/r.git), SSH (ssh://git@github.com/o/r) and
+// scp-like (git@github.com:o/r.git) forms are supported.
+func ParseRemoteURL(raw string) (owner, name string, err error) {
+	path := raw
+	if u, perr := url.Parse(raw); perr == nil && u.Scheme != "" && u.Host != "" {
+		path = u.Path
+	} else if i := strings.Index(raw, ":"); i >= 0 {
+		path = raw[i+1:]
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("cannot parse owner/name from remote URL %q", raw)
+	}
+	return parts[0], parts[1], nil
+}
+
+// RepositoryExists reports whether owner/name exists and is visible to the
+// authenticated user. A 404 is reported as (false, nil); any other failure
+// (bad credentials, rate limiting, network) is returned as an error.
+func (p *Publisher) RepositoryExists(owner, name string) (bool, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s", owner, name)
+	if err := p.client.Get(endpoint, nil); err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check repository %s/%s: %w", owner, name, err)
+	}
+	return true, nil
+}
+
 // CreateRepository creates a new GitHub repository
 func (p *Publisher) CreateRepository(cfg RepositoryConfig) error {
 	repoData := map[string]interface{}{
@@ -99,12 +228,64 @@ func (p *Publisher) AddTopics(owner, repo string, topics []string) error {
 	return nil
 }
 
+// SetAuth configures push credentials for remote from opts
+func (p *Publisher) SetAuth(remote string, opts AuthOptions) error {
+	r, err := p.repo.Remote(remote)
+	if err != nil {
+		return fmt.Errorf("failed to get remote %s: %w", remote, err)
+	}
+	var remoteURL string
+	if urls := r.Config().URLs; len(urls) > 0 {
+		remoteURL = urls[0]
+	}
+
+	auth, err := authMethod(remoteURL, opts)
+	if err != nil {
+		return err
+	}
+	p.auth = auth
+	return nil
+}
+
+// authMethod builds the go-git auth for remoteURL, or nil to use go-git's defaults
+func authMethod(remoteURL string, opts AuthOptions) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(remoteURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse remote URL: %w", err)
+	}
+
+	switch endpoint.Protocol {
+	case "ssh":
+		if opts.SSHKeyPath == "" {
+			return nil, nil
+		}
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		keys, err := gitssh.NewPublicKeysFromFile(user, opts.SSHKeyPath, opts.SSHKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load SSH key %s: %w", opts.SSHKeyPath, err)
+		}
+		return keys, nil
+	case "http", "https":
+		if opts.Token == "" {
+			return nil, nil
+		}
+		// GitHub accepts any non-empty username alongside a token
+		return &githttp.BasicAuth{Username: "x-access-token", Password: opts.Token}, nil
+	default:
+		return nil, nil
+	}
+}
+
 // PushCode pushes code to GitHub
 func (p *Publisher) PushCode(remote, branch string) error {
 	refSpec := config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/heads/%s", branch, branch))
 	err := p.repo.Push(&git.PushOptions{
 		RemoteName: remote,
 		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       p.auth,
 	})
 
 	if err != nil && err != git.NoErrAlreadyUpToDate {
@@ -114,13 +295,44 @@ func (p *Publisher) PushCode(remote, branch string) error {
 	return nil
 }
 
-// CreateTag creates a git tag
+// CurrentBranch returns the short name of the branch HEAD points to. A
+// detached HEAD is an error, since there is no branch to push.
+func CurrentBranch(repo *git.Repository) (string, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("HEAD is detached at %s; check out a branch or set the branch explicitly", head.Hash())
+	}
+	return head.Name().Short(), nil
+}
+
+// CreateTag creates a git tag at HEAD. An existing tag already at HEAD is
+// left as is; one at another commit is moved when cfg.ForceTag is set and
+// reported as ErrTagExists otherwise.
 func (p *Publisher) CreateTag(cfg ReleaseConfig) error {
 	head, err := p.repo.Head()
 	if err != nil {
 		return fmt.Errorf("failed to get HEAD: %w", err)
 	}
 
+	existing, err := tagCommit(p.repo, cfg.Tag)
+	if err != nil {
+		return err
+	}
+	if existing == head.Hash() {
+		return nil
+	}
+	if !existing.IsZero() {
+		if !cfg.ForceTag {
+			return fmt.Errorf("%s: %w at %s", cfg.Tag, ErrTagExists, existing)
+		}
+		if err := p.repo.DeleteTag(cfg.Tag); err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+	}
+
 	_, err = p.repo.CreateTag(cfg.Tag, head.Hash(), &git.CreateTagOptions{
 		Tagger: &object.Signature{
 			Name:  cfg.AuthorName,
@@ -137,12 +349,46 @@ func (p *Publisher) CreateTag(cfg ReleaseConfig) error {
 	return nil
 }
 
+// tagCommit returns the commit a tag points at, or the zero hash if the tag does not exist
+func tagCommit(repo *git.Repository, tag string) (plumbing.Hash, error) {
+	ref, err := repo.Tag(tag)
+	if errors.Is(err, git.ErrTagNotFound) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to look up tag: %w", err)
+	}
+
+	// Annotated tags point at a tag object; lightweight tags point at the commit
+	obj, err := repo.TagObject(ref.Hash())
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return ref.Hash(), nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to read tag: %w", err)
+	}
+	return obj.Target, nil
+}
+
 // PushTag pushes a tag to GitHub
 func (p *Publisher) PushTag(remote, tag string) error {
+	return p.pushTag(remote, tag, false)
+}
+
+// ForcePushTag pushes a tag with +refs/tags/..., replacing the remote tag if it moved
+func (p *Publisher) ForcePushTag(remote, tag string) error {
+	return p.pushTag(remote, tag, true)
+}
+
+func (p *Publisher) pushTag(remote, tag string, force bool) error {
 	refSpec := config.RefSpec(fmt.Sprintf("refs/tags/%s:refs/tags/%s", tag, tag))
+	if force {
+		refSpec = "+" + refSpec
+	}
 	err := p.repo.Push(&git.PushOptions{
 		RemoteName: remote,
 		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       p.auth,
 	})
 
 	if err != nil && err != git.NoErrAlreadyUpToDate {
@@ -152,26 +398,197 @@ func (p *Publisher) PushTag(remote, tag string) error {
 	return nil
 }
 
+// release is the subset of the GitHub release object Publisher uses
+type release struct {
+	ID        int64  `json:"id"`
+	HTMLURL   string `json:"html_url"`
+	UploadURL string `json:"upload_url"`
+}
+
+// CreateRelease creates the GitHub release for cfg.Tag, which must already
+// be pushed, and uploads each asset file to it. A release that already
+// exists for the tag is reused.
+func (p *Publisher) CreateRelease(cfg ReleaseConfig, assets []string) error {
+	if err := checkAssets(assets); err != nil {
+		return err
+	}
+
+	rel, err := p.releaseByTag(cfg.Owner, cfg.Repo, cfg.Tag)
+	if err != nil {
+		return err
+	}
+	if rel != nil {
+		fmt.Printf("Release %s already exists, continuing\n", cfg.Tag)
+	} else {
+		rel, err = p.postRelease(cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, asset := range assets {
+		if err := p.uploadAsset(rel.UploadURL, asset); err != nil {
+			return err
+		}
+	}
+	if rel.HTMLURL != "" {
+		fmt.Printf("Release %s: %s\n", cfg.Tag, rel.HTMLURL)
+	}
+	return nil
+}
+
+// checkAssets verifies that every asset is an existing regular file, before
+// anything is created on GitHub
+func checkAssets(assets []string) error {
+	for _, asset := range assets {
+		info, err := os.Stat(asset)
+		if err != nil {
+			return fmt.Errorf("release asset %s: %w", asset, err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("release asset %s: not a regular file", asset)
+		}
+	}
+	return nil
+}
+
+// releaseByTag returns the release for tag, or nil if there is none
+func (p *Publisher) releaseByTag(owner, name, tag string) (*release, error) {
+	var rel release
+	endpoint := fmt.Sprintf("repos/%s/%s/releases/tags/%s", owner, name, url.PathEscape(tag))
+	if err := p.client.Get(endpoint, &rel); err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to look up release %s: %w", tag, err)
+	}
+	return &rel, nil
+}
+
+func (p *Publisher) postRelease(cfg ReleaseConfig) (*release, error) {
+	name := cfg.Name
+	if name == "" {
+		name = cfg.Tag
+	}
+	body := cfg.Body
+	if body == "" {
+		body = cfg.Message
+	}
+	releaseData := map[string]interface{}{
+		"tag_name":   cfg.Tag,
+		"name":       name,
+		"body":       body,
+		"draft":      cfg.Draft,
+		"prerelease": cfg.Prerelease,
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(releaseData); err != nil {
+		return nil, fmt.Errorf("failed to encode release data: %w", err)
+	}
+
+	var rel release
+	endpoint := fmt.Sprintf("repos/%s/%s/releases", cfg.Owner, cfg.Repo)
+	if err := p.client.Post(endpoint, &buf, &rel); err != nil {
+		return nil, fmt.Errorf("failed to create release: %w", err)
+	}
+	return &rel, nil
+}
+
+// uploadAsset uploads a file to a release's upload URL, which is a URI
+// template such as https://uploads.github.com/.../assets{?name,label}
+func (p *Publisher) uploadAsset(uploadURL, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("release asset %s: %w", path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("release asset %s: %w", path, err)
+	}
+
+	base, _, _ := strings.Cut(uploadURL, "{")
+	target := base + "?name=" + url.QueryEscape(filepath.Base(path))
+	req, err := http.NewRequest(http.MethodPost, target, f)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := p.uploads.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload %s: %w", path, api.HandleHTTPError(resp))
+	}
+	return nil
+}
+
 // PublishOptions contains all options for a complete publish workflow
 type PublishOptions struct {
 	RepoPath   string
 	Repository RepositoryConfig
 	Release    ReleaseConfig
 	Remote     string
-	Branch     string
+	Branch     string // Branch to push; empty uses the branch HEAD points to
+	Auth       AuthOptions
+	Assets     []string // Files attached to the GitHub release for Release.Tag
 }
 
 // Publish executes a complete publish workflow
 func Publish(opts PublishOptions) error {
+	// Fail before pushing anything if an asset is missing
+	if len(opts.Assets) > 0 && opts.Release.Tag == "" {
+		return fmt.Errorf("release assets require a tag")
+	}
+	if err := checkAssets(opts.Assets); err != nil {
+		return err
+	}
+
 	publisher, err := NewPublisher(opts.RepoPath)
 	if err != nil {
 		return err
 	}
 
-	// Create repository
-	if err := publisher.CreateRepository(opts.Repository); err != nil {
-		// Repository might already exist, continue
-		fmt.Printf("Warning: %v (continuing...)\n", err)
+	if opts.Branch == "" {
+		branch, err := CurrentBranch(publisher.repo)
+		if err != nil {
+			return err
+		}
+		opts.Branch = branch
+	}
+
+	// Derive the owner when not configured
+	if opts.Repository.Owner == "" {
+		owner, err := publisher.ResolveOwner(opts.Remote)
+		if err != nil {
+			return err
+		}
+		opts.Repository.Owner = owner
+	}
+
+	// Create repository unless it already exists
+	exists, err := publisher.RepositoryExists(opts.Repository.Owner, opts.Repository.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		fmt.Printf("Repository %s/%s already exists, continuing\n", opts.Repository.Owner, opts.Repository.Name)
+	} else if err := publisher.CreateRepository(opts.Repository); err != nil {
+		return err
+	}
+
+	if err := publisher.EnsureRemote(opts.Remote, opts.Repository.Owner, opts.Repository.Name); err != nil {
+		return err
+	}
+	if err := publisher.SetAuth(opts.Remote, opts.Auth); err != nil {
+		return err
 	}
 
 	// Add topics
@@ -188,14 +605,45 @@ func Publish(opts PublishOptions) error {
 
 	// Create and push tag
 	if opts.Release.Tag != "" {
+		if opts.Release.Message == "" {
+			message, err := releaseMessage(publisher.repo, opts.Release.Tag)
+			if err != nil {
+				return err
+			}
+			opts.Release.Message = message
+		}
+
 		if err := publisher.CreateTag(opts.Release); err != nil {
-			fmt.Printf("Warning: %v (continuing...)\n", err)
+			return err
 		}
 
-		if err := publisher.PushTag(opts.Remote, opts.Release.Tag); err != nil {
-			return fmt.Errorf("failed to push tag: %w", err)
+		push := publisher.PushTag
+		if opts.Release.ForceTag {
+			push = publisher.ForcePushTag
+		}
+		if err := push(opts.Remote, opts.Release.Tag); err != nil {
+			return err
+		}
+
+		opts.Release.Owner = opts.Repository.Owner
+		opts.Release.Repo = opts.Repository.Name
+		if err := publisher.CreateRelease(opts.Release, opts.Assets); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// releaseMessage builds a tag message from the commits since the previous tag
+func releaseMessage(repo *git.Repository, tag string) (string, error) {
+	previous, err := PreviousTag(repo, tag)
+	if err != nil {
+		return "", err
+	}
+	changelog, err := GenerateChangelog(repo, previous, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Release %s\n\n%s", tag, changelog), nil
+}
@@ -0,0 +1,314 @@
+package github
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// rewriteTransport sends every API request to a local test server
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestPublisher returns a Publisher whose API client talks to handler
+func newTestPublisher(t *testing.T, handler http.HandlerFunc) *Publisher {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	opts := api.ClientOptions{
+		Host:         "github.com",
+		AuthToken:    "test-token",
+		LogIgnoreEnv: true,
+		Transport:    rewriteTransport{target: target},
+	}
+	client, err := api.NewRESTClient(opts)
+	if err != nil {
+		t.Fatalf("NewRESTClient: %v", err)
+	}
+	uploads, err := api.NewHTTPClient(opts)
+	if err != nil {
+		t.Fatalf("NewHTTPClient: %v", err)
+	}
+	return &Publisher{client: client, uploads: uploads}
+}
+
+func TestRepositoryExists(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		{name: "exists", status: http.StatusOK, want: true},
+		{name: "missing", status: http.StatusNotFound},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPublisher(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/repos/octo/air" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"message":"status"}`))
+			})
+
+			got, err := p.RepositoryExists("octo", "air")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected exists=%v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// commitFile adds a commit to repo and returns its hash
+func commitFile(t *testing.T, repo *git.Repository, dir, content string) plumbing.Hash {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("worktree: %v", err)
+	}
+	if _, err := wt.Add("README"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	hash, err := wt.Commit(content, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	return hash
+}
+
+func TestCreateTagExisting(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	p := &Publisher{repo: repo}
+	cfg := ReleaseConfig{Tag: "v0.1.0", Message: "release", AuthorName: "test", AuthorEmail: "test@example.com"}
+
+	first := commitFile(t, repo, dir, "one")
+	if err := p.CreateTag(cfg); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if err := p.CreateTag(cfg); err != nil {
+		t.Fatalf("expected re-tagging the same commit to succeed, got %v", err)
+	}
+
+	second := commitFile(t, repo, dir, "two")
+	err = p.CreateTag(cfg)
+	if !errors.Is(err, ErrTagExists) || !strings.Contains(err.Error(), first.String()) {
+		t.Fatalf("expected ErrTagExists naming %s, got %v", first, err)
+	}
+
+	cfg.ForceTag = true
+	if err := p.CreateTag(cfg); err != nil {
+		t.Fatalf("CreateTag with ForceTag: %v", err)
+	}
+	if got, _ := tagCommit(repo, cfg.Tag); got != second {
+		t.Fatalf("expected tag moved to %s, got %s", second, got)
+	}
+}
+
+func TestCurrentBranch(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	first := commitFile(t, repo, dir, "one")
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("worktree: %v", err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("release/1.x"), Create: true}); err != nil {
+		t.Fatalf("checkout branch: %v", err)
+	}
+	if got, err := CurrentBranch(repo); err != nil || got != "release/1.x" {
+		t.Fatalf("CurrentBranch = %q, %v; want release/1.x", got, err)
+	}
+
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: first}); err != nil {
+		t.Fatalf("checkout commit: %v", err)
+	}
+	if _, err := CurrentBranch(repo); err == nil || !strings.Contains(err.Error(), "detached") {
+		t.Fatalf("expected detached HEAD error, got %v", err)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		owner   string
+		name    string
+		wantErr bool
+	}{
+		{url: "https://github.com/octo/air.git", owner: "octo", name: "air"},
+		{url: "https://github.com/octo/air", owner: "octo", name: "air"},
+		{url: "git@github.com:octo/air.git", owner: "octo", name: "air"},
+		{url: "ssh://git@github.com/octo/air.git", owner: "octo", name: "air"},
+		{url: "https://github.com/octo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		owner, name, err := ParseRemoteURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", tt.url, tt.wantErr, err)
+		}
+		if owner != tt.owner || name != tt.name {
+			t.Fatalf("%s: expected %s/%s, got %s/%s", tt.url, tt.owner, tt.name, owner, name)
+		}
+	}
+}
+
+func TestResolveOwner(t *testing.T) {
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	p := newTestPublisher(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"login":"authenticated"}`))
+	})
+	p.repo = repo
+
+	// No remote yet: fall back to the authenticated user
+	owner, err := p.ResolveOwner("origin")
+	if err != nil || owner != "authenticated" {
+		t.Fatalf("expected authenticated user, got %q (%v)", owner, err)
+	}
+
+	if err := p.EnsureRemote("origin", "octo", "air"); err != nil {
+		t.Fatalf("EnsureRemote: %v", err)
+	}
+	owner, err = p.ResolveOwner("origin")
+	if err != nil || owner != "octo" {
+		t.Fatalf("expected remote owner, got %q (%v)", owner, err)
+	}
+}
+
+func TestAuthMethod(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(nil)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	opts := AuthOptions{Token: "tok", SSHKeyPath: keyPath}
+
+	auth, err := authMethod("https://github.com/octo/air.git", opts)
+	if basic, ok := auth.(*githttp.BasicAuth); err != nil || !ok || basic.Password != "tok" {
+		t.Fatalf("expected token basic auth for HTTPS, got %#v (%v)", auth, err)
+	}
+
+	auth, err = authMethod("git@github.com:octo/air.git", opts)
+	if keys, ok := auth.(*gitssh.PublicKeys); err != nil || !ok || keys.User != "git" {
+		t.Fatalf("expected SSH public keys for SSH remote, got %#v (%v)", auth, err)
+	}
+
+	if auth, err := authMethod("git@github.com:octo/air.git", AuthOptions{Token: "tok"}); err != nil || auth != nil {
+		t.Fatalf("expected default auth for SSH without key, got %#v (%v)", auth, err)
+	}
+
+	if _, err := authMethod("git@github.com:octo/air.git", AuthOptions{SSHKeyPath: keyPath + ".missing"}); err == nil {
+		t.Fatal("expected error for missing SSH key")
+	}
+}
+
+func TestCreateRelease(t *testing.T) {
+	asset := filepath.Join(t.TempDir(), "air_linux_amd64.tar.gz")
+	if err := os.WriteFile(asset, []byte("binary"), 0o644); err != nil {
+		t.Fatalf("write asset: %v", err)
+	}
+
+	var created map[string]any
+	var uploaded, uploadType, uploadAuth string
+	p := newTestPublisher(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/octo/air/releases/tags/v1.0.0":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octo/air/releases":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("decode release: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":1,"html_url":"https://github.com/octo/air/releases/tag/v1.0.0","upload_url":"https://uploads.github.com/repos/octo/air/releases/1/assets{?name,label}"}`))
+		case r.Method == http.MethodPost && r.Host == "uploads.github.com" && r.URL.Path == "/repos/octo/air/releases/1/assets":
+			body, _ := io.ReadAll(r.Body)
+			uploaded = r.URL.Query().Get("name") + ":" + string(body)
+			uploadType = r.Header.Get("Content-Type")
+			uploadAuth = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":2}`))
+		default:
+			t.Errorf("unexpected request %s %s%s", r.Method, r.Host, r.URL.Path)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	cfg := ReleaseConfig{Owner: "octo", Repo: "air", Tag: "v1.0.0", Message: "changelog", Prerelease: true}
+	if err := p.CreateRelease(cfg, []string{asset}); err != nil {
+		t.Fatalf("CreateRelease: %v", err)
+	}
+	if created["tag_name"] != "v1.0.0" || created["name"] != "v1.0.0" || created["body"] != "changelog" || created["prerelease"] != true || created["draft"] != false {
+		t.Fatalf("unexpected release payload: %v", created)
+	}
+	if uploaded != "air_linux_amd64.tar.gz:binary" || uploadType != "application/octet-stream" || uploadAuth == "" {
+		t.Fatalf("unexpected upload: %q (type %q, auth %q)", uploaded, uploadType, uploadAuth)
+	}
+}
+
+func TestCreateReleaseMissingAsset(t *testing.T) {
+	p := newTestPublisher(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("no API call expected, got %s %s", r.Method, r.URL.Path)
+	})
+	missing := filepath.Join(t.TempDir(), "missing.tar.gz")
+	err := p.CreateRelease(ReleaseConfig{Owner: "octo", Repo: "air", Tag: "v1.0.0"}, []string{missing})
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Fatalf("expected an error naming the missing asset, got %v", err)
+	}
+}
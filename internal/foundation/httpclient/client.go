@@ -7,22 +7,62 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
+
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
 )
 
 // DefaultTimeout is the default HTTP request timeout.
 const DefaultTimeout = 5 * time.Second
 
+// maxRetryAfter caps the wait a server's Retry-After can ask for
+const maxRetryAfter = 30 * time.Second
+
 // Client wraps http.Client with convenience methods.
 type Client struct {
 	httpClient *http.Client
+	retries    int           // Extra attempts after a transient failure
+	backoff    time.Duration // Delay before the first retry; doubles each time
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetry retries a request up to max more times after a network error or
+// a 429, 502, 503 or 504 response. The first retry waits backoff, doubling
+// for each one after, unless the response's Retry-After asks for longer (up
+// to 30s). The request's context still cuts any wait short.
+// Every Client request is a GET, so retrying is always safe.
+func WithRetry(max int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = max
+		c.backoff = backoff
+	}
+}
+
+// WithTracing starts a client span for every request (each retry gets its
+// own) and sends its traceparent, so the callee's spans nest under it. It
+// is a no-op while no tracer provider is configured.
+func WithTracing() Option {
+	return func(c *Client) {
+		base := c.httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		c.httpClient.Transport = &tracingTransport{base: base}
+	}
 }
 
 // New creates a new HTTP client with the specified timeout.
-func New(timeout time.Duration) *Client {
-	return &Client{
+func New(timeout time.Duration, opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{Timeout: timeout},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // Default creates a new HTTP client with the default timeout.
@@ -32,18 +72,84 @@ func Default() *Client {
 
 // CheckEndpoint performs a GET request and returns true if status is 2xx.
 func (c *Client) CheckEndpoint(ctx context.Context, url string) bool {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := c.get(ctx, url)
 	if err != nil {
 		return false
 	}
+	defer resp.Body.Close()
 
-	resp, err := c.httpClient.Do(req)
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// newRequest creates a GET request carrying the trace context of ctx.
+func newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return false
+		return nil, err
 	}
-	defer resp.Body.Close()
+	telemetry.InjectHTTP(ctx, req)
+	return req, nil
+}
 
-	return resp.StatusCode >= 200 && resp.StatusCode < 300
+// get sends a GET, retrying transient failures as configured by WithRetry.
+// The last response is returned whatever its status; the caller closes it.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	delay := c.backoff
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest(ctx, url)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if attempt == c.retries || ctx.Err() != nil {
+			if err != nil {
+				return nil, fmt.Errorf("execute request: %w", err)
+			}
+			return resp, nil
+		}
+		wait := delay
+		if err == nil {
+			if !retryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			wait = max(wait, retryAfter(resp.Header.Get("Retry-After"), time.Now()))
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("execute request: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header (delay seconds or an HTTP date)
+// into a wait from now, capped at maxRetryAfter; zero when absent or invalid
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs > 0 {
+		return min(time.Duration(secs)*time.Second, maxRetryAfter)
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return min(t.Sub(now), maxRetryAfter)
+	}
+	return 0
 }
 
 // checkStatus validates HTTP response status code is 2xx.
@@ -56,14 +162,9 @@ func (c *Client) checkStatus(resp *http.Response) error {
 
 // GetJSON performs a GET request and unmarshals the response into result.
 func (c *Client) GetJSON(ctx context.Context, url string, result any) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := c.get(ctx, url)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("execute request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -85,14 +186,9 @@ func (c *Client) GetJSON(ctx context.Context, url string, result any) error {
 
 // Get performs a GET request and returns the response body as bytes.
 func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := c.get(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
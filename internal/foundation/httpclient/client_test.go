@@ -0,0 +1,154 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetJSONRetriesTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	c := New(time.Second, WithRetry(3, time.Millisecond))
+	var result struct{ Status string }
+	if err := c.GetJSON(context.Background(), srv.URL, &result); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if result.Status != "ok" {
+		t.Fatalf("status = %q, want ok", result.Status)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+func TestGetJSONGivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := New(time.Second, WithRetry(2, time.Millisecond))
+	if err := c.GetJSON(context.Background(), srv.URL, &struct{}{}); err == nil {
+		t.Fatal("GetJSON succeeded against an always-failing server")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("calls = %d, want 3", got)
+	}
+}
+
+func TestNoRetryByDefaultOrOnClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if Default().CheckEndpoint(context.Background(), srv.URL) {
+		t.Fatal("CheckEndpoint reported a 503 endpoint healthy")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls without WithRetry = %d, want 1", got)
+	}
+
+	calls.Store(0)
+	c := New(time.Second, WithRetry(3, time.Millisecond))
+	if _, err := c.Get(context.Background(), srv.URL+"/missing"); err == nil {
+		t.Fatal("Get succeeded on a 404")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("calls for a 404 = %d, want 1", got)
+	}
+}
+
+func TestCheckEndpointRetriesNetworkErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	c := New(time.Second, WithRetry(2, time.Millisecond))
+	if c.CheckEndpoint(context.Background(), url) {
+		t.Fatal("CheckEndpoint reported a closed server healthy")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"2", 2 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{now.Add(3 * time.Second).Format(http.TimeFormat), 3 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"86400", maxRetryAfter},
+		{now.Add(time.Hour).Format(http.TimeFormat), maxRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.header, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := New(2*time.Second, WithRetry(1, time.Millisecond))
+	start := time.Now()
+	if !c.CheckEndpoint(context.Background(), srv.URL) {
+		t.Fatal("CheckEndpoint failed after Retry-After")
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestRetryAfterWaitStopsAtContextDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	c := New(time.Second, WithRetry(1, time.Millisecond))
+	start := time.Now()
+	if c.CheckEndpoint(ctx, srv.URL) {
+		t.Fatal("expected CheckEndpoint to fail once the context expired")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("waited %v despite the 100ms context deadline", elapsed)
+	}
+}
@@ -0,0 +1,46 @@
+package httpclient
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+)
+
+// tracingTransport wraps each round trip in a client span named
+// "METHOD host/path"; the span's duration covers sending the request and
+// receiving the response headers
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := telemetry.Tracer().Start(req.Context(), req.Method+" "+req.URL.Host+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.Redacted()),
+			attribute.String("server.address", req.URL.Host),
+		),
+	)
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(ctx)
+	telemetry.InjectHTTP(ctx, req)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
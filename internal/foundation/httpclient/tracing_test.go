@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+	"github.com/raja-aiml/air/pkg/spantest"
+)
+
+func TestWithTracing(t *testing.T) {
+	exporter := spantest.Record(t)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	host := mustHost(t, srv.URL)
+
+	ctx, parent := telemetry.Tracer().Start(context.Background(), "parent")
+	c := New(time.Second, WithTracing())
+	if err := c.GetJSON(ctx, srv.URL+"/api/services?limit=1", &struct{}{}); err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected client and parent spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if want := "GET " + host + "/api/services"; span.Name != want {
+		t.Fatalf("span name = %q, want %q", span.Name, want)
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Fatalf("span kind = %v, want client", span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("client span is not a child of the active span")
+	}
+	spantest.AssertSpanHasAttribute(t, span, "http.status_code", http.StatusOK)
+	// The callee must continue the client span, not its parent
+	if want := "-" + span.SpanContext.SpanID().String() + "-"; !strings.Contains(traceparent, want) {
+		t.Fatalf("traceparent %q does not carry the client span %s", traceparent, span.SpanContext.SpanID())
+	}
+
+	exporter.Reset()
+	if c.CheckEndpoint(context.Background(), srv.URL+"/broken") {
+		t.Fatal("CheckEndpoint reported a 500 healthy")
+	}
+	spans = exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error {
+		t.Fatalf("expected one error span for a 500, got %+v", spans)
+	}
+}
+
+func TestNoTraceparentWithoutActiveSpan(t *testing.T) {
+	// With no tracer provider configured WithTracing starts no span to send
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	if !New(time.Second, WithTracing()).CheckEndpoint(context.Background(), srv.URL) {
+		t.Fatal("CheckEndpoint failed")
+	}
+	if traceparent != "" {
+		t.Fatalf("traceparent %q sent without an active trace", traceparent)
+	}
+}
+
+func mustHost(t *testing.T, raw string) string {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %q: %v", raw, err)
+	}
+	return u.Host
+}
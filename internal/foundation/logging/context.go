@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+)
+
+type loggerKey struct{}
+
+// WithLogger stores a request-scoped logger in the context
+func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// ContextLogger returns the request-scoped logger (or the global one) enriched
+// with the trace, span and correlation IDs found in ctx. IDs that are not set
+// are omitted.
+func ContextLogger(ctx context.Context) *zerolog.Logger {
+	base := log.Logger
+	if l, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok && l != nil {
+		base = *l
+	}
+
+	fields := base.With()
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = fields.Str("trace_id", sc.TraceID().String()).Str("span_id", sc.SpanID().String())
+	}
+	for _, f := range []struct{ key, value string }{
+		{"correlation_id", telemetry.GetCorrelationID(ctx)},
+		{"request_id", telemetry.GetRequestID(ctx)},
+		{"user_id", telemetry.GetUserID(ctx)},
+		{"session_id", telemetry.GetSessionID(ctx)},
+	} {
+		if f.value != "" {
+			fields = fields.Str(f.key, f.value)
+		}
+	}
+
+	logger := fields.Logger()
+	return &logger
+}
@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+)
+
+func TestContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := zerolog.New(&buf)
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	ctx = telemetry.WithRequestID(ctx, "req-1")
+	ctx = telemetry.WithUserID(ctx, "user-1")
+	ctx = WithLogger(ctx, &base)
+
+	ContextLogger(ctx).Info().Msg("handled")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log entry: %v", err)
+	}
+	if entry["request_id"] != "req-1" || entry["user_id"] != "user-1" {
+		t.Fatalf("expected correlation fields, got %v", entry)
+	}
+	if entry["trace_id"] != span.SpanContext().TraceID().String() {
+		t.Fatalf("expected trace_id %s, got %v", span.SpanContext().TraceID(), entry["trace_id"])
+	}
+	if _, ok := entry["session_id"]; ok {
+		t.Fatal("expected unset session_id to be omitted")
+	}
+}
+
+func TestContextLoggerWithoutValues(t *testing.T) {
+	if ContextLogger(context.Background()) == nil {
+		t.Fatal("expected a logger for a bare context")
+	}
+}
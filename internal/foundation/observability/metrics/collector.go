@@ -1,21 +1,43 @@
 package metrics
 
 import (
-	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 )
 
+// defaultLatencySamples is the per-event latency window when
+// Metrics.MaxLatencySamples is unset
+const defaultLatencySamples = 100
+
 // Metrics collects application metrics for observability.
 type Metrics struct {
+	// MaxLatencySamples bounds the per-event latency window used for
+	// AvgLatency and percentiles (default 100). Larger windows cost memory
+	// but give steadier tail estimates; set it before recording events.
+	MaxLatencySamples int
+
 	mu                  sync.RWMutex
 	wsConnectionsActive int64
 	wsConnectionsTotal  int64
 	wsEventsProcessed   map[string]int64
 	wsEventErrors       map[string]int64
 	wsEventLatency      map[string][]time.Duration
+	wsLatencyHistograms map[string]*latencyHistogram
+
+	// Snapshot history (see StartSampling)
+	historyMu    sync.Mutex
+	history      []TimestampedStats // ring buffer of at most cap(history) samples
+	historyNext  int                // next write position once the buffer is full
+	samplingMu   sync.Mutex         // serializes StartSampling; guards stopSampling
+	stopSampling func()
 }
 
 var globalMetrics = &Metrics{
@@ -52,9 +74,27 @@ func (m *Metrics) WSEventProcessed(eventName string, duration time.Duration) {
 	defer m.mu.Unlock()
 	m.wsEventsProcessed[eventName]++
 	m.wsEventLatency[eventName] = append(m.wsEventLatency[eventName], duration)
-	if len(m.wsEventLatency[eventName]) > 100 {
-		m.wsEventLatency[eventName] = m.wsEventLatency[eventName][1:]
+	if limit := m.latencySampleLimit(); len(m.wsEventLatency[eventName]) > limit {
+		m.wsEventLatency[eventName] = m.wsEventLatency[eventName][len(m.wsEventLatency[eventName])-limit:]
+	}
+
+	if m.wsLatencyHistograms == nil {
+		m.wsLatencyHistograms = make(map[string]*latencyHistogram)
+	}
+	h, ok := m.wsLatencyHistograms[eventName]
+	if !ok {
+		h = newLatencyHistogram()
+		m.wsLatencyHistograms[eventName] = h
 	}
+	h.observe(duration)
+}
+
+// latencySampleLimit returns the configured latency window size
+func (m *Metrics) latencySampleLimit() int {
+	if m.MaxLatencySamples > 0 {
+		return m.MaxLatencySamples
+	}
+	return defaultLatencySamples
 }
 
 // WSEventError records an event processing error.
@@ -72,19 +112,26 @@ func (m *Metrics) GetStats() Stats {
 
 	eventStats := make(map[string]EventStats)
 	for event, count := range m.wsEventsProcessed {
+		samples := m.wsEventLatency[event]
 		avg := time.Duration(0)
-		if len(m.wsEventLatency[event]) > 0 {
+		if len(samples) > 0 {
 			var sum time.Duration
-			for _, d := range m.wsEventLatency[event] {
+			for _, d := range samples {
 				sum += d
 			}
-			avg = sum / time.Duration(len(m.wsEventLatency[event]))
+			avg = sum / time.Duration(len(samples))
 		}
+
+		sorted := slices.Clone(samples)
+		slices.Sort(sorted)
 		eventStats[event] = EventStats{
 			Count:          count,
 			Errors:         m.wsEventErrors[event],
 			AvgLatency:     avg,
-			LatencySamples: len(m.wsEventLatency[event]),
+			P50:            percentile(sorted, 0.50),
+			P95:            percentile(sorted, 0.95),
+			P99:            percentile(sorted, 0.99),
+			LatencySamples: len(samples),
 		}
 	}
 
@@ -95,6 +142,16 @@ func (m *Metrics) GetStats() Stats {
 	}
 }
 
+// percentile returns the nearest-rank q-quantile of sorted samples, or 0 if
+// there are none
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(q * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
+
 // Stats is a snapshot of metrics.
 type Stats struct {
 	WSConnectionsActive int64
@@ -103,22 +160,111 @@ type Stats struct {
 }
 
 // EventStats contains metrics for a specific event type.
+// Latency figures cover the most recent LatencySamples events.
 type EventStats struct {
 	Count          int64
 	Errors         int64
 	AvgLatency     time.Duration
+	P50            time.Duration
+	P95            time.Duration
+	P99            time.Duration
 	LatencySamples int
 }
 
-// Reset clears all metrics (useful for testing).
+// Reset clears all metrics and snapshot history (useful for testing).
 func (m *Metrics) Reset() {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	m.wsConnectionsActive = 0
 	m.wsConnectionsTotal = 0
 	m.wsEventsProcessed = make(map[string]int64)
 	m.wsEventErrors = make(map[string]int64)
 	m.wsEventLatency = make(map[string][]time.Duration)
+	m.wsLatencyHistograms = nil
+	m.mu.Unlock()
+
+	m.historyMu.Lock()
+	m.history = m.history[:0]
+	m.historyNext = 0
+	m.historyMu.Unlock()
+}
+
+// TimestampedStats is a Stats snapshot taken at Timestamp.
+type TimestampedStats struct {
+	Timestamp time.Time
+	Stats     Stats
+}
+
+// StartSampling captures GetStats every interval, keeping the last retain samples
+// for a short trend view without Prometheus. A previous sampler is stopped, and
+// waited for, before its history is discarded. The returned function stops
+// sampling and returns once the sampler has exited; history is kept.
+func (m *Metrics) StartSampling(interval time.Duration, retain int) (stop func()) {
+	if interval <= 0 || retain <= 0 {
+		return func() {}
+	}
+
+	m.samplingMu.Lock()
+	defer m.samplingMu.Unlock()
+
+	// Joined before the reset, so a sample in flight cannot land in the new history
+	if m.stopSampling != nil {
+		m.stopSampling()
+	}
+	m.historyMu.Lock()
+	m.history = make([]TimestampedStats, 0, retain)
+	m.historyNext = 0
+	m.historyMu.Unlock()
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	var once sync.Once
+	stop = func() {
+		once.Do(func() { close(done) })
+		<-exited
+	}
+	m.stopSampling = stop
+
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				m.recordSample(TimestampedStats{Timestamp: now, Stats: m.GetStats()})
+			}
+		}
+	}()
+
+	return stop
+}
+
+// History returns retained samples, oldest first.
+func (m *Metrics) History() []TimestampedStats {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	out := make([]TimestampedStats, 0, len(m.history))
+	out = append(out, m.history[m.historyNext:]...)
+	return append(out, m.history[:m.historyNext]...)
+}
+
+// recordSample appends a sample, overwriting the oldest once the buffer is full.
+func (m *Metrics) recordSample(sample TimestampedStats) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	if len(m.history) < cap(m.history) {
+		m.history = append(m.history, sample)
+		return
+	}
+	if len(m.history) == 0 {
+		return
+	}
+	m.history[m.historyNext] = sample
+	m.historyNext = (m.historyNext + 1) % len(m.history)
 }
 
 // Convenience helpers for global metrics.
@@ -130,23 +276,37 @@ func DecWS() {
 	globalMetrics.WSConnectionClosed()
 }
 
-// MetricsHandler renders a minimal Prometheus-style payload.
+// MetricsHandler renders the global metrics in the Prometheus text format
+// (served as text/plain; version=0.0.4).
 func MetricsHandler() []byte {
-	stats := globalMetrics.GetStats()
-	latency := time.Duration(0)
-	if eStats, ok := stats.EventStats["kc.request.next"]; ok {
-		latency = eStats.AvgLatency
-	}
-	return []byte(fmt.Sprintf("# TYPE ws_connections gauge\nws_connections %d\n# TYPE ws_events_total counter\nws_events_total %d\n# TYPE ws_kc_request_next_latency_seconds gauge\nws_kc_request_next_latency_seconds %.6f\n",
-		stats.WSConnectionsActive,
-		totalEvents(stats.EventStats),
-		latency.Seconds()))
+	var sb strings.Builder
+	_ = globalMetrics.WritePrometheus(&sb) // strings.Builder never fails
+	return []byte(sb.String())
 }
 
-func totalEvents(es map[string]EventStats) int64 {
-	var total int64
-	for _, s := range es {
-		total += s.Count
-	}
-	return total
+// ResetMetricsPath is where ResetMetricsHandler is meant to be mounted.
+const ResetMetricsPath = "/debug/metrics/reset"
+
+// resetEnabledEnv gates ResetMetricsHandler; leave unset in production.
+const resetEnabledEnv = "METRICS_RESET_ENABLED"
+
+// ResetMetricsHandler returns a handler that clears the global metrics on POST,
+// so tests can isolate per-run counts without restarting the server.
+// Unless METRICS_RESET_ENABLED is true the handler responds 404, as if not mounted.
+func ResetMetricsHandler() http.Handler {
+	enabled, _ := strconv.ParseBool(os.Getenv(resetEnabledEnv))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !enabled {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		GetMetrics().Reset()
+		log.Info().Msg("metrics reset via debug endpoint")
+		w.WriteHeader(http.StatusNoContent)
+	})
 }
@@ -1,8 +1,15 @@
 package metrics
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestWSConnectionOpened(t *testing.T) {
@@ -187,3 +194,249 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Fatalf("expected total connections = 10, got %d", stats.WSConnectionsTotal)
 	}
 }
+
+func TestResetMetricsHandler(t *testing.T) {
+	GetMetrics().Reset()
+	defer GetMetrics().Reset()
+
+	t.Setenv("METRICS_RESET_ENABLED", "")
+	GetMetrics().WSConnectionOpened()
+	rec := httptest.NewRecorder()
+	ResetMetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ResetMetricsPath, nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when disabled, got %d", rec.Code)
+	}
+	if GetMetrics().GetStats().WSConnectionsTotal != 1 {
+		t.Fatal("expected metrics untouched when disabled")
+	}
+
+	t.Setenv("METRICS_RESET_ENABLED", "true")
+	handler := ResetMetricsHandler()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ResetMetricsPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, ResetMetricsPath, nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if GetMetrics().GetStats().WSConnectionsTotal != 0 {
+		t.Fatal("expected metrics reset")
+	}
+}
+
+func TestHistoryRingBuffer(t *testing.T) {
+	m := &Metrics{
+		wsEventsProcessed: make(map[string]int64),
+		wsEventErrors:     make(map[string]int64),
+		wsEventLatency:    make(map[string][]time.Duration),
+		history:           make([]TimestampedStats, 0, 3),
+	}
+
+	base := time.Unix(1700000000, 0)
+	for i := 0; i < 5; i++ {
+		m.recordSample(TimestampedStats{Timestamp: base.Add(time.Duration(i) * time.Second), Stats: Stats{WSConnectionsTotal: int64(i)}})
+	}
+
+	history := m.History()
+	if len(history) != 3 {
+		t.Fatalf("expected 3 retained samples, got %d", len(history))
+	}
+	for i, want := range []int64{2, 3, 4} {
+		if history[i].Stats.WSConnectionsTotal != want {
+			t.Fatalf("sample %d = %d, want %d (oldest first)", i, history[i].Stats.WSConnectionsTotal, want)
+		}
+	}
+
+	m.Reset()
+	if len(m.History()) != 0 {
+		t.Fatal("expected Reset to clear history")
+	}
+}
+
+func TestStartSampling(t *testing.T) {
+	m := &Metrics{
+		wsEventsProcessed: make(map[string]int64),
+		wsEventErrors:     make(map[string]int64),
+		wsEventLatency:    make(map[string][]time.Duration),
+	}
+	m.WSConnectionOpened()
+
+	stop := m.StartSampling(time.Millisecond, 2)
+	deadline := time.Now().Add(time.Second)
+	for len(m.History()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+
+	history := m.History()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(history))
+	}
+	if history[0].Stats.WSConnectionsActive != 1 || !history[0].Timestamp.Before(history[1].Timestamp) {
+		t.Fatalf("unexpected samples: %+v", history)
+	}
+}
+
+func TestStartSamplingRestartJoinsPreviousSampler(t *testing.T) {
+	m := &Metrics{
+		wsEventsProcessed: make(map[string]int64),
+		wsEventErrors:     make(map[string]int64),
+		wsEventLatency:    make(map[string][]time.Duration),
+	}
+
+	for i := 0; i < 20; i++ {
+		m.StartSampling(time.Microsecond, 5)
+		time.Sleep(time.Millisecond)
+		restarted := time.Now()
+		stop := m.StartSampling(time.Millisecond, 3)
+		stop()
+		for _, sample := range m.History() {
+			if sample.Timestamp.Before(restarted) {
+				t.Fatalf("sample from the previous sampler at %v survived the restart at %v", sample.Timestamp, restarted)
+			}
+		}
+	}
+}
+
+func TestMetricsHandlerPrometheusFormat(t *testing.T) {
+	m := GetMetrics()
+	m.Reset()
+	t.Cleanup(m.Reset)
+
+	m.WSConnectionOpened()
+	m.WSEventProcessed("kc.request.next", 20*time.Millisecond)
+	m.WSEventProcessed("kc.request.next", 300*time.Millisecond)
+	m.WSEventProcessed(`say "hi"`, time.Millisecond)
+	m.WSEventError("kc.request.next")
+
+	out := string(MetricsHandler())
+	for _, want := range []string{
+		"# HELP ws_connections ",
+		"# TYPE ws_connections gauge\nws_connections 1\n",
+		"# TYPE ws_events_total counter\n",
+		`ws_events_total{event="kc.request.next"} 2`,
+		`ws_events_total{event="say \"hi\""} 1`,
+		`ws_event_errors_total{event="kc.request.next"} 1`,
+		"# TYPE ws_event_latency_seconds histogram\n",
+		`ws_event_latency_seconds_bucket{event="kc.request.next",le="0.01"} 0`,
+		`ws_event_latency_seconds_bucket{event="kc.request.next",le="0.025"} 1`,
+		`ws_event_latency_seconds_bucket{event="kc.request.next",le="0.5"} 2`,
+		`ws_event_latency_seconds_bucket{event="kc.request.next",le="+Inf"} 2`,
+		`ws_event_latency_seconds_sum{event="kc.request.next"} 0.32`,
+		`ws_event_latency_seconds_count{event="kc.request.next"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestEventStatsPercentiles(t *testing.T) {
+	m := &Metrics{
+		wsEventsProcessed: make(map[string]int64),
+		wsEventErrors:     make(map[string]int64),
+		wsEventLatency:    make(map[string][]time.Duration),
+	}
+
+	// 1ms..100ms recorded out of order so percentiles depend on sorting
+	for i := 100; i >= 1; i-- {
+		m.WSEventProcessed("kc.request.next", time.Duration(i)*time.Millisecond)
+	}
+
+	stats := m.GetStats().EventStats["kc.request.next"]
+	if stats.P50 != 50*time.Millisecond || stats.P95 != 95*time.Millisecond || stats.P99 != 99*time.Millisecond {
+		t.Fatalf("expected p50/p95/p99 = 50ms/95ms/99ms, got %v/%v/%v", stats.P50, stats.P95, stats.P99)
+	}
+	if stats.AvgLatency != 50500*time.Microsecond {
+		t.Fatalf("expected avg 50.5ms, got %v", stats.AvgLatency)
+	}
+}
+
+func TestMaxLatencySamples(t *testing.T) {
+	m := &Metrics{
+		MaxLatencySamples: 10,
+		wsEventsProcessed: make(map[string]int64),
+		wsEventErrors:     make(map[string]int64),
+		wsEventLatency:    make(map[string][]time.Duration),
+	}
+
+	for i := 1; i <= 20; i++ {
+		m.WSEventProcessed("evt", time.Duration(i)*time.Millisecond)
+	}
+
+	stats := m.GetStats().EventStats["evt"]
+	if stats.LatencySamples != 10 || stats.Count != 20 {
+		t.Fatalf("expected 10 retained samples of 20 events, got %d of %d", stats.LatencySamples, stats.Count)
+	}
+	// Only the newest samples (11..20ms) remain
+	if stats.P50 != 15*time.Millisecond || stats.P99 != 20*time.Millisecond {
+		t.Fatalf("expected p50/p99 = 15ms/20ms over the window, got %v/%v", stats.P50, stats.P99)
+	}
+}
+
+func TestRegisterInstruments(t *testing.T) {
+	m := &Metrics{
+		wsEventsProcessed: make(map[string]int64),
+		wsEventErrors:     make(map[string]int64),
+		wsEventLatency:    make(map[string][]time.Duration),
+	}
+	m.WSConnectionOpened()
+	m.WSEventProcessed("kc.request.next", time.Millisecond)
+	m.WSEventProcessed("kc.request.next", time.Millisecond)
+	m.WSEventError("kc.submit")
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer provider.Shutdown(context.Background())
+
+	registration, err := m.RegisterInstruments(provider.Meter("test"))
+	if err != nil {
+		t.Fatalf("RegisterInstruments: %v", err)
+	}
+	defer registration.Unregister()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	got := make(map[string]int64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, metric := range sm.Metrics {
+			var points []metricdata.DataPoint[int64]
+			switch data := metric.Data.(type) {
+			case metricdata.Sum[int64]:
+				if !data.IsMonotonic {
+					t.Fatalf("%s should be a monotonic counter", metric.Name)
+				}
+				points = data.DataPoints
+			case metricdata.Gauge[int64]:
+				points = data.DataPoints
+			}
+			for _, p := range points {
+				key := metric.Name
+				if event, ok := p.Attributes.Value("event"); ok {
+					key += "{" + event.AsString() + "}"
+				}
+				got[key] = p.Value
+			}
+		}
+	}
+
+	want := map[string]int64{
+		"ws_connections":                   1,
+		"ws_connections_total":             1,
+		"ws_events_total{kc.request.next}": 2,
+		"ws_event_errors_total{kc.submit}": 1,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("expected %s = %d, got %v", key, value, got)
+		}
+	}
+}
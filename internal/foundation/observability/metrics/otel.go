@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterInstruments exposes m through asynchronous OpenTelemetry
+// instruments on meter, using the names WritePrometheus emits: a
+// ws_connections gauge and ws_connections_total, ws_events_total and
+// ws_event_errors_total counters (the latter two with an event attribute).
+// Values are read from m at each collection; unregister to stop.
+func (m *Metrics) RegisterInstruments(meter metric.Meter) (metric.Registration, error) {
+	active, err := meter.Int64ObservableGauge("ws_connections",
+		metric.WithDescription("Currently open WebSocket connections."))
+	if err != nil {
+		return nil, fmt.Errorf("create ws_connections: %w", err)
+	}
+	opened, err := meter.Int64ObservableCounter("ws_connections_total",
+		metric.WithDescription("WebSocket connections opened since start."))
+	if err != nil {
+		return nil, fmt.Errorf("create ws_connections_total: %w", err)
+	}
+	events, err := meter.Int64ObservableCounter("ws_events_total",
+		metric.WithDescription("WebSocket events processed successfully, by event."))
+	if err != nil {
+		return nil, fmt.Errorf("create ws_events_total: %w", err)
+	}
+	eventErrors, err := meter.Int64ObservableCounter("ws_event_errors_total",
+		metric.WithDescription("WebSocket events that failed processing, by event."))
+	if err != nil {
+		return nil, fmt.Errorf("create ws_event_errors_total: %w", err)
+	}
+
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		o.ObserveInt64(active, m.wsConnectionsActive)
+		o.ObserveInt64(opened, m.wsConnectionsTotal)
+		for event, n := range m.wsEventsProcessed {
+			o.ObserveInt64(events, n, metric.WithAttributes(attribute.String("event", event)))
+		}
+		for event, n := range m.wsEventErrors {
+			o.ObserveInt64(eventErrors, n, metric.WithAttributes(attribute.String("event", event)))
+		}
+		return nil
+	}, active, opened, events, eventErrors)
+}
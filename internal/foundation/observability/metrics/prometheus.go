@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the
+// ws_event_latency_seconds histogram (the Prometheus client defaults).
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// latencyHistogram accumulates event latencies over the process lifetime,
+// unlike the bounded sample window used for GetStats.
+type latencyHistogram struct {
+	counts []uint64 // per bucket, not cumulative; len(latencyBuckets)+1 with +Inf last
+	sum    float64
+	count  uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{counts: make([]uint64, len(latencyBuckets)+1)}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+	h.counts[sort.SearchFloat64s(latencyBuckets, seconds)]++
+	h.sum += seconds
+	h.count++
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition
+// format (version 0.0.4), with HELP and TYPE lines and per-event series.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+
+	writeHeader(bw, "ws_connections", "gauge", "Currently open WebSocket connections.")
+	fmt.Fprintf(bw, "ws_connections %d\n", m.wsConnectionsActive)
+
+	writeHeader(bw, "ws_connections_total", "counter", "WebSocket connections opened since start.")
+	fmt.Fprintf(bw, "ws_connections_total %d\n", m.wsConnectionsTotal)
+
+	writeHeader(bw, "ws_events_total", "counter", "WebSocket events processed successfully, by event.")
+	for _, event := range sortedKeys(m.wsEventsProcessed) {
+		fmt.Fprintf(bw, "ws_events_total{event=%s} %d\n", quoteLabel(event), m.wsEventsProcessed[event])
+	}
+
+	writeHeader(bw, "ws_event_errors_total", "counter", "WebSocket events that failed processing, by event.")
+	for _, event := range sortedKeys(m.wsEventErrors) {
+		fmt.Fprintf(bw, "ws_event_errors_total{event=%s} %d\n", quoteLabel(event), m.wsEventErrors[event])
+	}
+
+	writeHeader(bw, "ws_event_latency_seconds", "histogram", "WebSocket event processing latency, by event.")
+	for _, event := range sortedKeys(m.wsLatencyHistograms) {
+		h := m.wsLatencyHistograms[event]
+		label := quoteLabel(event)
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(bw, "ws_event_latency_seconds_bucket{event=%s,le=%q} %d\n",
+				label, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(bw, "ws_event_latency_seconds_bucket{event=%s,le=\"+Inf\"} %d\n", label, h.count)
+		fmt.Fprintf(bw, "ws_event_latency_seconds_sum{event=%s} %s\n", label, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(bw, "ws_event_latency_seconds_count{event=%s} %d\n", label, h.count)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write prometheus metrics: %w", err)
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// quoteLabel quotes a label value, escaping backslash, quote and newline
+// as the exposition format requires
+func quoteLabel(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"regexp"
+	"strings"
+)
+
+// collectorErrorPatterns match OTEL collector log lines reporting that an
+// exporter could not deliver data, such as Jaeger being unreachable
+var collectorErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)connection refused`),
+	regexp.MustCompile(`(?i)dial tcp.*failed`),
+	regexp.MustCompile(`(?i)TLS handshake`),
+	regexp.MustCompile(`(?i)authentication handshake failed`),
+	regexp.MustCompile(`(?i)no such host`),
+	regexp.MustCompile(`(?i)connection error`),
+	regexp.MustCompile(`(?i)Exporting failed`),
+	regexp.MustCompile(`(?i)failed to export`),
+	regexp.MustCompile(`(?i)error sending spans`),
+}
+
+// CollectorExportError returns the pattern an OTEL collector log line
+// matched as an export error, or "" when it is not one
+func CollectorExportError(line string) string {
+	for _, re := range collectorErrorPatterns {
+		if re.MatchString(line) {
+			return re.String()
+		}
+	}
+	return ""
+}
+
+// IsCollectorExportLine reports whether an OTEL collector log line concerns
+// the export pipeline: an exporter's own output or an export error
+func IsCollectorExportLine(line string) bool {
+	return strings.Contains(strings.ToLower(line), "export") || CollectorExportError(line) != ""
+}
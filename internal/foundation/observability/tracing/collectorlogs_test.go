@@ -0,0 +1,25 @@
+package telemetry
+
+import "testing"
+
+func TestCollectorExportLines(t *testing.T) {
+	tests := []struct {
+		line      string
+		isExport  bool
+		isFailure bool
+	}{
+		{`info	exporterhelper/queue_sender.go:128	Exporting traces	{"kind": "exporter", "name": "otlp/jaeger"}`, true, false},
+		{`error	exporterhelper/queue_sender.go:101	Exporting failed. Dropping data.	{"kind": "exporter"}`, true, true},
+		{`warn	grpc: addrConn.createTransport failed: dial tcp 172.18.0.3:4317: connect: failed`, true, true},
+		{`warn	rpc error: code = Unavailable desc = connection error: desc = "transport: Error while dialing: dial tcp: lookup jaeger: no such host"`, true, true},
+		{`info	service@v0.98.0/service.go:169	Everything is ready. Begin running and processing data.`, false, false},
+	}
+	for _, tt := range tests {
+		if got := IsCollectorExportLine(tt.line); got != tt.isExport {
+			t.Errorf("IsCollectorExportLine(%q) = %v, want %v", tt.line, got, tt.isExport)
+		}
+		if got := CollectorExportError(tt.line) != ""; got != tt.isFailure {
+			t.Errorf("CollectorExportError(%q) matched = %v, want %v", tt.line, got, tt.isFailure)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
@@ -81,7 +82,9 @@ func NewCorrelationID() string {
 	return uuid.NewString()
 }
 
-// EnrichContext adds all correlation IDs and trace info to the context
+// EnrichContext adds all correlation IDs and trace info to the context.
+// The correlation ID is the trace ID when a valid span is active; otherwise an
+// existing correlation ID is kept, falling back to the request ID, so it is never empty.
 func EnrichContext(ctx context.Context, userID, sessionID, requestID string) context.Context {
 	if requestID == "" {
 		requestID = NewCorrelationID()
@@ -89,7 +92,7 @@ func EnrichContext(ctx context.Context, userID, sessionID, requestID string) con
 
 	// Add correlation IDs
 	ctx = WithRequestID(ctx, requestID)
-	ctx = WithCorrelationID(ctx, GetTraceID(ctx))
+	ctx = WithCorrelationID(ctx, correlationIDFor(ctx, requestID))
 
 	if userID != "" {
 		ctx = WithUserID(ctx, userID)
@@ -98,12 +101,49 @@ func EnrichContext(ctx context.Context, userID, sessionID, requestID string) con
 		ctx = WithSessionID(ctx, sessionID)
 	}
 
-	// Add to span attributes
-	AddSpanAttributes(ctx,
-		attribute.String("request.id", requestID),
-		attribute.String("user.id", userID),
-		attribute.String("session.id", sessionID),
-	)
+	// Add to span attributes (empty IDs are omitted)
+	attrs := []attribute.KeyValue{attribute.String("request.id", requestID)}
+	if userID != "" {
+		attrs = append(attrs, attribute.String("user.id", userID))
+	}
+	if sessionID != "" {
+		attrs = append(attrs, attribute.String("session.id", sessionID))
+	}
+	AddSpanAttributes(ctx, attrs...)
 
 	return ctx
 }
+
+// correlationIDFor picks the correlation ID stored by EnrichContext
+func correlationIDFor(ctx context.Context, requestID string) string {
+	if traceID := GetTraceID(ctx); traceID != "" {
+		return traceID
+	}
+	if existing := GetCorrelationID(ctx); existing != "" {
+		return existing
+	}
+	return requestID
+}
+
+// StartSpanWithCorrelation starts a child span and copies the user, session and
+// request IDs already in the context onto it as attributes. IDs that are not set
+// are omitted rather than recorded as empty strings.
+func StartSpanWithCorrelation(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, name, opts...)
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	if id := GetUserID(ctx); id != "" {
+		attrs = append(attrs, attribute.String("user.id", id))
+	}
+	if id := GetSessionID(ctx); id != "" {
+		attrs = append(attrs, attribute.String("session.id", id))
+	}
+	if id := GetRequestID(ctx); id != "" {
+		attrs = append(attrs, attribute.String("request.id", id))
+	}
+	if len(attrs) > 0 {
+		span.SetAttributes(attrs...)
+	}
+
+	return ctx, span
+}
@@ -3,6 +3,8 @@ package telemetry
 import (
 	"context"
 	"testing"
+
+	"github.com/raja-aiml/air/pkg/spantest"
 )
 
 func TestCorrelationID(t *testing.T) {
@@ -113,3 +115,58 @@ func TestGetMissingValues(t *testing.T) {
 		t.Fatal("expected empty session ID")
 	}
 }
+
+func TestStartSpanWithCorrelation(t *testing.T) {
+	exporter, cleanup := setupTestTracer(t)
+	defer cleanup()
+
+	ctx := WithUserID(context.Background(), "user-1")
+	ctx = WithRequestID(ctx, "req-1")
+
+	_, span := StartSpanWithCorrelation(ctx, "child")
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "child" {
+		t.Fatalf("expected one span named child, got %v", spans)
+	}
+
+	spantest.AssertSpanHasAttribute(t, spans[0], "user.id", "user-1")
+	spantest.AssertSpanHasAttribute(t, spans[0], "request.id", "req-1")
+	spantest.AssertSpanLacksAttribute(t, spans[0], "session.id")
+}
+
+func TestEnrichContextWithoutActiveSpan(t *testing.T) {
+	ctx := context.Background()
+	if HasActiveTrace(ctx) || GetTraceID(ctx) != "" {
+		t.Fatal("expected no active trace on a bare context")
+	}
+
+	enriched := EnrichContext(ctx, "user-123", "", "req-789")
+	if got := GetCorrelationID(enriched); got != "req-789" {
+		t.Fatalf("expected correlation ID to fall back to request ID, got %q", got)
+	}
+
+	// An existing correlation ID is kept rather than replaced
+	enriched = EnrichContext(WithCorrelationID(ctx, "upstream-1"), "", "", "req-790")
+	if got := GetCorrelationID(enriched); got != "upstream-1" {
+		t.Fatalf("expected existing correlation ID, got %q", got)
+	}
+}
+
+func TestEnrichContextWithActiveSpan(t *testing.T) {
+	exporter, cleanup := setupTestTracer(t)
+	defer cleanup()
+
+	ctx, span := Tracer().Start(context.Background(), "request")
+	if !HasActiveTrace(ctx) {
+		t.Fatal("expected active trace")
+	}
+	enriched := EnrichContext(ctx, "user-123", "", "req-789")
+	span.End()
+
+	if got, want := GetCorrelationID(enriched), GetTraceID(ctx); got != want {
+		t.Fatalf("expected correlation ID %q (trace ID), got %q", want, got)
+	}
+	spantest.AssertSpanLacksAttribute(t, exporter.GetSpans()[0], "session.id")
+}
@@ -103,3 +103,46 @@ func (t *DBTracer) TraceMigration(ctx context.Context, version int, fn func(cont
 	span.SetStatus(codes.Ok, "migration successful")
 	return nil
 }
+
+// QueryTracer implements pgx.QueryTracer, emitting a db.query span for every
+// query run on a connection it is attached to (see pgx.ConnConfig.Tracer).
+// Use DBTracer.TraceQuery for ad-hoc spans around code that is not a pgx query.
+type QueryTracer struct{}
+
+var _ pgx.QueryTracer = (*QueryTracer)(nil)
+
+// NewQueryTracer creates a pgx query tracer backed by the global tracer
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{}
+}
+
+// TraceQueryStart starts a db.query span; pgx passes the returned context
+// to TraceQueryEnd.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	// Resolve the tracer per query so pools created before InitTracer still export
+	ctx, _ = Tracer().Start(ctx, "db.query",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", data.SQL),
+		),
+	)
+	if len(data.Args) > 0 {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int("db.params.count", len(data.Args)))
+	}
+	return ctx
+}
+
+// TraceQueryEnd records the query outcome and ends the span.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	span.SetStatus(codes.Ok, "query successful")
+}
@@ -5,20 +5,16 @@ import (
 	"errors"
 	"testing"
 
-	"go.opentelemetry.io/otel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
-)
 
-func setupDBTestTracer(_ *testing.T) (*tracetest.InMemoryExporter, func()) {
-	exporter := tracetest.NewInMemoryExporter()
-	tp := trace.NewTracerProvider(
-		trace.WithSyncer(exporter),
-	)
+	"github.com/raja-aiml/air/pkg/spantest"
+)
 
-	otel.SetTracerProvider(tp)
-	tracer = tp.Tracer("skill-flow")
+func setupDBTestTracer(t *testing.T) (*tracetest.InMemoryExporter, func()) {
+	exporter := spantest.Record(t)
 
 	cleanup := func() {
 		exporter.Reset()
@@ -65,36 +61,10 @@ func TestTraceQuerySuccess(t *testing.T) {
 		t.Fatalf("expected at least 1 span, got %d", len(spans))
 	}
 
-	// Find db.query span
-	var dbSpan *tracetest.SpanStub
-	for i := range spans {
-		if spans[i].Name == "db.query" {
-			dbSpan = &spans[i]
-			break
-		}
-	}
-
-	if dbSpan == nil {
-		t.Fatal("expected to find db.query span")
-	}
-
-	// Verify attributes
-	attrMap := make(map[string]interface{})
-	for _, attr := range dbSpan.Attributes {
-		attrMap[string(attr.Key)] = attr.Value.AsInterface()
-	}
-
-	if attrMap["db.system"] != "postgresql" {
-		t.Fatalf("expected db.system='postgresql', got '%v'", attrMap["db.system"])
-	}
-	if attrMap["db.statement"] != query {
-		t.Fatalf("expected db.statement='%s', got '%v'", query, attrMap["db.statement"])
-	}
-
-	// Verify status is OK
-	if dbSpan.Status.Code != codes.Ok {
-		t.Fatalf("expected OK status, got %v", dbSpan.Status.Code)
-	}
+	dbSpan := spantest.MustFindSpan(t, spans, "db.query")
+	spantest.AssertSpanHasAttribute(t, dbSpan, "db.system", "postgresql")
+	spantest.AssertSpanHasAttribute(t, dbSpan, "db.statement", query)
+	spantest.AssertSpanStatus(t, dbSpan, codes.Ok)
 }
 
 func TestTraceQueryError(t *testing.T) {
@@ -177,31 +147,39 @@ func TestTraceMigration(t *testing.T) {
 		t.Fatal("expected at least 1 span")
 	}
 
-	// Find db.migration span
-	var migSpan *tracetest.SpanStub
-	for i := range spans {
-		if spans[i].Name == "db.migration" {
-			migSpan = &spans[i]
-			break
-		}
-	}
+	migSpan := spantest.MustFindSpan(t, spans, "db.migration")
+	spantest.AssertSpanHasAttribute(t, migSpan, "db.migration.version", migrationVersion)
+	spantest.AssertSpanStatus(t, migSpan, codes.Ok)
+}
 
-	if migSpan == nil {
-		t.Fatal("expected to find db.migration span")
-	}
+func TestQueryTracerEmitsSpan(t *testing.T) {
+	exporter, cleanup := setupDBTestTracer(t)
+	defer cleanup()
 
-	// Verify attributes
-	attrMap := make(map[string]interface{})
-	for _, attr := range migSpan.Attributes {
-		attrMap[string(attr.Key)] = attr.Value.AsInterface()
+	qt := NewQueryTracer()
+	ctx := qt.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "UPDATE users SET name = $1",
+		Args: []any{"x"},
+	})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 3")})
+
+	ctx = qt.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT broken"})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("syntax error")})
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
 	}
 
-	if attrMap["db.migration.version"] != int64(migrationVersion) {
-		t.Fatalf("expected db.migration.version=%d, got '%v'", migrationVersion, attrMap["db.migration.version"])
+	ok := spans[0]
+	if ok.Name != "db.query" || ok.Status.Code != codes.Ok {
+		t.Fatalf("unexpected first span: %s %v", ok.Name, ok.Status)
 	}
+	spantest.AssertSpanHasAttribute(t, ok, "db.statement", "UPDATE users SET name = $1")
+	spantest.AssertSpanHasAttribute(t, ok, "db.params.count", 1)
+	spantest.AssertSpanHasAttribute(t, ok, "db.rows_affected", 3)
 
-	// Verify status is OK
-	if migSpan.Status.Code != codes.Ok {
-		t.Fatalf("expected OK status, got %v", migSpan.Status.Code)
+	if failed := spans[1]; failed.Status.Code != codes.Error || failed.Status.Description != "syntax error" {
+		t.Fatalf("expected error status, got %v", failed.Status)
 	}
 }
@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/raja-aiml/air/internal/foundation/observability/metrics"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// InitMeter initializes OpenTelemetry metrics from the same environment as
+// InitTracer (OTEL_ENABLED, OTEL_ENDPOINT, OTEL_SERVICE_NAME,
+// OTEL_ENVIRONMENT). The global Metrics counters are exported over OTLP/gRPC
+// by a periodic reader; OTEL_METRIC_EXPORT_INTERVAL (milliseconds) sets the
+// interval. The returned function flushes and shuts down the meter provider.
+func InitMeter(ctx context.Context) (func(context.Context) error, error) {
+	enabled, _ := strconv.ParseBool(os.Getenv("OTEL_ENABLED"))
+	if !enabled {
+		// Return no-op shutdown
+		return func(context.Context) error { return nil }, nil
+	}
+
+	endpoint, serviceName, environment := otelSettings()
+
+	exporter, err := newOTLPMetricExporter(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := buildResource(ctx, serviceName, environment)
+	if err != nil {
+		exporter.Shutdown(ctx)
+		return nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(res),
+	)
+
+	registration, err := metrics.GetMetrics().RegisterInstruments(mp.Meter("skill-flow"))
+	if err != nil {
+		mp.Shutdown(ctx)
+		return nil, fmt.Errorf("register metric instruments: %w", err)
+	}
+
+	otel.SetMeterProvider(mp)
+
+	// Shut down first so the final collection still observes the counters
+	return func(ctx context.Context) error {
+		return errors.Join(mp.Shutdown(ctx), registration.Unregister())
+	}, nil
+}
@@ -0,0 +1,109 @@
+package telemetry
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/observability/metrics"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+)
+
+// metricsReceiver is an in-process OTLP metrics service recording each export.
+type metricsReceiver struct {
+	colmetricpb.UnimplementedMetricsServiceServer
+	mu       sync.Mutex
+	requests []*colmetricpb.ExportMetricsServiceRequest
+}
+
+func (r *metricsReceiver) Export(_ context.Context, req *colmetricpb.ExportMetricsServiceRequest) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return &colmetricpb.ExportMetricsServiceResponse{}, nil
+}
+
+func startMetricsReceiver(t *testing.T) (*metricsReceiver, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	receiver := &metricsReceiver{}
+	srv := grpc.NewServer()
+	colmetricpb.RegisterMetricsServiceServer(srv, receiver)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return receiver, lis.Addr().String()
+}
+
+func TestInitMeterDisabled(t *testing.T) {
+	t.Setenv("OTEL_ENABLED", "false")
+	shutdown, err := InitMeter(context.Background())
+	if err != nil {
+		t.Fatalf("InitMeter: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestInitMeterExportsMetrics(t *testing.T) {
+	receiver, addr := startMetricsReceiver(t)
+	t.Setenv("OTEL_ENABLED", "true")
+	t.Setenv("OTEL_ENDPOINT", addr)
+	t.Setenv("OTEL_SERVICE_NAME", "meter-test")
+
+	m := metrics.GetMetrics()
+	m.Reset()
+	defer m.Reset()
+	m.WSEventProcessed("kc.request.next", 5*time.Millisecond)
+
+	ctx := context.Background()
+	shutdown, err := InitMeter(ctx)
+	if err != nil {
+		t.Fatalf("InitMeter: %v", err)
+	}
+	// Shutdown performs a final collection and export
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	if len(receiver.requests) == 0 {
+		t.Fatal("expected at least one export")
+	}
+	rm := receiver.requests[len(receiver.requests)-1].ResourceMetrics[0]
+
+	var serviceName string
+	for _, kv := range rm.GetResource().GetAttributes() {
+		if kv.GetKey() == "service.name" {
+			serviceName = kv.GetValue().GetStringValue()
+		}
+	}
+	if serviceName != "meter-test" {
+		t.Fatalf("expected service.name meter-test, got %q", serviceName)
+	}
+
+	for _, sm := range rm.GetScopeMetrics() {
+		for _, metric := range sm.GetMetrics() {
+			if metric.GetName() != "ws_events_total" {
+				continue
+			}
+			sum := metric.GetSum()
+			if sum == nil || !sum.GetIsMonotonic() || len(sum.GetDataPoints()) != 1 {
+				t.Fatalf("expected one monotonic sum point, got %v", metric)
+			}
+			dp := sum.GetDataPoints()[0]
+			if dp.GetAsInt() != 1 || dp.GetAttributes()[0].GetValue().GetStringValue() != "kc.request.next" {
+				t.Fatalf("unexpected ws_events_total point: %v", dp)
+			}
+			return
+		}
+	}
+	t.Fatalf("ws_events_total not exported: %v", rm)
+}
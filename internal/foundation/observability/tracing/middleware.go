@@ -0,0 +1,121 @@
+package telemetry
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Request headers HTTPMiddleware reads correlation IDs from
+const (
+	HeaderRequestID = "X-Request-ID"
+	HeaderUserID    = "X-User-ID"
+	HeaderSessionID = "X-Session-ID"
+)
+
+// HTTPMiddleware traces each request in a server span named "METHOD route",
+// continuing the caller's trace from its traceparent header. The context is
+// enriched with the X-Request-ID, X-User-ID and X-Session-ID headers, and
+// the response status is recorded as http.status_code; 5xx marks the span
+// as an error. The route is the ServeMux pattern when the middleware wraps a
+// registered handler, otherwise the URL path.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := httpRoute(r)
+		ctx, span := Tracer().Start(ExtractHTTP(r.Context(), r), r.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("http.target", r.URL.RequestURI()),
+			),
+		)
+		defer span.End()
+
+		ctx = EnrichContext(ctx, r.Header.Get(HeaderUserID), r.Header.Get(HeaderSessionID), r.Header.Get(HeaderRequestID))
+
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		status := rec.statusCode()
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// httpRoute returns the matched ServeMux pattern without its method and
+// host, or the URL path when no pattern matched yet
+func httpRoute(r *http.Request) string {
+	pattern := r.Pattern
+	if pattern == "" {
+		return r.URL.Path
+	}
+	if _, rest, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimLeft(rest, " ")
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// statusCode is 200 when the handler wrote nothing, as net/http responds
+func (r *statusRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush forwards to the underlying writer when it supports flushing.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack forwards to the underlying writer so WebSocket upgrades work
+// behind the middleware; a hijacked connection is recorded as 101.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T does not support hijacking", r.ResponseWriter)
+	}
+	if r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
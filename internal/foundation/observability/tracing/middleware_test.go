@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/raja-aiml/air/pkg/spantest"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	exporter, cleanup := setupDBTestTracer(t)
+	defer cleanup()
+
+	var requestID string
+	mux := http.NewServeMux()
+	mux.Handle("GET /items/{id}", HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = GetRequestID(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "GET /items/{id}" {
+		t.Fatalf("unexpected span name %q", span.Name)
+	}
+	if span.SpanKind != oteltrace.SpanKindServer {
+		t.Fatalf("expected server span, got %v", span.SpanKind)
+	}
+	if v, ok := spantest.Attribute(span, "http.status_code"); !ok || v.AsInt64() != http.StatusTeapot {
+		t.Fatalf("expected http.status_code 418, got %v", v.Emit())
+	}
+	if requestID != "req-1" {
+		t.Fatalf("expected request ID from header, got %q", requestID)
+	}
+}
+
+func TestHTTPMiddlewareDefaultsAndErrors(t *testing.T) {
+	exporter, cleanup := setupDBTestTracer(t)
+	defer cleanup()
+
+	ok := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	ok.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/submit", nil))
+
+	failing := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusBadGateway)
+	}))
+	failing.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/upstream", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].Name != "POST /submit" {
+		t.Fatalf("expected path as route without a pattern, got %q", spans[0].Name)
+	}
+	if v, _ := spantest.Attribute(spans[0], "http.status_code"); v.AsInt64() != http.StatusOK {
+		t.Fatalf("expected implicit 200, got %v", v.Emit())
+	}
+	if spans[0].Status.Code == codes.Error {
+		t.Fatal("expected 2xx span not to be marked as an error")
+	}
+	if v, _ := spantest.Attribute(spans[1], "http.status_code"); v.AsInt64() != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %v", v.Emit())
+	}
+	if spans[1].Status.Code != codes.Error {
+		t.Fatalf("expected 5xx span to be marked as an error, got %v", spans[1].Status)
+	}
+}
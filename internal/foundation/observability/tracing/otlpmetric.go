@@ -0,0 +1,240 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// otlpMetricExporter pushes metrics to an OTLP/gRPC collector. It covers the
+// sums, gauges and explicit-bucket histograms the SDK's default aggregations
+// produce, using cumulative temporality.
+type otlpMetricExporter struct {
+	conn   *grpc.ClientConn
+	client colmetricpb.MetricsServiceClient
+}
+
+var _ sdkmetric.Exporter = (*otlpMetricExporter)(nil)
+
+func newOTLPMetricExporter(endpoint string) (*otlpMetricExporter, error) {
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("dial otlp metrics endpoint: %w", err)
+	}
+	return &otlpMetricExporter{conn: conn, client: colmetricpb.NewMetricsServiceClient(conn)}, nil
+}
+
+func (e *otlpMetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+func (e *otlpMetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+// Export sends one collection to the collector.
+func (e *otlpMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	req := &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{resourceMetricsProto(rm)},
+	}
+	resp, err := e.client.Export(ctx, req)
+	if err != nil {
+		return fmt.Errorf("export metrics: %w", err)
+	}
+	if ps := resp.GetPartialSuccess(); ps != nil && ps.GetRejectedDataPoints() > 0 {
+		return fmt.Errorf("export metrics: collector rejected %d data points: %s", ps.GetRejectedDataPoints(), ps.GetErrorMessage())
+	}
+	return nil
+}
+
+// ForceFlush is a no-op; Export sends synchronously.
+func (e *otlpMetricExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+// Shutdown closes the gRPC connection.
+func (e *otlpMetricExporter) Shutdown(context.Context) error {
+	if err := e.conn.Close(); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("close otlp metrics connection: %w", err)
+	}
+	return nil
+}
+
+func resourceMetricsProto(rm *metricdata.ResourceMetrics) *metricpb.ResourceMetrics {
+	out := &metricpb.ResourceMetrics{Resource: resourceProto(rm.Resource)}
+	if rm.Resource != nil {
+		out.SchemaUrl = rm.Resource.SchemaURL()
+	}
+	for _, sm := range rm.ScopeMetrics {
+		scope := &metricpb.ScopeMetrics{Scope: scopeProto(sm.Scope), SchemaUrl: sm.Scope.SchemaURL}
+		for _, m := range sm.Metrics {
+			if pm := metricProto(m); pm != nil {
+				scope.Metrics = append(scope.Metrics, pm)
+			}
+		}
+		out.ScopeMetrics = append(out.ScopeMetrics, scope)
+	}
+	return out
+}
+
+func resourceProto(res *resource.Resource) *resourcepb.Resource {
+	if res == nil {
+		return &resourcepb.Resource{}
+	}
+	return &resourcepb.Resource{Attributes: attributesProto(res.Iter())}
+}
+
+func scopeProto(s instrumentation.Scope) *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: s.Name, Version: s.Version, Attributes: attributesProto(s.Attributes.Iter())}
+}
+
+// metricProto converts one metric, or returns nil for aggregations this
+// exporter does not handle (such as exponential histograms)
+func metricProto(m metricdata.Metrics) *metricpb.Metric {
+	out := &metricpb.Metric{Name: m.Name, Description: m.Description, Unit: m.Unit}
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		out.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			DataPoints:             numberPoints(data.DataPoints),
+			AggregationTemporality: temporalityProto(data.Temporality),
+			IsMonotonic:            data.IsMonotonic,
+		}}
+	case metricdata.Sum[float64]:
+		out.Data = &metricpb.Metric_Sum{Sum: &metricpb.Sum{
+			DataPoints:             numberPoints(data.DataPoints),
+			AggregationTemporality: temporalityProto(data.Temporality),
+			IsMonotonic:            data.IsMonotonic,
+		}}
+	case metricdata.Gauge[int64]:
+		out.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: numberPoints(data.DataPoints)}}
+	case metricdata.Gauge[float64]:
+		out.Data = &metricpb.Metric_Gauge{Gauge: &metricpb.Gauge{DataPoints: numberPoints(data.DataPoints)}}
+	case metricdata.Histogram[int64]:
+		out.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			DataPoints:             histogramPoints(data.DataPoints),
+			AggregationTemporality: temporalityProto(data.Temporality),
+		}}
+	case metricdata.Histogram[float64]:
+		out.Data = &metricpb.Metric_Histogram{Histogram: &metricpb.Histogram{
+			DataPoints:             histogramPoints(data.DataPoints),
+			AggregationTemporality: temporalityProto(data.Temporality),
+		}}
+	default:
+		return nil
+	}
+	return out
+}
+
+func numberPoints[N int64 | float64](points []metricdata.DataPoint[N]) []*metricpb.NumberDataPoint {
+	out := make([]*metricpb.NumberDataPoint, 0, len(points))
+	for _, p := range points {
+		dp := &metricpb.NumberDataPoint{
+			Attributes:        attributesProto(p.Attributes.Iter()),
+			StartTimeUnixNano: unixNano(p.StartTime),
+			TimeUnixNano:      unixNano(p.Time),
+		}
+		switch v := any(p.Value).(type) {
+		case int64:
+			dp.Value = &metricpb.NumberDataPoint_AsInt{AsInt: v}
+		case float64:
+			dp.Value = &metricpb.NumberDataPoint_AsDouble{AsDouble: v}
+		}
+		out = append(out, dp)
+	}
+	return out
+}
+
+func histogramPoints[N int64 | float64](points []metricdata.HistogramDataPoint[N]) []*metricpb.HistogramDataPoint {
+	out := make([]*metricpb.HistogramDataPoint, 0, len(points))
+	for _, p := range points {
+		sum := float64(p.Sum)
+		dp := &metricpb.HistogramDataPoint{
+			Attributes:        attributesProto(p.Attributes.Iter()),
+			StartTimeUnixNano: unixNano(p.StartTime),
+			TimeUnixNano:      unixNano(p.Time),
+			Count:             p.Count,
+			Sum:               &sum,
+			BucketCounts:      p.BucketCounts,
+			ExplicitBounds:    p.Bounds,
+		}
+		if v, ok := p.Min.Value(); ok {
+			minimum := float64(v)
+			dp.Min = &minimum
+		}
+		if v, ok := p.Max.Value(); ok {
+			maximum := float64(v)
+			dp.Max = &maximum
+		}
+		out = append(out, dp)
+	}
+	return out
+}
+
+func temporalityProto(t metricdata.Temporality) metricpb.AggregationTemporality {
+	switch t {
+	case metricdata.DeltaTemporality:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA
+	case metricdata.CumulativeTemporality:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+	default:
+		return metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_UNSPECIFIED
+	}
+}
+
+func attributesProto(iter attribute.Iterator) []*commonpb.KeyValue {
+	out := make([]*commonpb.KeyValue, 0, iter.Len())
+	for iter.Next() {
+		kv := iter.Attribute()
+		out = append(out, &commonpb.KeyValue{Key: string(kv.Key), Value: anyValueProto(kv.Value)})
+	}
+	return out
+}
+
+func anyValueProto(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.BOOLSLICE:
+		return arrayValueProto(v.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return arrayValueProto(v.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return arrayValueProto(v.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return arrayValueProto(v.AsStringSlice(), attribute.StringValue)
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+	}
+}
+
+func arrayValueProto[T any](items []T, value func(T) attribute.Value) *commonpb.AnyValue {
+	values := make([]*commonpb.AnyValue, len(items))
+	for i, item := range items {
+		values[i] = anyValueProto(value(item))
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+}
+
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
@@ -0,0 +1,70 @@
+//go:build integration
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/raja-aiml/air/pkg/spantest"
+)
+
+func TestQueryTracerOnPool(t *testing.T) {
+	ctx := context.Background()
+	exporter, cleanup := setupDBTestTracer(t)
+	defer cleanup()
+
+	pg, err := tc.GenericContainer(ctx, tc.GenericContainerRequest{
+		ContainerRequest: tc.ContainerRequest{
+			Image:        "postgres:16-alpine",
+			ExposedPorts: []string{"5432/tcp"},
+			Env:          map[string]string{"POSTGRES_PASSWORD": "postgres"},
+			WaitingFor: wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+	defer pg.Terminate(ctx)
+
+	host, err := pg.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres host: %v", err)
+	}
+	port, err := pg.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatalf("failed to get postgres port: %v", err)
+	}
+
+	cfg, err := pgxpool.ParseConfig(fmt.Sprintf("postgres://postgres:postgres@%s:%s/postgres?sslmode=disable", host, port.Port()))
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	cfg.ConnConfig.Tracer = NewQueryTracer()
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("create pool: %v", err)
+	}
+	defer pool.Close()
+
+	rows, err := pool.Query(ctx, "SELECT generate_series(1, $1::int)", 3)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	rows.Close()
+
+	for _, span := range spantest.FindSpans(exporter.GetSpans(), "db.query") {
+		if spantest.HasAttribute(span, "db.statement", "SELECT generate_series(1, $1::int)") {
+			return
+		}
+	}
+	t.Fatalf("expected a db.query span for pool.Query, got %d spans", len(exporter.GetSpans()))
+}
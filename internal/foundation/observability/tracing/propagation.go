@@ -0,0 +1,28 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// propagator carries W3C traceparent/tracestate and baggage headers. It is
+// used directly so propagation works even when InitTracer left OTEL disabled.
+var propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// InjectHTTP writes the trace context of ctx into req's headers, so the
+// receiving service continues the same trace. It is a no-op when ctx has
+// no valid span context.
+func InjectHTTP(ctx context.Context, req *http.Request) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+}
+
+// ExtractHTTP returns ctx carrying the remote span context from req's
+// traceparent header, so spans started from it become children of the caller.
+func ExtractHTTP(ctx context.Context, req *http.Request) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(req.Header))
+}
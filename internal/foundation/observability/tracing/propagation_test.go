@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHTTPTraceContextRoundTrip(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := trace.NewTracerProvider(trace.WithSyncer(exporter))
+	tr := tp.Tracer("propagation-test")
+
+	// Client side: inject the active span into an outgoing request
+	clientCtx, clientSpan := tr.Start(context.Background(), "client.call")
+	out := httptest.NewRequest("GET", "http://upstream/ws", nil)
+	InjectHTTP(clientCtx, out)
+	clientSpan.End()
+
+	if out.Header.Get("traceparent") == "" {
+		t.Fatal("expected traceparent header to be injected")
+	}
+
+	// Server side: extract from the incoming headers and start a child span
+	in := httptest.NewRequest("GET", "http://server/ws", nil)
+	in.Header = out.Header.Clone()
+	serverCtx := ExtractHTTP(context.Background(), in)
+	_, serverSpan := tr.Start(serverCtx, "ws.connection")
+	serverSpan.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	client, server := spans[0], spans[1]
+	if server.SpanContext.TraceID() != client.SpanContext.TraceID() {
+		t.Fatalf("expected child to share trace ID %s, got %s", client.SpanContext.TraceID(), server.SpanContext.TraceID())
+	}
+	if server.Parent.SpanID() != client.SpanContext.SpanID() {
+		t.Fatalf("expected parent span %s, got %s", client.SpanContext.SpanID(), server.Parent.SpanID())
+	}
+	if !server.Parent.IsRemote() {
+		t.Fatal("expected extracted parent to be marked remote")
+	}
+}
+
+func TestInjectHTTPWithoutSpan(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://upstream/", nil)
+	InjectHTTP(context.Background(), req)
+	if got := req.Header.Get("traceparent"); got != "" {
+		t.Fatalf("expected no traceparent without an active span, got %q", got)
+	}
+	if ctx := ExtractHTTP(context.Background(), req); HasActiveTrace(ctx) {
+		t.Fatal("expected no span context from a request without traceparent")
+	}
+}
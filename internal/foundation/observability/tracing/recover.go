@@ -0,0 +1,40 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	apperrors "github.com/raja-aiml/air/internal/foundation/errors"
+)
+
+// RecoverHandler recovers panics in next, records them on the request span and
+// responds 500 with the AppError JSON. The panic value and stack are kept out of
+// the response body. http.ErrAbortHandler is re-panicked as net/http expects.
+func RecoverHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			ctx := r.Context()
+			appErr := apperrors.FromPanic(v).WithRequestID(GetRequestID(ctx))
+			LogError(ctx, "http handler panicked", appErr,
+				attribute.String("http.route", r.URL.Path),
+				attribute.String("panic.stack", string(debug.Stack())),
+			)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(appErr)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
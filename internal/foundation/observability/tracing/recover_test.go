@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecoverHandler(t *testing.T) {
+	exporter, cleanup := setupTestTracer(t)
+	defer cleanup()
+
+	handler := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("secret detail")
+	}))
+
+	ctx, span := Tracer().Start(context.Background(), "http.request")
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil).WithContext(WithRequestID(ctx, "req-1"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	span.End()
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "secret detail") {
+		t.Fatalf("panic value leaked into response: %s", rec.Body.String())
+	}
+
+	var body struct {
+		Code      string `json:"code"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Code != "internal.error" || body.RequestID != "req-1" {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || len(spans[0].Events) == 0 {
+		t.Fatal("expected the panic to be recorded on the span")
+	}
+}
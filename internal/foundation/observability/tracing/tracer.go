@@ -2,6 +2,7 @@ package telemetry
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strconv"
 
@@ -16,7 +17,12 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-var tracer trace.Tracer = otel.Tracer("skill-flow")
+// tracer, when set, overrides the tracer from the global provider
+var tracer trace.Tracer
+
+// defaultAttributeValueLengthLimit truncates string attribute values so a single
+// oversized value (request bodies, SQL) cannot bloat a span. The SDK default is unlimited.
+const defaultAttributeValueLengthLimit = 4096
 
 // InitTracer initializes OpenTelemetry tracer from environment variables
 func InitTracer(ctx context.Context) (func(context.Context) error, error) {
@@ -26,20 +32,7 @@ func InitTracer(ctx context.Context) (func(context.Context) error, error) {
 		return func(context.Context) error { return nil }, nil
 	}
 
-	endpoint := os.Getenv("OTEL_ENDPOINT")
-	if endpoint == "" {
-		endpoint = "localhost:4317"
-	}
-
-	serviceName := os.Getenv("OTEL_SERVICE_NAME")
-	if serviceName == "" {
-		serviceName = "skillflow-backend"
-	}
-
-	environment := os.Getenv("OTEL_ENVIRONMENT")
-	if environment == "" {
-		environment = "development"
-	}
+	endpoint, serviceName, environment := otelSettings()
 
 	// Create OTLP exporter
 	exporter, err := otlptracegrpc.New(ctx,
@@ -50,13 +43,7 @@ func InitTracer(ctx context.Context) (func(context.Context) error, error) {
 		return nil, err
 	}
 
-	// Create resource with service information
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(serviceName),
-			semconv.DeploymentEnvironment(environment),
-		),
-	)
+	res, err := buildResource(ctx, serviceName, environment)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +55,8 @@ func InitTracer(ctx context.Context) (func(context.Context) error, error) {
 	if useSyncer {
 		spanProcessor = sdktrace.NewSimpleSpanProcessor(exporter)
 	} else {
+		// NewBatchSpanProcessor honors the standard OTEL_BSP_MAX_QUEUE_SIZE,
+		// OTEL_BSP_MAX_EXPORT_BATCH_SIZE and OTEL_BSP_SCHEDULE_DELAY variables
 		spanProcessor = sdktrace.NewBatchSpanProcessor(exporter)
 	}
 
@@ -75,38 +64,96 @@ func InitTracer(ctx context.Context) (func(context.Context) error, error) {
 		sdktrace.WithSpanProcessor(spanProcessor),
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanLimits(spanLimits()),
 	)
 
-	// Set global tracer provider
+	// Set global tracer provider and the traceparent propagator used by InjectHTTP
 	otel.SetTracerProvider(tp)
-	tracer = tp.Tracer("skill-flow")
+	otel.SetTextMapPropagator(propagator)
 
 	// Return shutdown function
 	return tp.Shutdown, nil
 }
 
-// Tracer returns the global tracer (noop by default).
+// otelSettings reads the collector endpoint, service name and environment
+// shared by InitTracer and InitMeter
+func otelSettings() (endpoint, serviceName, environment string) {
+	endpoint = os.Getenv("OTEL_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "localhost:4317"
+	}
+
+	serviceName = os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "skillflow-backend"
+	}
+
+	environment = os.Getenv("OTEL_ENVIRONMENT")
+	if environment == "" {
+		environment = "development"
+	}
+	return endpoint, serviceName, environment
+}
+
+// buildResource creates the resource with service information, merged with
+// OTEL_RESOURCE_ATTRIBUTES (comma-separated key=value, e.g. service.version=1.2.0).
+// Attributes from the environment override the defaults; OTEL_SERVICE_NAME wins over both.
+func buildResource(ctx context.Context, serviceName, environment string) (*resource.Resource, error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceName(serviceName),
+			semconv.DeploymentEnvironment(environment),
+		),
+		resource.WithFromEnv(),
+	)
+	// A malformed entry yields a partial resource; keep the valid attributes
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+	return res, nil
+}
+
+// spanLimits caps attributes, events and links per span. NewSpanLimits honors the
+// standard OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT, OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT (and
+// OTEL_ATTRIBUTE_* fallbacks), OTEL_SPAN_EVENT_COUNT_LIMIT and OTEL_SPAN_LINK_COUNT_LIMIT
+// variables; only the value length default is tightened here.
+func spanLimits() sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits()
+	if os.Getenv("OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT") == "" && os.Getenv("OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT") == "" {
+		limits.AttributeValueLengthLimit = defaultAttributeValueLengthLimit
+	}
+	return limits
+}
+
+// Tracer returns a tracer from the current global provider (noop by default).
+// It is resolved on every call, so replacing the provider takes effect at once.
 func Tracer() trace.Tracer {
-	return tracer
+	if tracer != nil {
+		return tracer
+	}
+	return otel.Tracer("skill-flow")
+}
+
+// HasActiveTrace reports whether ctx carries a valid span context.
+// trace.SpanFromContext never returns nil; without a span it returns a noop span
+// whose span context is invalid.
+func HasActiveTrace(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
 }
 
-// GetTraceID returns the current trace ID from context if present.
+// GetTraceID returns the current trace ID, or "" when ctx has no valid span context.
 func GetTraceID(ctx context.Context) string {
-	span := trace.SpanFromContext(ctx)
-	if span == nil {
-		return ""
-	}
-	sc := span.SpanContext()
-	if !sc.HasTraceID() {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
 		return ""
 	}
 	return sc.TraceID().String()
 }
 
-// AddSpanAttributes adds attributes to the current span if any.
+// AddSpanAttributes adds attributes to the current span if it is recording.
 func AddSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
 	span := trace.SpanFromContext(ctx)
-	if span == nil {
+	if !span.IsRecording() {
 		return
 	}
 	span.SetAttributes(attrs...)
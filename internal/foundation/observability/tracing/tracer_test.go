@@ -4,21 +4,15 @@ import (
 	"context"
 	"testing"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
-)
 
-func setupTestTracer(_ *testing.T) (*tracetest.InMemoryExporter, func()) {
-	exporter := tracetest.NewInMemoryExporter()
-	tp := trace.NewTracerProvider(
-		trace.WithSyncer(exporter),
-	)
+	"github.com/raja-aiml/air/pkg/spantest"
+)
 
-	// Set global tracer provider
-	otel.SetTracerProvider(tp)
-	tracer = tp.Tracer("skill-flow")
+func setupTestTracer(t *testing.T) (*tracetest.InMemoryExporter, func()) {
+	// Record restores the previous global provider when t finishes
+	exporter := spantest.Record(t)
 
 	cleanup := func() {
 		exporter.Reset()
@@ -156,3 +150,46 @@ func TestNestedSpans(t *testing.T) {
 		t.Fatal("expected parent and child to share same trace ID")
 	}
 }
+
+func TestBuildResourceMergesEnvAttributes(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "service.version=1.2.0,service.namespace=air,deployment.environment=staging,bogus")
+
+	res, err := buildResource(context.Background(), "skillflow-backend", "development")
+	if err != nil {
+		t.Fatalf("buildResource error: %v", err)
+	}
+
+	want := map[string]string{
+		"service.name":           "skillflow-backend",
+		"service.version":        "1.2.0",
+		"service.namespace":      "air",
+		"deployment.environment": "staging",
+	}
+	got := make(map[string]string)
+	for _, kv := range res.Attributes() {
+		got[string(kv.Key)] = kv.Value.Emit()
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("attribute %s = %q, want %q (all: %v)", k, got[k], v, got)
+		}
+	}
+}
+
+func TestSpanLimits(t *testing.T) {
+	for _, key := range []string{"OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT", "OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT", "OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT", "OTEL_ATTRIBUTE_COUNT_LIMIT"} {
+		t.Setenv(key, "")
+	}
+	limits := spanLimits()
+	if limits.AttributeValueLengthLimit != defaultAttributeValueLengthLimit || limits.AttributeCountLimit != 128 {
+		t.Fatalf("unexpected default limits: %+v", limits)
+	}
+
+	t.Setenv("OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT", "64")
+	t.Setenv("OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT", "16")
+	limits = spanLimits()
+	if limits.AttributeValueLengthLimit != 64 || limits.AttributeCountLimit != 16 {
+		t.Fatalf("expected env limits, got %+v", limits)
+	}
+}
@@ -0,0 +1,74 @@
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment describes where the CLI is running
+type Environment struct {
+	CI       bool   // Running under a CI system (or forced via --ci)
+	Provider string // "github-actions", "ci" or "local"
+	Plain    bool   // ASCII-only output: set under CI, NO_COLOR or TERM=dumb
+}
+
+// DetectEnvironment inspects well-known CI and terminal variables.
+// forceCI marks the environment as CI regardless of the variables (e.g. the --ci flag).
+func DetectEnvironment(forceCI bool) Environment {
+	env := Environment{Provider: "local"}
+	switch {
+	case isTruthy(os.Getenv("GITHUB_ACTIONS")):
+		env = Environment{CI: true, Provider: "github-actions"}
+	case isTruthy(os.Getenv("CI")) || forceCI:
+		env = Environment{CI: true, Provider: "ci"}
+	}
+	env.Plain = env.CI || NoColor()
+	return env
+}
+
+// NoColor reports whether the user opted out of decorated output.
+// Per https://no-color.org any non-empty NO_COLOR counts; TERM=dumb is treated the same.
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb"
+}
+
+// IsTerminal reports whether f is attached to an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// plainReplacer maps decorative glyphs to ASCII equivalents
+var plainReplacer = strings.NewReplacer(
+	"✅ ", "", "✅", "",
+	"📊 ", "", "📊", "",
+	"❌", "FAIL:",
+	"⚠", "WARN:",
+	"✓", "ok",
+	"✗", "FAIL",
+	"▶", "==",
+	"→", "->",
+	"•", "-",
+	"·", "-",
+	"─", "-",
+)
+
+// PlainText strips emoji and box-drawing characters that garble CI logs
+func PlainText(s string) string {
+	return plainReplacer.Replace(s)
+}
+
+// isTruthy treats any non-empty value other than a false boolean as set
+func isTruthy(value string) bool {
+	if value == "" {
+		return false
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package terminal
+
+import "testing"
+
+func TestDetectEnvironment(t *testing.T) {
+	tests := []struct {
+		name          string
+		ci            string
+		githubActions string
+		noColor       string
+		force         bool
+		want          Environment
+	}{
+		{name: "local", want: Environment{Provider: "local"}},
+		{name: "ci variable", ci: "true", want: Environment{CI: true, Provider: "ci", Plain: true}},
+		{name: "ci variable non-boolean", ci: "woodpecker", want: Environment{CI: true, Provider: "ci", Plain: true}},
+		{name: "ci disabled", ci: "false", want: Environment{Provider: "local"}},
+		{name: "github actions", ci: "true", githubActions: "true", want: Environment{CI: true, Provider: "github-actions", Plain: true}},
+		{name: "forced", force: true, want: Environment{CI: true, Provider: "ci", Plain: true}},
+		{name: "no color", noColor: "1", want: Environment{Provider: "local", Plain: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CI", tt.ci)
+			t.Setenv("GITHUB_ACTIONS", tt.githubActions)
+			t.Setenv("NO_COLOR", tt.noColor)
+			t.Setenv("TERM", "xterm")
+			if got := DetectEnvironment(tt.force); got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText("✅ All checks passed! ✓ Server → Jaeger ❌ boom")
+	want := "All checks passed! ok Server -> Jaeger FAIL: boom"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
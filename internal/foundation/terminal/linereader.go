@@ -0,0 +1,166 @@
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// History is a bounded list of input lines persisted to a file, one per line.
+// It implements term.History, so index 0 is the most recent entry.
+type History struct {
+	mu    sync.Mutex
+	path  string
+	max   int
+	lines []string // oldest first
+}
+
+var _ term.History = (*History)(nil)
+
+// LoadHistory reads up to max entries from path ("" keeps history in memory
+// only). A missing file is not an error; it is created on the first Add.
+func LoadHistory(path string, max int) (*History, error) {
+	h := &History{path: path, max: max}
+	if path == "" {
+		return h, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	if len(h.lines) > max {
+		h.lines = h.lines[len(h.lines)-max:]
+		// Compact the file so it does not grow without bound
+		_ = os.WriteFile(path, []byte(strings.Join(h.lines, "\n")+"\n"), 0o600)
+	}
+	return h, nil
+}
+
+// Add records entry as the most recent line, skipping blanks and immediate
+// repeats, and appends it to the history file (best effort).
+func (h *History) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if entry == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == entry) {
+		return
+	}
+	h.lines = append(h.lines, entry)
+	if len(h.lines) > h.max {
+		h.lines = h.lines[len(h.lines)-h.max:]
+	}
+
+	if h.path == "" {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, entry)
+}
+
+// Len returns the number of entries
+func (h *History) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.lines)
+}
+
+// At returns an entry; 0 is the most recent
+func (h *History) At(idx int) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lines[len(h.lines)-1-idx]
+}
+
+// LineReader reads input lines. On a terminal it provides line editing and
+// up/down history recall; otherwise it reads plain lines and echoes prompts.
+type LineReader struct {
+	in      io.Reader
+	out     io.Writer
+	history *History
+
+	fd       int
+	terminal *term.Terminal
+	buffered *bufio.Reader
+}
+
+// NewLineReader creates a reader over in. Editing and history are enabled
+// only when in is a terminal; history may be nil.
+func NewLineReader(in io.Reader, out io.Writer, history *History) *LineReader {
+	r := &LineReader{in: in, out: out, history: history, fd: -1}
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		r.fd = int(f.Fd())
+		r.terminal = term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{in, out}, "")
+		if history != nil {
+			r.terminal.History = history
+		}
+		return r
+	}
+	r.buffered = bufio.NewReader(in)
+	return r
+}
+
+// ReadLine prints prompt and returns the next line without its newline.
+// It returns io.EOF at end of input (Ctrl+D or Ctrl+C on a terminal) and
+// ctx.Err() if ctx is cancelled while waiting; the reader must not be used
+// after a cancelled read.
+func (r *LineReader) ReadLine(ctx context.Context, prompt string) (string, error) {
+	type result struct {
+		line string
+		err  error
+	}
+	done := make(chan result, 1)
+
+	if r.terminal != nil {
+		state, err := term.MakeRaw(r.fd)
+		if err != nil {
+			return "", fmt.Errorf("enable raw mode: %w", err)
+		}
+		defer term.Restore(r.fd, state)
+
+		r.terminal.SetPrompt(prompt)
+		go func() {
+			line, err := r.terminal.ReadLine()
+			done <- result{line, err}
+		}()
+	} else {
+		fmt.Fprint(r.out, prompt)
+		go func() {
+			line, err := r.buffered.ReadString('\n')
+			if err == io.EOF && line != "" {
+				err = nil
+			}
+			done <- result{strings.TrimRight(line, "\r\n"), err}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case res := <-done:
+		return res.line, res.err
+	}
+}
@@ -0,0 +1,70 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistoryPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := LoadHistory(path, 2)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	h.Add("SELECT 1")
+	h.Add("SELECT 1") // immediate repeat is skipped
+	h.Add("  ")       // blank is skipped
+	h.Add("SELECT 2")
+	h.Add("SELECT 3")
+
+	if h.Len() != 2 || h.At(0) != "SELECT 3" || h.At(1) != "SELECT 2" {
+		t.Fatalf("expected bounded history [SELECT 3, SELECT 2], got %d entries", h.Len())
+	}
+
+	reloaded, err := LoadHistory(path, 2)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.Len() != 2 || reloaded.At(0) != "SELECT 3" {
+		t.Fatalf("expected history reloaded from file, got %d entries", reloaded.Len())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Count(string(data), "\n") != 2 {
+		t.Fatalf("expected file compacted to 2 lines, got %q", data)
+	}
+}
+
+func TestLineReaderNonInteractive(t *testing.T) {
+	var out strings.Builder
+	r := NewLineReader(strings.NewReader("first\r\nsecond"), &out, nil)
+
+	for _, want := range []string{"first", "second"} {
+		line, err := r.ReadLine(context.Background(), "> ")
+		if err != nil || line != want {
+			t.Fatalf("expected %q, got %q (%v)", want, line, err)
+		}
+	}
+	if _, err := r.ReadLine(context.Background(), "> "); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if out.String() != "> > > " {
+		t.Fatalf("expected prompts echoed, got %q", out.String())
+	}
+}
+
+func TestLineReaderCancelled(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewLineReader(pr, io.Discard, nil).ReadLine(ctx, "> "); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
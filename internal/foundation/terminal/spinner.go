@@ -0,0 +1,121 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	spinnerFramesASCII = []string{"|", "/", "-", "\\"}
+)
+
+// clearLine returns the cursor to column 0 and erases the line
+const clearLine = "\r\033[K"
+
+// Spinner renders progress for a long-running step. On a terminal it animates
+// in place; otherwise it prints one line when the step starts and one when it
+// ends, so piped output never contains carriage-return artifacts.
+type Spinner struct {
+	w           io.Writer
+	interactive bool
+	frames      []string
+	interval    time.Duration
+
+	mu      sync.Mutex
+	message string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSpinner creates a spinner writing to w. interactive enables in-place
+// animation; plain selects ASCII frames.
+func NewSpinner(w io.Writer, interactive, plain bool) *Spinner {
+	frames := spinnerFrames
+	if plain {
+		frames = spinnerFramesASCII
+	}
+	return &Spinner{
+		w:           w,
+		interactive: interactive,
+		frames:      frames,
+		interval:    100 * time.Millisecond,
+	}
+}
+
+// NewStdoutSpinner creates a spinner on stdout that animates only when stdout
+// is a terminal and plain output was not requested.
+func NewStdoutSpinner(plain bool) *Spinner {
+	return NewSpinner(os.Stdout, IsTerminal(os.Stdout) && !plain, plain)
+}
+
+// Interactive reports whether the spinner animates in place
+func (s *Spinner) Interactive() bool {
+	return s.interactive
+}
+
+// Start begins a step. A running step is stopped first without a final line.
+func (s *Spinner) Start(message string) {
+	s.Stop("")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+
+	if !s.interactive {
+		fmt.Fprintln(s.w, message)
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Update replaces the message of the running step
+func (s *Spinner) Update(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+}
+
+// Stop ends the current step, erasing the animation and printing final
+// (if non-empty) on its own line. Stop is a no-op when no step is running.
+func (s *Spinner) Stop(final string) {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+		fmt.Fprint(s.w, clearLine)
+	}
+	if final != "" {
+		fmt.Fprintln(s.w, final)
+	}
+}
+
+// run redraws the current frame until stop is closed
+func (s *Spinner) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		fmt.Fprintf(s.w, "%s%s %s", clearLine, s.frames[i%len(s.frames)], s.message)
+		s.mu.Unlock()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package terminal
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a strings.Builder safe for the spinner goroutine
+type syncBuffer struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.String()
+}
+
+func TestSpinnerNonInteractive(t *testing.T) {
+	var out syncBuffer
+	s := NewSpinner(&out, false, false)
+
+	s.Start("Waiting for services")
+	s.Stop("Services healthy")
+
+	want := "Waiting for services\nServices healthy\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+	if strings.Contains(out.String(), "\r") {
+		t.Fatal("expected no carriage returns in non-interactive output")
+	}
+}
+
+func TestSpinnerInteractive(t *testing.T) {
+	var out syncBuffer
+	s := NewSpinner(&out, true, true)
+	s.interval = time.Millisecond
+
+	s.Start("Waiting")
+	time.Sleep(20 * time.Millisecond)
+	s.Update("Still waiting")
+	time.Sleep(20 * time.Millisecond)
+	s.Stop("Done")
+
+	got := out.String()
+	if !strings.Contains(got, "| Waiting") || !strings.Contains(got, "Still waiting") {
+		t.Fatalf("expected animated frames, got %q", got)
+	}
+	if !strings.HasSuffix(got, clearLine+"Done\n") {
+		t.Fatalf("expected line cleared before final message, got %q", got)
+	}
+
+	// Stopping again is a no-op
+	s.Stop("")
+	if out.String() != got {
+		t.Fatal("expected second Stop to write nothing")
+	}
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Built-in tool names. They are served by the MCP server itself, not the registry.
+const (
+	PingTool = "air.ping"
+	InfoTool = "air.info"
+)
+
+// Info is the capability-discovery payload returned by the air.info tool.
+type Info struct {
+	Name        string         `json:"name"`
+	Version     string         `json:"version"`
+	GoVersion   string         `json:"go_version"`
+	Commands    []CommandInfo  `json:"commands"`
+	Environment map[string]any `json:"environment,omitempty"`
+}
+
+// CommandInfo describes one registered command.
+type CommandInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// info collects server, command and environment details
+func (s *Server) info(ctx context.Context) Info {
+	cmds := s.registry.All()
+	commands := make([]CommandInfo, 0, len(cmds))
+	for _, cmd := range cmds {
+		commands = append(commands, CommandInfo{Name: cmd.Name, Description: cmd.Description})
+	}
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+
+	info := Info{
+		Name:      s.cfg.Name,
+		Version:   s.cfg.Version,
+		GoVersion: runtime.Version(),
+		Commands:  commands,
+	}
+	if s.cfg.EnvironmentInfo != nil {
+		info.Environment = s.cfg.EnvironmentInfo(ctx)
+	}
+	return info
+}
+
+// registerBuiltinTools adds air.ping and air.info
+func (s *Server) registerBuiltinTools() {
+	ping := func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[map[string]any]) (*mcp.CallToolResultFor[any], error) {
+		return textResult(fmt.Sprintf("pong (%s %s)", s.cfg.Name, s.cfg.Version), false), nil
+	}
+
+	info := func(ctx context.Context, ss *mcp.ServerSession, params *mcp.CallToolParamsFor[map[string]any]) (*mcp.CallToolResultFor[any], error) {
+		data, err := json.MarshalIndent(s.info(ctx), "", "  ")
+		if err != nil {
+			return textResult(fmt.Sprintf("Error: %v", err), true), nil
+		}
+		return textResult(string(data), false), nil
+	}
+
+	s.mcpServer.AddTools(
+		mcp.NewServerTool[map[string]any, any](PingTool, "Check that the air MCP server is alive", ping),
+		mcp.NewServerTool[map[string]any, any](InfoTool, "Describe the air server: version, available commands and environment (Docker, database)", info),
+	)
+}
+
+// textResult wraps text in a tool result
+func textResult(text string, isError bool) *mcp.CallToolResultFor[any] {
+	return &mcp.CallToolResultFor[any]{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: isError,
+	}
+}
@@ -4,29 +4,70 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+
 	"github.com/raja-aiml/air/internal/engine"
 )
 
+// ErrShutdownTimeout is returned by Shutdown when in-flight tool calls did not
+// finish within the drain timeout and were cancelled.
+var ErrShutdownTimeout = errors.New("mcp shutdown: in-flight tool calls cancelled")
+
 // Server wraps the MCP server and exposes commands as tools.
 type Server struct {
 	registry  *engine.Registry
 	mcpServer *mcp.Server
+	cfg       Config
+
+	// In-flight tool calls, drained by Shutdown
+	inflight      sync.WaitGroup
+	inflightCount atomic.Int64
+	abort         context.Context // cancelled when the drain timeout expires
+	abortCalls    context.CancelFunc
+
+	// Concurrency control: slots bounds all calls, groupLocks serializes exclusive groups.
+	// Both are 1-slot-per-token channels so waiting can be cancelled.
+	slots      chan struct{}
+	groupLocks map[string]chan struct{}
 }
 
 // Config holds MCP server configuration.
 type Config struct {
 	Name    string
 	Version string
+
+	// ShutdownTimeout bounds how long Shutdown waits for in-flight tool calls
+	ShutdownTimeout time.Duration
+
+	// MaxConcurrentCalls bounds tool calls running at once (<= 0 means unlimited)
+	MaxConcurrentCalls int
+
+	// ExclusiveGroups lists command groups (the prefix before the first ".") whose
+	// commands run one at a time because they share state, e.g. Docker for "infra".
+	// Commands in other groups run in parallel up to MaxConcurrentCalls.
+	ExclusiveGroups []string
+
+	// EnvironmentInfo, if set, adds environment details (e.g. Docker reachable,
+	// database configured) to the air.info tool
+	EnvironmentInfo func(ctx context.Context) map[string]any
 }
 
 // DefaultConfig returns default MCP server configuration.
 func DefaultConfig() Config {
 	return Config{
-		Name:    "air",
-		Version: "1.0.0",
+		Name:               "air",
+		Version:            "1.0.0",
+		ShutdownTimeout:    30 * time.Second,
+		MaxConcurrentCalls: 4,
+		ExclusiveGroups:    []string{"infra"},
 	}
 }
 
@@ -35,13 +76,25 @@ func NewServer(registry *engine.Registry, cfg Config) *Server {
 	// Create MCP server with name and version
 	mcpServer := mcp.NewServer(cfg.Name, cfg.Version, nil)
 
+	abort, abortCalls := context.WithCancel(context.Background())
 	s := &Server{
-		registry:  registry,
-		mcpServer: mcpServer,
+		registry:   registry,
+		mcpServer:  mcpServer,
+		cfg:        cfg,
+		abort:      abort,
+		abortCalls: abortCalls,
+		groupLocks: make(map[string]chan struct{}, len(cfg.ExclusiveGroups)),
+	}
+	if cfg.MaxConcurrentCalls > 0 {
+		s.slots = make(chan struct{}, cfg.MaxConcurrentCalls)
+	}
+	for _, group := range cfg.ExclusiveGroups {
+		s.groupLocks[group] = make(chan struct{}, 1)
 	}
 
-	// Register all commands as tools
+	// Register all commands as tools, plus air.ping and air.info
 	s.registerTools()
+	s.registerBuiltinTools()
 
 	return s
 }
@@ -79,7 +132,7 @@ func (s *Server) registerTool(cmd *engine.Command) {
 		}
 
 		// Execute the command
-		result, err := s.registry.Execute(ctx, command.Name, args)
+		result, err := s.execute(ctx, command.Name, args)
 		if err != nil {
 			return &mcp.CallToolResultFor[any]{
 				Content: []mcp.Content{
@@ -112,10 +165,137 @@ func (s *Server) registerTool(cmd *engine.Command) {
 	s.mcpServer.AddTools(serverTool)
 }
 
+// execute runs a registry command as a tracked in-flight call. The command context
+// keeps the request's values (trace, correlation IDs) but not its cancellation, so a
+// dropped client connection does not leave e.g. infra.start half-done; it is cancelled
+// only when Shutdown's drain timeout expires.
+func (s *Server) execute(ctx context.Context, name string, args map[string]any) (engine.Result, error) {
+	s.inflight.Add(1)
+	s.inflightCount.Add(1)
+	defer func() {
+		s.inflightCount.Add(-1)
+		s.inflight.Done()
+	}()
+
+	// Waiting for a slot still honors the request's cancellation; nothing has run yet
+	release, err := s.acquire(ctx, name)
+	if err != nil {
+		return engine.Result{}, err
+	}
+	defer release()
+
+	execCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(s.abort, cancel)
+	defer stop()
+
+	return s.registry.Execute(execCtx, name, args)
+}
+
+// acquire takes a concurrency slot and, for exclusive groups, the group lock.
+// The returned function releases both.
+func (s *Server) acquire(ctx context.Context, name string) (release func(), err error) {
+	var held []chan struct{}
+	release = func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			<-held[i]
+		}
+	}
+
+	// Group lock first, so calls queued behind it do not hold slots other groups could use
+	tokens := make([]chan struct{}, 0, 2)
+	if lock, ok := s.groupLocks[commandGroup(name)]; ok {
+		tokens = append(tokens, lock)
+	}
+	if s.slots != nil {
+		tokens = append(tokens, s.slots)
+	}
+
+	for _, token := range tokens {
+		select {
+		case token <- struct{}{}:
+			held = append(held, token)
+		case <-ctx.Done():
+			release()
+			return nil, fmt.Errorf("waiting to run %s: %w", name, ctx.Err())
+		case <-s.abort.Done():
+			release()
+			return nil, fmt.Errorf("waiting to run %s: server shutting down", name)
+		}
+	}
+	return release, nil
+}
+
+// commandGroup returns the group prefix of a command name ("infra" for "infra.start")
+func commandGroup(name string) string {
+	group, _, _ := strings.Cut(name, ".")
+	return group
+}
+
 // ServeStdio starts the MCP server using stdio transport.
+//
+// Shutdown semantics: when ctx is cancelled or the client disconnects, the session
+// is closed and no new tool calls are accepted. Calls already running are allowed to
+// finish for up to Config.ShutdownTimeout, then their contexts are cancelled. Finally
+// the global tracer provider is flushed so spans from the last calls are exported.
 func (s *Server) ServeStdio(ctx context.Context) error {
-	transport := &mcp.StdioTransport{}
-	return s.mcpServer.Run(ctx, transport)
+	return s.serve(ctx, &mcp.StdioTransport{})
+}
+
+// serve runs a session on t until ctx is cancelled or the peer disconnects, then shuts down
+func (s *Server) serve(ctx context.Context, t mcp.Transport) error {
+	ss, err := s.mcpServer.Connect(ctx, t)
+	if err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { ss.Close() })
+	defer stop()
+
+	waitErr := ss.Wait()
+	if ctx.Err() != nil {
+		// Closed by us; the transport error is expected
+		waitErr = nil
+	}
+	return errors.Join(waitErr, s.Shutdown(context.Background()))
+}
+
+// Shutdown waits for in-flight tool calls, cancelling them once Config.ShutdownTimeout
+// (or ctx) expires, and flushes the tracer. It returns ErrShutdownTimeout if calls had
+// to be cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	drained := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(drained)
+	}()
+
+	var err error
+	timeout := s.cfg.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultConfig().ShutdownTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+	case <-timer.C:
+		err = fmt.Errorf("%w: %d still running after %v", ErrShutdownTimeout, s.inflightCount.Load(), timeout)
+	case <-ctx.Done():
+		err = fmt.Errorf("%w: %v", ErrShutdownTimeout, ctx.Err())
+	}
+	if err != nil {
+		s.abortCalls()
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if flusher, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error }); ok {
+		if flushErr := flusher.ForceFlush(flushCtx); flushErr != nil {
+			err = errors.Join(err, fmt.Errorf("flush tracer: %w", flushErr))
+		}
+	}
+	return err
 }
 
 // GetMCPServer returns the underlying MCP server for custom configuration.
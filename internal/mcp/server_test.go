@@ -0,0 +1,241 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/raja-aiml/air/internal/engine"
+)
+
+// newTestServer registers a command that blocks until release is closed or its ctx ends
+func newTestServer(t *testing.T, timeout time.Duration) (*Server, chan struct{}, chan error) {
+	t.Helper()
+	release := make(chan struct{})
+	finished := make(chan error, 1)
+
+	r := engine.NewRegistry()
+	r.MustRegister(&engine.Command{
+		Name: "test.slow",
+		Execute: func(ctx context.Context, params map[string]any) (engine.Result, error) {
+			select {
+			case <-release:
+				finished <- nil
+				return engine.NewResult("done"), nil
+			case <-ctx.Done():
+				finished <- ctx.Err()
+				return engine.Result{}, ctx.Err()
+			}
+		},
+	})
+
+	cfg := DefaultConfig()
+	cfg.ShutdownTimeout = timeout
+	return NewServer(r, cfg), release, finished
+}
+
+func TestShutdownDrainsInFlightCalls(t *testing.T) {
+	s, release, finished := newTestServer(t, time.Second)
+
+	// The client's request context is cancelled (connection dropped) mid-call
+	reqCtx, cancelReq := context.WithCancel(context.Background())
+	go s.execute(reqCtx, "test.slow", nil)
+	waitInFlight(t, s)
+	cancelReq()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected clean drain, got %v", err)
+	}
+	if err := <-finished; err != nil {
+		t.Fatalf("expected call to finish, got %v", err)
+	}
+}
+
+func TestShutdownCancelsAfterTimeout(t *testing.T) {
+	s, _, finished := newTestServer(t, 20*time.Millisecond)
+
+	go s.execute(context.Background(), "test.slow", nil)
+	waitInFlight(t, s)
+
+	if err := s.Shutdown(context.Background()); !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("expected ErrShutdownTimeout, got %v", err)
+	}
+	select {
+	case err := <-finished:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected cancelled call, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected in-flight call to be cancelled")
+	}
+}
+
+func waitInFlight(t *testing.T, s *Server) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for s.inflightCount.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("tool call never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestServeReturnsOnCancel(t *testing.T) {
+	s, _, _ := newTestServer(t, time.Second)
+	serverTransport, _ := mcp.NewInMemoryTransports()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.serve(ctx, serverTransport) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("serve did not return after cancellation")
+	}
+}
+
+// trackingRegistry registers commands that record their peak concurrency
+func trackingRegistry(names ...string) (*engine.Registry, *atomic.Int64) {
+	var running, peak atomic.Int64
+	r := engine.NewRegistry()
+	for _, name := range names {
+		r.MustRegister(&engine.Command{
+			Name: name,
+			Execute: func(ctx context.Context, params map[string]any) (engine.Result, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				running.Add(-1)
+				return engine.NewResult("ok"), nil
+			},
+		})
+	}
+	return r, &peak
+}
+
+func runConcurrently(s *Server, names ...string) {
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.execute(context.Background(), name, nil)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestExclusiveGroupIsSerialized(t *testing.T) {
+	r, peak := trackingRegistry("infra.start", "infra.stop", "infra.status")
+	s := NewServer(r, DefaultConfig())
+
+	runConcurrently(s, "infra.start", "infra.stop", "infra.status")
+	if peak.Load() != 1 {
+		t.Fatalf("expected infra commands to run one at a time, peak %d", peak.Load())
+	}
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	var running, peak atomic.Int64
+	filled := make(chan struct{}) // closed once two calls run at the same time
+	var fillOnce sync.Once
+	release := make(chan struct{})
+
+	r := engine.NewRegistry()
+	names := []string{"obs.a", "obs.b", "obs.c", "obs.d"}
+	for _, name := range names {
+		r.MustRegister(&engine.Command{
+			Name: name,
+			Execute: func(ctx context.Context, params map[string]any) (engine.Result, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				if n == 2 {
+					fillOnce.Do(func() { close(filled) })
+				}
+				<-release
+				running.Add(-1)
+				return engine.NewResult("ok"), nil
+			},
+		})
+	}
+	cfg := DefaultConfig()
+	cfg.MaxConcurrentCalls = 2
+	s := NewServer(r, cfg)
+
+	done := make(chan struct{})
+	go func() {
+		runConcurrently(s, names...)
+		close(done)
+	}()
+
+	select {
+	case <-filled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected two read-only commands to run in parallel")
+	}
+	close(release)
+	<-done
+	if peak.Load() > 2 {
+		t.Fatalf("expected at most 2 concurrent calls, peak %d", peak.Load())
+	}
+}
+
+func TestAcquireHonorsCancellation(t *testing.T) {
+	r, _ := trackingRegistry("infra.start")
+	s := NewServer(r, DefaultConfig())
+	s.groupLocks["infra"] <- struct{}{} // held by another call
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.execute(ctx, "infra.start", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error while queued, got %v", err)
+	}
+	if len(s.slots) != 0 {
+		t.Fatal("expected no slot held after a cancelled wait")
+	}
+}
+
+func TestInfo(t *testing.T) {
+	r, _ := trackingRegistry("infra.start", "db.ping")
+	cfg := DefaultConfig()
+	cfg.EnvironmentInfo = func(ctx context.Context) map[string]any {
+		return map[string]any{"docker_available": true}
+	}
+	s := NewServer(r, cfg)
+
+	info := s.info(context.Background())
+	if info.Name != "air" || info.Version != cfg.Version {
+		t.Fatalf("unexpected server identity: %+v", info)
+	}
+	if len(info.Commands) != 2 || info.Commands[0].Name != "db.ping" || info.Commands[1].Name != "infra.start" {
+		t.Fatalf("expected sorted registry commands, got %+v", info.Commands)
+	}
+	if info.Environment["docker_available"] != true {
+		t.Fatalf("expected environment info, got %v", info.Environment)
+	}
+}
@@ -0,0 +1,127 @@
+package nlp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/raja-aiml/air/internal/engine"
+)
+
+// embeddingCacheVersion is bumped whenever tokenize or vectorize change,
+// invalidating caches written by older builds
+const embeddingCacheVersion = 2
+
+// embeddingCache is the on-disk form of an EmbeddingMatcher.
+type embeddingCache struct {
+	Version    int                  `json:"version"`
+	Hash       string               `json:"hash"`
+	Vocabulary map[string]int       `json:"vocabulary"`
+	IDF        []float64            `json:"idf"`
+	Vectors    map[string][]float64 `json:"vectors"`
+}
+
+// DefaultCachePath returns the embedding cache file under the user cache
+// directory, or "" if the platform has none.
+func DefaultCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "air", "embeddings.json")
+}
+
+// SaveCache writes the vocabulary and command vectors to path, keyed by a
+// hash of the command set.
+func (m *EmbeddingMatcher) SaveCache(path string) error {
+	data, err := json.Marshal(embeddingCache{
+		Version:    embeddingCacheVersion,
+		Hash:       commandsHash(m.commands),
+		Vocabulary: m.vocabulary,
+		IDF:        m.idf,
+		Vectors:    m.vectors,
+	})
+	if err != nil {
+		return fmt.Errorf("encode embedding cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	// Write then rename so a concurrent reader never sees a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create embedding cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write embedding cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write embedding cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write embedding cache: %w", err)
+	}
+	return nil
+}
+
+// LoadCache restores a matcher for commands from path. It reports false when
+// the file is missing, unreadable, or was built from a different command set.
+func LoadCache(path string, commands []*engine.Command) (*EmbeddingMatcher, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var cache embeddingCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, false
+	}
+	if cache.Version != embeddingCacheVersion || cache.Hash != commandsHash(commands) {
+		return nil, false
+	}
+	if len(cache.Vectors) != len(commands) || len(cache.IDF) != len(cache.Vocabulary) {
+		return nil, false
+	}
+	for _, vec := range cache.Vectors {
+		if len(vec) != len(cache.Vocabulary) {
+			return nil, false
+		}
+	}
+
+	return &EmbeddingMatcher{
+		commands:   commands,
+		vocabulary: cache.Vocabulary,
+		idf:        cache.IDF,
+		vectors:    cache.Vectors,
+	}, true
+}
+
+// NewCachedEmbeddingMatcher loads the matcher from path, rebuilding and
+// rewriting the cache when it is missing or stale. Cache write failures are
+// ignored; the matcher is still usable.
+func NewCachedEmbeddingMatcher(commands []*engine.Command, path string) *EmbeddingMatcher {
+	if m, ok := LoadCache(path, commands); ok {
+		return m
+	}
+	m := NewEmbeddingMatcher(commands)
+	_ = m.SaveCache(path)
+	return m
+}
+
+// commandsHash fingerprints the text the vectors are built from, independent
+// of command order
+func commandsHash(commands []*engine.Command) string {
+	sorted := append([]*engine.Command(nil), commands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	h := sha256.New()
+	for _, cmd := range sorted {
+		fmt.Fprintf(h, "%q %q %q\n", cmd.Name, cmd.Description, cmd.Examples)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
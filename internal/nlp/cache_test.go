@@ -0,0 +1,64 @@
+package nlp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/raja-aiml/air/internal/engine"
+)
+
+func cacheTestCommands() []*engine.Command {
+	return []*engine.Command{
+		{Name: "stack.up", Description: "Start the infrastructure stack", Examples: []string{"start postgres in the background"}},
+		{Name: "stack.logs", Description: "Show container logs", Examples: []string{"show jaeger logs"}},
+		{Name: "db.status", Description: "Show applied and pending migrations"},
+	}
+}
+
+func TestEmbeddingCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "air", "embeddings.json")
+	built := NewEmbeddingMatcher(cacheTestCommands())
+	if err := built.SaveCache(path); err != nil {
+		t.Fatalf("SaveCache: %v", err)
+	}
+
+	loaded, ok := LoadCache(path, cacheTestCommands())
+	if !ok {
+		t.Fatal("expected cache hit for the same command set")
+	}
+	for _, input := range []string{"start the stack", "show me the jaeger logs", "which migrations are pending", "unrelated words"} {
+		want, _ := built.Match(input)
+		got, _ := loaded.Match(input)
+		if got.Command != want.Command || got.Confidence != want.Confidence {
+			t.Fatalf("%q: loaded matcher gave %s (%v), built gave %s (%v)", input, got.Command, got.Confidence, want.Command, want.Confidence)
+		}
+	}
+}
+
+func TestEmbeddingCacheStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "embeddings.json")
+	if err := NewEmbeddingMatcher(cacheTestCommands()).SaveCache(path); err != nil {
+		t.Fatalf("SaveCache: %v", err)
+	}
+
+	changed := cacheTestCommands()
+	changed[2].Description = "Show migration status"
+	if _, ok := LoadCache(path, changed); ok {
+		t.Fatal("expected a changed description to invalidate the cache")
+	}
+	if _, ok := LoadCache(filepath.Join(t.TempDir(), "missing.json"), changed); ok {
+		t.Fatal("expected a missing cache file to miss")
+	}
+
+	// Rebuilding rewrites the cache for the new command set
+	NewCachedEmbeddingMatcher(changed, path)
+	if _, ok := LoadCache(path, changed); !ok {
+		t.Fatal("expected the rebuilt cache to match the new command set")
+	}
+}
+
+func TestDefaultParserConfigLeavesCacheOff(t *testing.T) {
+	if path := DefaultParserConfig().CachePath; path != "" {
+		t.Fatalf("expected caching to be opt-in, got CachePath %q", path)
+	}
+}
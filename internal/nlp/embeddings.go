@@ -2,7 +2,11 @@ package nlp
 
 import (
 	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/raja-aiml/air/internal/engine"
@@ -13,6 +17,7 @@ import (
 type EmbeddingMatcher struct {
 	commands   []*engine.Command
 	vocabulary map[string]int
+	idf        []float64            // vocabulary index -> inverse document frequency
 	vectors    map[string][]float64 // command name -> vector
 }
 
@@ -49,7 +54,8 @@ func NewEmbeddingMatcher(commands []*engine.Command) *EmbeddingMatcher {
 		}
 	}
 
-	// Pre-compute vectors for each command (combining name, description, examples)
+	// Each command (name, description, examples) is one document
+	documents := make(map[string][]string, len(commands))
 	for _, cmd := range commands {
 		var allText []string
 		allText = append(allText, tokenize(cmd.Name)...)
@@ -57,7 +63,31 @@ func NewEmbeddingMatcher(commands []*engine.Command) *EmbeddingMatcher {
 		for _, ex := range cmd.Examples {
 			allText = append(allText, tokenize(ex)...)
 		}
-		m.vectors[cmd.Name] = m.vectorize(allText)
+		documents[cmd.Name] = allText
+	}
+
+	// Weight tokens by how few commands use them, so words shared across
+	// many commands (like "database" or "show") count for less
+	df := make([]int, len(m.vocabulary))
+	for _, tokens := range documents {
+		seen := make(map[int]bool)
+		for _, token := range tokens {
+			idx := m.vocabulary[token]
+			if !seen[idx] {
+				seen[idx] = true
+				df[idx]++
+			}
+		}
+	}
+	m.idf = make([]float64, len(m.vocabulary))
+	for idx, count := range df {
+		// Smoothed so a token in every command keeps a small positive weight
+		m.idf[idx] = math.Log(float64(1+len(documents))/float64(1+count)) + 1
+	}
+
+	// Pre-compute vectors for each command
+	for name, tokens := range documents {
+		m.vectors[name] = m.vectorize(tokens)
 	}
 
 	return m
@@ -65,33 +95,54 @@ func NewEmbeddingMatcher(commands []*engine.Command) *EmbeddingMatcher {
 
 // Match finds the best matching command for the input.
 func (m *EmbeddingMatcher) Match(input string) (*ParseResult, error) {
+	if results := m.TopN(input, 1); len(results) > 0 {
+		return results[0], nil
+	}
+	return &ParseResult{
+		Parameters: make(map[string]any),
+		Source:     "embeddings",
+		RawInput:   input,
+	}, nil
+}
+
+// TopN returns up to n commands that match the input, best first. Commands
+// sharing no tokens with the input are left out.
+func (m *EmbeddingMatcher) TopN(input string, n int) []*ParseResult {
 	tokens := tokenize(input)
 	inputVector := m.vectorize(tokens)
 
-	var bestMatch string
-	var bestScore float64
-
+	results := make([]*ParseResult, 0, len(m.vectors))
 	for cmdName, cmdVector := range m.vectors {
 		score := cosineSimilarity(inputVector, cmdVector)
-		if score > bestScore {
-			bestScore = score
-			bestMatch = cmdName
+		if score <= 0 {
+			continue
 		}
+		results = append(results, &ParseResult{
+			Command:    cmdName,
+			Confidence: score,
+			Source:     "embeddings",
+			RawInput:   input,
+		})
+	}
+	// Ties are broken by name so the order is stable across runs
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Confidence != results[j].Confidence {
+			return results[i].Confidence > results[j].Confidence
+		}
+		return results[i].Command < results[j].Command
+	})
+	if n > 0 && len(results) > n {
+		results = results[:n]
 	}
 
 	// Extract potential parameters from input
-	params := m.extractParameters(input, bestMatch)
-
-	return &ParseResult{
-		Command:    bestMatch,
-		Parameters: params,
-		Confidence: bestScore,
-		Source:     "embeddings",
-		RawInput:   input,
-	}, nil
+	for _, r := range results {
+		r.Parameters = m.extractParameters(input, r.Command)
+	}
+	return results
 }
 
-// vectorize converts tokens to a TF vector.
+// vectorize converts tokens to a TF-IDF vector.
 func (m *EmbeddingMatcher) vectorize(tokens []string) []float64 {
 	vector := make([]float64, len(m.vocabulary))
 
@@ -105,6 +156,9 @@ func (m *EmbeddingMatcher) vectorize(tokens []string) []float64 {
 	for token, count := range tf {
 		if idx, exists := m.vocabulary[token]; exists {
 			vector[idx] = float64(count)
+			if m.idf != nil {
+				vector[idx] *= m.idf[idx]
+			}
 		}
 	}
 
@@ -141,6 +195,7 @@ func (m *EmbeddingMatcher) extractParameters(input, cmdName string) map[string]a
 	}
 
 	// Simple keyword-based parameter extraction
+	filled := make(map[string]bool) // parameter types already given a value
 	for _, p := range cmd.Parameters {
 		switch p.Type {
 		case "bool":
@@ -151,6 +206,21 @@ func (m *EmbeddingMatcher) extractParameters(input, cmdName string) map[string]a
 			if containsAny(lower, []string{"volume", "remove volume", "-v"}) && p.Name == "removeVolumes" {
 				params[p.Name] = true
 			}
+		case "int":
+			// A number in the input goes to the first int parameter only
+			if !filled["int"] {
+				if n, ok := findInt(lower); ok {
+					params[p.Name] = n
+					filled["int"] = true
+				}
+			}
+		case "duration":
+			if !filled["duration"] {
+				if d, ok := findDuration(lower); ok {
+					params[p.Name] = d.String()
+					filled["duration"] = true
+				}
+			}
 		case "string":
 			// Look for service names
 			services := []string{"postgres", "jaeger", "prometheus", "otel", "fluent"}
@@ -166,6 +236,63 @@ func (m *EmbeddingMatcher) extractParameters(input, cmdName string) map[string]a
 	return params
 }
 
+// goDurationPattern matches Go duration literals such as 5m or 1h30m
+var goDurationPattern = regexp.MustCompile(`\b(?:\d+(?:\.\d+)?(?:ns|us|µs|ms|s|m|h))+\b`)
+
+// spokenDurationPattern matches a number followed by a unit word, as in "30 seconds"
+var spokenDurationPattern = regexp.MustCompile(`\b(\d+(?:\.\d+)?)\s*(milliseconds?|millis|seconds?|secs?|minutes?|mins?|hours?|hrs?)\b`)
+
+// spokenDurationUnits maps unit words to Go duration suffixes
+var spokenDurationUnits = map[string]string{
+	"millisecond": "ms", "milliseconds": "ms", "millis": "ms",
+	"second": "s", "seconds": "s", "sec": "s", "secs": "s",
+	"minute": "m", "minutes": "m", "min": "m", "mins": "m",
+	"hour": "h", "hours": "h", "hr": "h", "hrs": "h",
+}
+
+var intPattern = regexp.MustCompile(`\b\d+\b`)
+
+// findDuration returns the first duration in lowercase input, written either
+// as a Go literal ("5m") or spoken ("wait 30 seconds")
+func findDuration(input string) (time.Duration, bool) {
+	if lit := goDurationPattern.FindString(input); lit != "" {
+		if d, err := time.ParseDuration(lit); err == nil {
+			return d, true
+		}
+	}
+	if m := spokenDurationPattern.FindStringSubmatch(input); m != nil {
+		if d, err := time.ParseDuration(m[1] + spokenDurationUnits[m[2]]); err == nil {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// findInt returns the first whole number in input that is not part of a
+// duration
+func findInt(input string) (int, bool) {
+	var durations [][]int
+	durations = append(durations, goDurationPattern.FindAllStringIndex(input, -1)...)
+	durations = append(durations, spokenDurationPattern.FindAllStringIndex(input, -1)...)
+
+	for _, loc := range intPattern.FindAllStringIndex(input, -1) {
+		inDuration := false
+		for _, d := range durations {
+			if loc[0] >= d[0] && loc[1] <= d[1] {
+				inDuration = true
+				break
+			}
+		}
+		if inDuration {
+			continue
+		}
+		if n, err := strconv.Atoi(input[loc[0]:loc[1]]); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
 // tokenize splits text into normalized tokens.
 func tokenize(text string) []string {
 	text = strings.ToLower(text)
@@ -0,0 +1,142 @@
+package nlp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raja-aiml/air/internal/commands"
+	"github.com/raja-aiml/air/internal/engine"
+)
+
+func dbCommands() []*engine.Command {
+	return []*engine.Command{
+		{Name: "db.ping", Description: "Check database connectivity", Examples: []string{"ping database", "check database connection", "is database running", "test database"}},
+		{Name: "db.query", Description: "Execute a SQL query", Examples: []string{"run query", "execute sql", "query database"}},
+		{Name: "db.status", Description: "Show database migration status", Examples: []string{"show database status", "which migrations are applied"}},
+		{Name: "stack.logs", Description: "Show container logs", Examples: []string{"show logs"}},
+	}
+}
+
+// queryMargin is how far db.query's score is ahead of db.ping's for input
+func queryMargin(m *EmbeddingMatcher, input string) float64 {
+	in := m.vectorize(tokenize(input))
+	return cosineSimilarity(in, m.vectors["db.query"]) - cosineSimilarity(in, m.vectors["db.ping"])
+}
+
+func TestIDFImprovesDiscrimination(t *testing.T) {
+	weighted := NewEmbeddingMatcher(dbCommands())
+
+	// The same matcher with IDF disabled gives the previous plain TF scores
+	plain := &EmbeddingMatcher{commands: weighted.commands, vocabulary: weighted.vocabulary, vectors: map[string][]float64{}}
+	for _, cmd := range plain.commands {
+		plain.vectors[cmd.Name] = plain.vectorize(append(append(tokenize(cmd.Name), tokenize(cmd.Description)...), tokenize(strings.Join(cmd.Examples, " "))...))
+	}
+
+	const input = "run a sql query"
+	before, after := queryMargin(plain, input), queryMargin(weighted, input)
+	if after <= 0 {
+		t.Fatalf("expected db.query to rank above db.ping, margin %v", after)
+	}
+	t.Logf("db.query margin: TF %.3f, TF-IDF %.3f", before, after)
+	if after <= before {
+		t.Fatalf("expected IDF to widen the margin: TF %v, TF-IDF %v", before, after)
+	}
+
+	result, err := weighted.Match(input)
+	if err != nil || result.Command != "db.query" {
+		t.Fatalf("expected db.query, got %+v (%v)", result, err)
+	}
+}
+
+func TestIDFDownweightsSharedTokens(t *testing.T) {
+	m := NewEmbeddingMatcher(dbCommands())
+	shared, unique := m.idf[m.vocabulary["database"]], m.idf[m.vocabulary["sql"]]
+	if shared >= unique {
+		t.Fatalf("expected \"database\" (in most commands) to weigh less than \"sql\": %v >= %v", shared, unique)
+	}
+}
+
+func TestCandidatesRegisteredCommands(t *testing.T) {
+	registry := engine.NewRegistry()
+	commands.NewDBCommands("").Register(registry)
+	commands.NewObsCommands().Register(registry)
+	commands.NewLintCommands().Register(registry)
+
+	// A threshold above any local score keeps Parse on the embeddings path
+	// with no LLM configured
+	p := &Parser{embeddings: NewEmbeddingMatcher(registry.All()), registry: registry, threshold: 2}
+
+	candidates, err := p.Candidates(context.Background(), "check database migrations", 3)
+	if err != nil {
+		t.Fatalf("Candidates: %v", err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("expected 3 candidates, got %d", len(candidates))
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Confidence > candidates[i-1].Confidence {
+			t.Fatalf("candidates not sorted by confidence: %v then %v", candidates[i-1].Confidence, candidates[i].Confidence)
+		}
+	}
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if _, ok := registry.Get(c.Command); seen[c.Command] || !ok {
+			t.Fatalf("unexpected candidate %q in %v", c.Command, candidates)
+		}
+		seen[c.Command] = true
+	}
+
+	best, err := p.Parse(context.Background(), "check database migrations")
+	if err != nil || best.Command != candidates[0].Command {
+		t.Fatalf("expected Parse to return the top candidate %q, got %+v (%v)", candidates[0].Command, best, err)
+	}
+
+	if _, err := p.Candidates(context.Background(), "zzz qqq", 3); err == nil {
+		t.Fatal("expected an error when nothing matches")
+	}
+}
+
+func TestExtractNumericParameters(t *testing.T) {
+	m := NewEmbeddingMatcher([]*engine.Command{
+		{Name: "infra.start", Description: "Start infrastructure services", Parameters: []engine.Parameter{
+			{Name: "timeout", Type: "duration"},
+		}},
+		{Name: "db.rollback", Description: "Roll back migrations", Parameters: []engine.Parameter{
+			{Name: "version", Type: "int"},
+			{Name: "steps", Type: "int"},
+			{Name: "timeout", Type: "duration"},
+		}},
+	})
+
+	for _, tc := range []struct {
+		input, cmd string
+		want       map[string]any
+	}{
+		{"start infrastructure with timeout 5m", "infra.start", map[string]any{"timeout": "5m0s"}},
+		{"start infrastructure and wait 30 seconds", "infra.start", map[string]any{"timeout": "30s"}},
+		{"start infrastructure, give it 1h30m", "infra.start", map[string]any{"timeout": "1h30m0s"}},
+		{"roll back to version 3", "db.rollback", map[string]any{"version": 3}},
+		{"roll back to 2 within 10 minutes", "db.rollback", map[string]any{"version": 2, "timeout": "10m0s"}},
+		{"start infrastructure", "infra.start", map[string]any{}},
+	} {
+		got := m.extractParameters(strings.ToLower(tc.input), tc.cmd)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%q: got %v, want %v", tc.input, got, tc.want)
+		}
+		for k, v := range tc.want {
+			if got[k] != v {
+				t.Fatalf("%q: %s = %v, want %v", tc.input, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestExtractedDurationParsesAsParam(t *testing.T) {
+	m := NewEmbeddingMatcher([]*engine.Command{{Name: "infra.start", Parameters: []engine.Parameter{{Name: "timeout", Type: "duration"}}}})
+	params := engine.Params(m.extractParameters("start with timeout 5m", "infra.start"))
+	if d := params.Duration("timeout", 0); d != 5*time.Minute {
+		t.Fatalf("expected 5m, got %v", d)
+	}
+}
@@ -0,0 +1,176 @@
+package nlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/raja-aiml/air/internal/engine"
+)
+
+// DefaultOllamaURL is the address of a local Ollama server.
+const DefaultOllamaURL = "http://localhost:11434"
+
+// OllamaProvider implements Provider using a local Ollama server.
+type OllamaProvider struct {
+	client    *http.Client
+	baseURL   string
+	model     string
+	maxTokens int
+}
+
+// NewOllamaProvider creates a new Ollama provider. The server address comes
+// from cfg.BaseURL, then OLLAMA_HOST, then DefaultOllamaURL.
+func NewOllamaProvider(cfg ProviderConfig) (*OllamaProvider, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("OLLAMA_HOST")
+	}
+	if baseURL == "" {
+		baseURL = DefaultOllamaURL
+	}
+	// OLLAMA_HOST is commonly set as host:port
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "llama3.1"
+	}
+
+	return &OllamaProvider{
+		client:    &http.Client{Timeout: cfg.Timeout},
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		model:     model,
+		maxTokens: cfg.MaxTokens,
+	}, nil
+}
+
+func (o *OllamaProvider) Name() string {
+	return "ollama"
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaTool struct {
+	Type     string             `json:"type"`
+	Function ollamaToolFunction `json:"function"`
+}
+
+type ollamaToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	Tools    []ollamaTool    `json:"tools"`
+	Stream   bool            `json:"stream"`
+	Options  map[string]any  `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message ollamaMessage `json:"message"`
+	Error   string        `json:"error"`
+}
+
+func (o *OllamaProvider) Parse(ctx context.Context, input string, commands []*engine.Command) (*ParseResult, error) {
+	// Build function tools from commands
+	tools := make([]ollamaTool, len(commands))
+	for i, cmd := range commands {
+		tools[i] = ollamaTool{
+			Type: "function",
+			Function: ollamaToolFunction{
+				Name:        cmd.Name,
+				Description: cmd.Description,
+				Parameters:  cmd.ParameterSchema(),
+			},
+		}
+	}
+
+	chatReq := ollamaChatRequest{
+		Model: o.model,
+		Messages: []ollamaMessage{
+			{Role: "system", Content: buildSystemPrompt(commands)},
+			{Role: "user", Content: input},
+		},
+		Tools: tools,
+	}
+	if o.maxTokens > 0 {
+		chatReq.Options = map[string]any{"num_predict": o.maxTokens}
+	}
+
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("encode ollama request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create ollama request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read ollama response: %w", err)
+	}
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(data, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse ollama response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if chatResp.Error != "" {
+			return nil, fmt.Errorf("ollama API error: %s", chatResp.Error)
+		}
+		return nil, fmt.Errorf("ollama API error: unexpected status %d", resp.StatusCode)
+	}
+
+	// Extract tool call from response
+	if len(chatResp.Message.ToolCalls) > 0 {
+		call := chatResp.Message.ToolCalls[0].Function
+		params := call.Arguments
+		if params == nil {
+			params = make(map[string]any)
+		}
+
+		return &ParseResult{
+			Command:    call.Name,
+			Parameters: params,
+			Confidence: 1.0,
+			Source:     "ollama",
+			RawInput:   input,
+		}, nil
+	}
+
+	// No tool call - return text response as error
+	if chatResp.Message.Content != "" {
+		return nil, fmt.Errorf("could not parse command: %s", chatResp.Message.Content)
+	}
+
+	return nil, fmt.Errorf("no valid response from Ollama")
+}
@@ -0,0 +1,81 @@
+package nlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raja-aiml/air/internal/engine"
+)
+
+func TestOllamaProviderParse(t *testing.T) {
+	var got ollamaChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"db.rollback","arguments":{"version":2}}}]},"done":true}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewOllamaProvider(ProviderConfig{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewOllamaProvider: %v", err)
+	}
+	commands := []*engine.Command{{
+		Name:        "db.rollback",
+		Description: "Roll back migrations",
+		Parameters:  []engine.Parameter{{Name: "version", Type: "int", Required: true}},
+	}}
+
+	result, err := p.Parse(context.Background(), "roll back to version 2", commands)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if result.Command != "db.rollback" || result.Source != "ollama" || result.Parameters["version"] != float64(2) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if got.Model != "llama3.1" || got.Stream || len(got.Tools) != 1 || got.Tools[0].Function.Name != "db.rollback" {
+		t.Fatalf("unexpected request %+v", got)
+	}
+	if _, ok := got.Tools[0].Function.Parameters["properties"]; !ok {
+		t.Fatalf("expected tool parameters from ParameterSchema, got %v", got.Tools[0].Function.Parameters)
+	}
+}
+
+func TestOllamaProviderTextResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"role":"assistant","content":"which database?"},"done":true}`))
+	}))
+	defer srv.Close()
+
+	p, _ := NewOllamaProvider(ProviderConfig{BaseURL: srv.URL})
+	_, err := p.Parse(context.Background(), "do the thing", nil)
+	if err == nil || !strings.Contains(err.Error(), "which database?") {
+		t.Fatalf("expected model text in error, got %v", err)
+	}
+}
+
+func TestOllamaHostWithoutScheme(t *testing.T) {
+	t.Setenv("OLLAMA_HOST", "127.0.0.1:11434")
+	p, err := NewOllamaProvider(ProviderConfig{})
+	if err != nil {
+		t.Fatalf("NewOllamaProvider: %v", err)
+	}
+	if p.baseURL != "http://127.0.0.1:11434" {
+		t.Fatalf("unexpected base URL %q", p.baseURL)
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	auto, err := NewAutoProvider(DefaultConfig())
+	if err != nil || auto.Name() != "auto:ollama" {
+		t.Fatalf("expected auto provider to pick ollama, got %v, %v", auto, err)
+	}
+}
@@ -19,9 +19,11 @@ type Parser struct {
 type ParserConfig struct {
 	Provider            ProviderConfig
 	ConfidenceThreshold float64 // Minimum confidence for local matching (default: 0.7)
+	CachePath           string  // Embedding cache file (empty disables caching)
 }
 
-// DefaultParserConfig returns default parser configuration.
+// DefaultParserConfig returns default parser configuration. Caching is off;
+// set CachePath (e.g. to DefaultCachePath()) to reuse embeddings between runs.
 func DefaultParserConfig() ParserConfig {
 	return ParserConfig{
 		Provider:            DefaultConfig(),
@@ -35,8 +37,13 @@ func NewParser(registry *engine.Registry, cfg ParserConfig) (*Parser, error) {
 		cfg.ConfidenceThreshold = 0.7
 	}
 
-	// Initialize embeddings matcher
-	embeddings := NewEmbeddingMatcher(registry.All())
+	// Initialize embeddings matcher, reusing cached vectors when the command set is unchanged
+	var embeddings *EmbeddingMatcher
+	if cfg.CachePath != "" {
+		embeddings = NewCachedEmbeddingMatcher(registry.All(), cfg.CachePath)
+	} else {
+		embeddings = NewEmbeddingMatcher(registry.All())
+	}
 
 	// Initialize LLM provider (may fail if no API key)
 	provider, err := NewProvider(cfg.Provider)
@@ -55,30 +62,56 @@ func NewParser(registry *engine.Registry, cfg ParserConfig) (*Parser, error) {
 
 // Parse interprets natural language input and returns the matching command.
 func (p *Parser) Parse(ctx context.Context, input string) (*ParseResult, error) {
+	results, err := p.Candidates(ctx, input, 1)
+	if err != nil {
+		return nil, err
+	}
+	return results[0], nil
+}
+
+// Candidates returns up to n matching commands sorted by confidence, so
+// callers can offer alternatives when the best match is uncertain. When no
+// local match reaches the threshold, the LLM's choice (if any) comes first.
+func (p *Parser) Candidates(ctx context.Context, input string, n int) ([]*ParseResult, error) {
 	// Step 1: Try local embeddings first (fast, free, offline)
-	result, err := p.embeddings.Match(input)
-	if err == nil && result.Confidence >= p.threshold {
-		return result, nil
+	results := p.embeddings.TopN(input, n)
+	if len(results) > 0 && results[0].Confidence >= p.threshold {
+		return results, nil
 	}
 
 	// Step 2: Fall back to LLM for ambiguous cases
 	if p.provider != nil {
 		llmResult, err := p.provider.Parse(ctx, input, p.registry.All())
 		if err == nil {
-			return llmResult, nil
+			return prependCandidate(llmResult, results, n), nil
 		}
 		// Log LLM error but don't fail - return best embedding match
 		fmt.Printf("LLM fallback failed: %v\n", err)
 	}
 
-	// Step 3: Return best embedding match even if below threshold
-	if result != nil && result.Command != "" {
-		return result, nil
+	// Step 3: Return best embedding matches even if below threshold
+	if len(results) > 0 {
+		return results, nil
 	}
 
 	return nil, fmt.Errorf("could not parse command from input: %s", input)
 }
 
+// prependCandidate puts first ahead of results, dropping any other result
+// for the same command and keeping at most n
+func prependCandidate(first *ParseResult, results []*ParseResult, n int) []*ParseResult {
+	out := []*ParseResult{first}
+	for _, r := range results {
+		if r.Command != first.Command {
+			out = append(out, r)
+		}
+	}
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
 // ParseWithoutLLM forces local-only parsing (useful for offline mode).
 func (p *Parser) ParseWithoutLLM(input string) (*ParseResult, error) {
 	return p.embeddings.Match(input)
@@ -89,6 +122,11 @@ func (p *Parser) HasLLMProvider() bool {
 	return p.provider != nil
 }
 
+// Threshold returns the minimum confidence for trusting a local match.
+func (p *Parser) Threshold() float64 {
+	return p.threshold
+}
+
 // ProviderName returns the name of the active LLM provider.
 func (p *Parser) ProviderName() string {
 	if p.provider == nil {
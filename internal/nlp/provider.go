@@ -24,14 +24,15 @@ type ParseResult struct {
 	Command    string
 	Parameters map[string]any
 	Confidence float64
-	Source     string // "embeddings", "anthropic", "openai"
+	Source     string // "embeddings", "anthropic", "openai", "ollama"
 	RawInput   string
 }
 
 // ProviderConfig holds configuration for LLM providers.
 type ProviderConfig struct {
-	Type      string        // "anthropic", "openai", "auto"
+	Type      string        // "anthropic", "openai", "ollama", "auto"
 	APIKey    string        // API key (if empty, uses provider-specific env var)
+	BaseURL   string        // Server URL for ollama (if empty, uses OLLAMA_HOST or the local default)
 	Model     string        // Model name (if empty, uses default)
 	MaxTokens int           // Max tokens for response
 	Timeout   time.Duration // Request timeout
@@ -53,6 +54,8 @@ func NewProvider(cfg ProviderConfig) (Provider, error) {
 		return NewAnthropicProvider(cfg)
 	case "openai":
 		return NewOpenAIProvider(cfg)
+	case "ollama":
+		return NewOllamaProvider(cfg)
 	case "auto":
 		return NewAutoProvider(cfg)
 	default:
@@ -65,7 +68,8 @@ type AutoProvider struct {
 	provider Provider
 }
 
-// NewAutoProvider creates a provider by detecting available API keys.
+// NewAutoProvider creates a provider by detecting available API keys,
+// falling back to a local Ollama server when OLLAMA_HOST is set.
 func NewAutoProvider(cfg ProviderConfig) (*AutoProvider, error) {
 	// Try Anthropic first
 	if key := getAPIKey("ANTHROPIC_API_KEY", cfg.APIKey); key != "" {
@@ -87,7 +91,15 @@ func NewAutoProvider(cfg ProviderConfig) (*AutoProvider, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("no LLM API key found (set ANTHROPIC_API_KEY or OPENAI_API_KEY)")
+	// Fall back to a local Ollama server
+	if os.Getenv("OLLAMA_HOST") != "" {
+		p, err := NewOllamaProvider(cfg)
+		if err == nil {
+			return &AutoProvider{provider: p}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no LLM provider found (set ANTHROPIC_API_KEY, OPENAI_API_KEY or OLLAMA_HOST)")
 }
 
 func (a *AutoProvider) Name() string {
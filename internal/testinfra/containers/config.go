@@ -4,47 +4,158 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
+	"github.com/raja-aiml/air/internal/foundation/terminal"
 	"gopkg.in/yaml.v3"
 )
 
+// Backend selects how test infrastructure is provisioned
+type Backend string
+
+const (
+	// BackendCompose starts the full stack with Docker Compose (default)
+	BackendCompose Backend = "compose"
+
+	// BackendEmbedded starts an embedded Postgres without Docker.
+	// Observability services are replaced by a local HTTP stub.
+	BackendEmbedded Backend = "embedded"
+)
+
+// MetricsBackend selects where verification looks for the server's metrics
+type MetricsBackend string
+
+const (
+	// MetricsBackendPrometheus checks that Prometheus scraped the collector (default)
+	MetricsBackendPrometheus MetricsBackend = "prometheus"
+
+	// MetricsBackendOTLP checks that the collector pushed metrics over OTLP to
+	// OtelMetricsExporterEndpoint, by querying that backend when
+	// OTLPMetricsQueryURL is set and otherwise from the collector's own
+	// sent-metric counters
+	MetricsBackendOTLP MetricsBackend = "otlp"
+)
+
+// OTLPMetricsExporter is the collector exporter that pushes metrics to
+// OtelMetricsExporterEndpoint; its self-telemetry counters carry this name
+const OTLPMetricsExporter = "otlp/metrics"
+
+// DefaultOtelConfigPath is the static collector config generated from the
+// built-in template (see RenderStaticOtelConfig)
+const DefaultOtelConfigPath = "config/observability/otel-collector-config.yaml"
+
+// PhaseBudgets caps how long each verification phase may run.
+// A zero budget leaves that phase bounded only by the overall context deadline.
+type PhaseBudgets struct {
+	Infrastructure time.Duration // Start containers and wait for readiness
+	Health         time.Duration // Deep health checks
+	Server         time.Duration // Start the application server and wait for schema
+	Traffic        time.Duration // Generate WebSocket traffic
+	Verification   time.Duration // Check traces and metrics
+	Cleanup        time.Duration // Reserved before the overall deadline for teardown
+}
+
+// DefaultPhaseBudgets returns budgets sized for a cold start on a CI runner
+func DefaultPhaseBudgets() PhaseBudgets {
+	return PhaseBudgets{
+		Infrastructure: 3 * time.Minute,
+		Health:         time.Minute,
+		Server:         2 * time.Minute,
+		Traffic:        time.Minute,
+		Verification:   2 * time.Minute,
+		Cleanup:        time.Minute,
+	}
+}
+
 // Config holds all configuration for infrastructure setup
 type Config struct {
 	// Project identification
 	ProjectName string
 	ServiceName string
 
+	// Infrastructure backend (compose or embedded)
+	Backend Backend
+
 	// Database configuration
 	DBUser     string
 	DBPassword string
 	DBName     string
 
 	// Server configuration
-	ServerPort     string
-	ServerCommand  []string // e.g., []string{"go", "run", "cmd/server/main.go"}
-	HealthEndpoint string   // e.g., "/healthz"
+	ServerPort         string
+	ServerCommand      []string // e.g., []string{"go", "run", "cmd/server/main.go"}
+	HealthEndpoint     string   // e.g., "/healthz"
+	ServerLogPath      string   // Server output; parent directories are created
+	MirrorServerOutput bool     // Also copy StartServerInBackground output to stdout/stderr (StartServer always does)
 
 	// JWT configuration
-	JWTSecret   string
-	JWTIssuer   string
-	JWTAudience string
+	JWTSecret         string
+	JWTIssuer         string
+	JWTAudience       string
+	JWTPrivateKeyPath string // PEM RSA key; when set, traffic tokens are RS256 instead of HS256 with JWTSecret
 
 	// WebSocket configuration
 	WSEndpoint string // e.g., "/ws"
 
 	// OTEL configuration
-	OTELEnabled     bool
-	OTELServiceName string
-	OTELEnvironment string
+	OTELEnabled          bool
+	OTELServiceName      string
+	OTELEnvironment      string
+	OTELRequireCollector bool // Fail server startup if the collector's OTLP port stays unreachable; otherwise warn
+
+	// Jaeger query configuration
+	JaegerLookback       time.Duration // Search window when the traffic start time is unknown
+	JaegerLookbackMargin time.Duration // Slack before the traffic start time (clock skew, batching)
+	JaegerQueryLimit     int           // Maximum traces returned per Jaeger query
+
+	// Verification time budgets
+	PhaseBudgets PhaseBudgets
+
+	// Output
+	PlainOutput    bool // ASCII-only report output (defaults to on under CI or NO_COLOR)
+	CIMode         bool // Deprecated: use PlainOutput; either one turns plain output on
+	FailOnWarnings bool // Treat warning-severity steps as a failed run (see ErrVerificationWarnings)
+
+	// Artifacts
+	DumpDir string // When set, verification writes trace.json and metrics.json here
+
+	// OTEL collector config templating (see RenderOtelConfig)
+	OtelConfigTemplate        string  // Custom collector config template; empty uses the built-in one
+	OtelTraceExporterEndpoint string  // Where the collector sends traces (e.g. jaeger:4317, tempo:4317)
+	OtelTraceExporterInsecure bool    // Disable TLS for the trace exporter
+	OtelSamplingPercentage    float64 // Percentage of traces kept by the collector (100 = all)
+
+	// OTLP metrics export; the collector pushes metrics through the
+	// OTLPMetricsExporter exporter too when OtelMetricsExporterEndpoint is set
+	MetricsBackend              MetricsBackend // Where verification checks metrics (default prometheus)
+	OtelMetricsExporterEndpoint string         // OTLP gRPC destination for metrics (e.g. mimir:4317)
+	OtelMetricsExporterInsecure bool           // Disable TLS for the metrics exporter
+	OTLPMetricsQueryURL         string         // Prometheus-compatible query API of that backend; empty checks collector counters
+
+	// Prometheus config templating (see RenderPrometheusConfig)
+	PrometheusConfigTemplate string         // Custom Prometheus config template (format durations with promDuration); empty uses the built-in one
+	PrometheusScrapeInterval time.Duration  // Global scrape and evaluation interval
+	PrometheusScrapeTargets  []ScrapeTarget // Scrape jobs; append to monitor additional services
 
 	// Docker Compose configuration
 	ComposeFilePath string // Path to docker-compose.yml
-	OtelConfigPath  string // Path to otel-collector-config.yaml
+	OtelConfigPath  string // Static otel-collector-config.yaml; a custom one cannot be used with MetricsBackendOTLP
+	RandomizePorts  bool   // Bind containers and the server to free host ports, for parallel runs
+
+	// ImageDigests pins tagged compose images to the registry digest
+	// ("sha256:...") they must resolve to, by service name; a mismatch is
+	// reported as a warning once the stack is healthy
+	ImageDigests map[string]string
 
 	// File paths
 	MigrationsDir string // Path to database migrations
 	SeedsDir      string // Path to database seeds
 
+	// SchemaTables must all exist before the server counts as ready (its
+	// migrations have run); see WaitForSchema
+	SchemaTables []string
+
 	// Parsed from docker-compose.yml
 	ContainerImages map[string]string // Service name -> Docker image
 	NetworkName     string            // Docker network name
@@ -53,6 +164,38 @@ type Config struct {
 	ExtraEnv map[string]string
 }
 
+// PlainMode reports whether report output should be ASCII-only, via
+// PlainOutput or the deprecated CIMode
+func (c *Config) PlainMode() bool {
+	return c.PlainOutput || c.CIMode
+}
+
+// Validate rejects settings that cannot work together, so a bad combination
+// fails before any container starts rather than in the last verification phase
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case "", BackendCompose, BackendEmbedded:
+	default:
+		return fmt.Errorf("unknown infrastructure backend: %s", c.Backend)
+	}
+
+	switch c.MetricsBackend {
+	case "", MetricsBackendPrometheus:
+	case MetricsBackendOTLP:
+		if c.OtelMetricsExporterEndpoint == "" {
+			return fmt.Errorf("metrics backend %q needs OtelMetricsExporterEndpoint (AIR_OTEL_METRICS_ENDPOINT)", c.MetricsBackend)
+		}
+		if c.OtelConfigPath != "" && c.OtelConfigPath != DefaultOtelConfigPath {
+			return fmt.Errorf("metrics backend %q needs the rendered collector config, which defines the %s exporter; "+
+				"custom OtelConfigPath %s is a static file without it: unset OtelConfigPath and customize OtelConfigTemplate instead",
+				c.MetricsBackend, OTLPMetricsExporter, c.OtelConfigPath)
+		}
+	default:
+		return fmt.Errorf("unknown metrics backend: %s", c.MetricsBackend)
+	}
+	return nil
+}
+
 // DockerComposeFile represents docker-compose.yml structure
 type DockerComposeFile struct {
 	Services map[string]struct {
@@ -69,20 +212,42 @@ func DefaultConfig() *Config {
 	cfg := &Config{
 		// File paths (relative from project root)
 		ComposeFilePath: "config/docker/docker-compose.yml",
-		OtelConfigPath:  "config/observability/otel-collector-config.yaml",
+		OtelConfigPath:  DefaultOtelConfigPath,
 		MigrationsDir:   "config/database/migrations",
 		SeedsDir:        "config/database/seeds",
+		SchemaTables:    slices.Clone(defaultSchemaTables),
 
 		// Server defaults (not in docker-compose)
 		ServerPort:      "8080",
 		ServerCommand:   []string{"go", "run", "cmd/server/main.go"},
 		HealthEndpoint:  "/healthz",
+		ServerLogPath:   DefaultServerLogPath,
 		WSEndpoint:      "/ws",
 		OTELEnabled:     true,
 		OTELServiceName: "skillflow-backend",
 		OTELEnvironment: "test",
+
+		OtelTraceExporterEndpoint: getEnvDefault("AIR_OTEL_TRACE_ENDPOINT", "jaeger:4317"),
+		OtelTraceExporterInsecure: true,
+		OtelSamplingPercentage:    100,
+
+		MetricsBackend:              MetricsBackend(getEnvDefault("AIR_METRICS_BACKEND", string(MetricsBackendPrometheus))),
+		OtelMetricsExporterEndpoint: os.Getenv("AIR_OTEL_METRICS_ENDPOINT"),
+		OtelMetricsExporterInsecure: true,
+		OTLPMetricsQueryURL:         os.Getenv("AIR_OTLP_METRICS_QUERY_URL"),
+
+		PrometheusScrapeInterval: 5 * time.Second,
+		PrometheusScrapeTargets:  DefaultScrapeTargets(),
+
+		JaegerLookback:       5 * time.Minute,
+		JaegerLookbackMargin: 30 * time.Second,
+		JaegerQueryLimit:     100,
+		PhaseBudgets:         DefaultPhaseBudgets(),
+		PlainOutput:          terminal.DetectEnvironment(false).Plain,
+
 		ExtraEnv:        make(map[string]string),
 		ContainerImages: make(map[string]string),
+		Backend:         Backend(getEnvDefault("AIR_TEST_BACKEND", string(BackendCompose))),
 	}
 
 	// Load configuration from docker-compose.yml
@@ -165,3 +330,11 @@ func (c *Config) LoadFromDockerCompose() error {
 
 	return nil
 }
+
+// getEnvDefault returns the environment variable value or a default if unset
+func getEnvDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,45 @@
+package containers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"defaults", Config{}, ""},
+		{"prometheus", Config{MetricsBackend: MetricsBackendPrometheus, OtelConfigPath: "custom.yaml"}, ""},
+		{"otlp", Config{MetricsBackend: MetricsBackendOTLP, OtelMetricsExporterEndpoint: "mimir:4317", OtelConfigPath: DefaultOtelConfigPath}, ""},
+		{"otlp without endpoint", Config{MetricsBackend: MetricsBackendOTLP}, "needs OtelMetricsExporterEndpoint"},
+		{"otlp with static config", Config{MetricsBackend: MetricsBackendOTLP, OtelMetricsExporterEndpoint: "mimir:4317", OtelConfigPath: "custom.yaml"}, "custom OtelConfigPath custom.yaml"},
+		{"unknown metrics backend", Config{MetricsBackend: "graphite"}, "unknown metrics backend"},
+		{"unknown backend", Config{Backend: "k8s"}, "unknown infrastructure backend"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfigPlainModeHonorsDeprecatedCIMode(t *testing.T) {
+	if (&Config{}).PlainMode() {
+		t.Fatal("expected decorated output by default")
+	}
+	if !(&Config{PlainOutput: true}).PlainMode() || !(&Config{CIMode: true}).PlainMode() {
+		t.Fatal("expected PlainOutput and CIMode to each turn plain output on")
+	}
+}
@@ -0,0 +1,120 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+// Start provisions infrastructure using the backend selected in cfg.Backend
+func Start(ctx context.Context, cfg *Config) (*Infrastructure, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	switch cfg.Backend {
+	case "", BackendCompose:
+		return StartWithCompose(ctx, cfg)
+	case BackendEmbedded:
+		return StartEmbedded(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown infrastructure backend: %s", cfg.Backend)
+	}
+}
+
+// StartEmbedded starts an embedded Postgres (no Docker required) for DB-only tests.
+// Jaeger, Prometheus and the OTEL collector are replaced by a single HTTP stub that
+// answers health and API endpoints with empty payloads, so readiness checks pass
+// but no telemetry is stored. Extensions such as pgvector are not available.
+func StartEmbedded(ctx context.Context, cfg *Config) (*Infrastructure, error) {
+	reserved, err := reservePort()
+	if err != nil {
+		return nil, fmt.Errorf("allocate postgres port: %w", err)
+	}
+	// Embedded Postgres binds the port itself
+	reserved.Release()
+	port := reserved.Port
+
+	runtimeDir, err := os.MkdirTemp("", "air-embedded-pg-")
+	if err != nil {
+		return nil, fmt.Errorf("create runtime dir: %w", err)
+	}
+
+	pg := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		Username(cfg.DBUser).
+		Password(cfg.DBPassword).
+		Database(cfg.DBName).
+		Port(uint32(port)).
+		RuntimePath(filepath.Join(runtimeDir, "runtime")).
+		DataPath(filepath.Join(runtimeDir, "data")).
+		StartTimeout(60 * time.Second).
+		Logger(io.Discard))
+
+	if err := pg.Start(); err != nil {
+		os.RemoveAll(runtimeDir)
+		return nil, fmt.Errorf("start embedded postgres: %w", err)
+	}
+
+	stub, stubURL, err := startObservabilityStub()
+	if err != nil {
+		pg.Stop()
+		os.RemoveAll(runtimeDir)
+		return nil, fmt.Errorf("start observability stub: %w", err)
+	}
+
+	infra := &Infrastructure{
+		PostgresURL:    fmt.Sprintf("postgres://%s:%s@localhost:%d/%s?sslmode=disable", cfg.DBUser, cfg.DBPassword, port, cfg.DBName),
+		JaegerURL:      stubURL,
+		PrometheusURL:  stubURL,
+		OtelHealthURL:  stubURL + "/",
+		OtelMetricsURL: stubURL + "/metrics",
+
+		OtelInternalMetricsURL: stubURL + "/metrics",
+	}
+
+	infra.Cleanup = func() {
+		StopServer()
+		stub.Close()
+		pg.Stop()
+		os.RemoveAll(runtimeDir)
+	}
+
+	if err := WaitForPostgres(ctx, infra.PostgresURL); err != nil {
+		infra.Cleanup()
+		return nil, fmt.Errorf("postgres wait: %w", err)
+	}
+
+	return infra, nil
+}
+
+// startObservabilityStub serves minimal Jaeger/Prometheus/OTEL responses on a random port
+func startObservabilityStub() (*http.Server, string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/services", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	mux.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+
+	return server, "http://" + listener.Addr().String(), nil
+}
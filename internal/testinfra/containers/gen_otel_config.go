@@ -0,0 +1,29 @@
+//go:build ignore
+
+// gen_otel_config writes the static OTEL collector config from the built-in
+// template, so the file compose mounts by default never drifts from what
+// air renders. Run through go generate ./internal/testinfra/containers.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: go run gen_otel_config.go <output path>")
+		os.Exit(2)
+	}
+	out, err := containers.RenderStaticOtelConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(os.Args[1], out, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
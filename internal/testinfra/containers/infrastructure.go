@@ -3,6 +3,7 @@ package containers
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -11,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/raja-aiml/air/internal/foundation/compose"
@@ -18,8 +20,6 @@ import (
 	_ "github.com/lib/pq"
 )
 
-var serverCmd *exec.Cmd
-
 type Infrastructure struct {
 	// URLs
 	PostgresURL    string
@@ -28,6 +28,8 @@ type Infrastructure struct {
 	OtelEndpoint   string
 	OtelHealthURL  string // OTEL collector health endpoint
 	OtelMetricsURL string // OTEL collector metrics endpoint
+	// OTEL collector self-telemetry (otelcol_* metrics)
+	OtelInternalMetricsURL string
 
 	// Docker SDK container IDs
 	PostgresContainerID   string
@@ -37,7 +39,13 @@ type Infrastructure struct {
 	DockerClient          *compose.Service
 
 	// Server process
-	ServerCancel context.CancelFunc
+	ServerPort    string // Host port the application server listens on, once started
+	ServerCancel  context.CancelFunc
+	ServerLogPath string // Output of a server started by StartServerInBackground
+
+	// Closed when a background server exits before its context is cancelled
+	serverExited  chan struct{}
+	serverExitErr error
 
 	// Cleanup function
 	Cleanup func()
@@ -45,47 +53,179 @@ type Infrastructure struct {
 
 // StartWithCompose starts infrastructure using Docker Compose via Docker SDK
 func StartWithCompose(ctx context.Context, cfg *Config) (*Infrastructure, error) {
+	// Render collector and Prometheus configs; the compose file mounts them
+	// via AIR_OTEL_CONFIG and AIR_PROMETHEUS_CONFIG
+	otelConfig, removeOtelConfig, err := WriteOtelConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("render otel collector config: %w", err)
+	}
+
+	promConfig, removePromConfig, err := WritePrometheusConfig(cfg)
+	if err != nil {
+		removeOtelConfig()
+		return nil, fmt.Errorf("render prometheus config: %w", err)
+	}
+	removeConfigs := func() {
+		removeOtelConfig()
+		removePromConfig()
+	}
+
 	// Use compose service (Docker SDK)
 	svc, err := compose.New(compose.Config{
 		ComposeFilePath: cfg.ComposeFilePath,
 		ProjectName:     cfg.ProjectName,
-		Env:             make(map[string]string),
+		RandomizePorts:  cfg.RandomizePorts,
+		ImageDigests:    cfg.ImageDigests,
+		Env: map[string]string{
+			"AIR_OTEL_CONFIG":       otelConfig,
+			"AIR_PROMETHEUS_CONFIG": promConfig,
+		},
 	})
 	if err != nil {
+		removeConfigs()
 		return nil, fmt.Errorf("initialize compose: %w", err)
 	}
 
+	// Teardown must still run when ctx has hit its deadline
+	teardown := func() {
+		stopCompose(context.WithoutCancel(ctx), svc)
+		removeConfigs()
+	}
+
 	if err := svc.Start(ctx); err != nil {
-		svc.Close()
+		teardown()
 		return nil, fmt.Errorf("start services: %w", err)
 	}
 
 	// Wait for services to be healthy
 	if err := svc.WaitForHealthy(ctx, 60*time.Second); err != nil {
-		svc.Stop(ctx)
-		svc.Close()
+		teardown()
 		return nil, fmt.Errorf("services not healthy: %w", err)
 	}
+	warnImageDigests(ctx, svc)
 
 	// Build Infrastructure struct with URLs
 	status, err := svc.Status(ctx)
 	if err != nil {
-		svc.Stop(ctx)
-		svc.Close()
+		teardown()
 		return nil, fmt.Errorf("get status: %w", err)
 	}
 
+	infra, err := composeInfrastructure(cfg, status)
+	if err != nil {
+		teardown()
+		return nil, err
+	}
+	infra.DockerClient = svc
+
+	// Set cleanup function
+	infra.Cleanup = func() {
+		StopServer()
+		stopCompose(context.Background(), svc)
+		removeConfigs()
+	}
+
+	return infra, nil
+}
+
+// WaitOptions controls the polling in WaitForService. Zero fields take the
+// DefaultWaitOptions values, except Timeout: zero leaves the wait bounded
+// only by the context.
+type WaitOptions struct {
+	Timeout    time.Duration // overall limit on the wait
+	Initial    time.Duration // delay after the first failed check
+	Max        time.Duration // upper bound on the delay between checks
+	Multiplier float64       // delay growth factor after each failed check
+}
+
+// DefaultWaitOptions returns a 30s wait with exponential backoff from 250ms to 2s
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Timeout:    30 * time.Second,
+		Initial:    250 * time.Millisecond,
+		Max:        2 * time.Second,
+		Multiplier: 2,
+	}
+}
+
+// WaitForService calls check until it succeeds, backing off exponentially
+// between attempts. It fails with the context error, wrapping the last
+// check error, once opts.Timeout elapses or ctx is done.
+func WaitForService(ctx context.Context, check func(context.Context) error, opts WaitOptions) error {
+	defaults := DefaultWaitOptions()
+	if opts.Initial <= 0 {
+		opts.Initial = defaults.Initial
+	}
+	if opts.Max <= 0 {
+		opts.Max = max(defaults.Max, opts.Initial)
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaults.Multiplier
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	delay := opts.Initial
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w: last error: %w", ctx.Err(), err)
+		case <-timer.C:
+		}
+		delay = min(time.Duration(float64(delay)*opts.Multiplier), opts.Max)
+	}
+}
+
+// waitOptionsFor returns DefaultWaitOptions with the given per-service
+// timeout, shortened to what remains of ctx's deadline (as set by
+// WaitForAllServices), so one hung service cannot use up the whole budget
+func waitOptionsFor(ctx context.Context, timeout time.Duration) WaitOptions {
+	opts := DefaultWaitOptions()
+	opts.Timeout = timeout
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.Timeout = min(time.Until(deadline), timeout)
+	}
+	return opts
+}
+
+// composeInfrastructure builds service URLs from the host ports the compose
+// services actually publish (Docker-assigned ones with RandomizePorts), and
+// records their container IDs. A service or port missing from status is
+// an error.
+func composeInfrastructure(cfg *Config, status *compose.ServiceStatus) (*Infrastructure, error) {
+	var firstErr error
+	endpoint := func(service string, containerPort int) string {
+		port, err := status.HostPort(service, containerPort)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("resolve %s port %d: %w", service, containerPort, err)
+		}
+		return fmt.Sprintf("localhost:%d", port)
+	}
+
 	infra := &Infrastructure{
-		PostgresURL:    fmt.Sprintf("postgres://%s:%s@localhost:5432/%s?sslmode=disable", cfg.DBUser, cfg.DBPassword, cfg.DBName),
-		JaegerURL:      "http://localhost:16686",
-		PrometheusURL:  "http://localhost:9090",
-		OtelEndpoint:   "localhost:4317",
-		OtelHealthURL:  "http://localhost:13133/",
-		OtelMetricsURL: "http://localhost:8889/metrics",
-		DockerClient:   svc,
+		PostgresURL:    fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable", cfg.DBUser, cfg.DBPassword, endpoint("postgres", 5432), cfg.DBName),
+		JaegerURL:      "http://" + endpoint("jaeger", 16686),
+		PrometheusURL:  "http://" + endpoint("prometheus", 9090),
+		OtelEndpoint:   endpoint("otel-collector", 4317),
+		OtelHealthURL:  "http://" + endpoint("otel-collector", 13133) + "/",
+		OtelMetricsURL: "http://" + endpoint("otel-collector", 8889) + "/metrics",
+
+		OtelInternalMetricsURL: "http://" + endpoint("otel-collector", 8888) + "/metrics",
+	}
+	if firstErr != nil {
+		return nil, firstErr
 	}
 
-	// Populate container IDs from status
 	for name, info := range status.Services {
 		switch name {
 		case "postgres":
@@ -98,40 +238,23 @@ func StartWithCompose(ctx context.Context, cfg *Config) (*Infrastructure, error)
 			infra.OtelContainerID = info.ContainerID
 		}
 	}
-
-	// Set cleanup function
-	infra.Cleanup = func() {
-		StopServer()
-		svc.Stop(context.Background())
-		svc.Close()
-	}
-
 	return infra, nil
 }
 
+// WaitForPostgres waits up to 30s (or ctx's deadline) for postgres to accept connections
 func WaitForPostgres(ctx context.Context, dbURL string) error {
-	deadline := time.Now().Add(30 * time.Second)
-
-	for time.Now().Before(deadline) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
+	err := WaitForService(ctx, func(ctx context.Context) error {
 		db, err := sql.Open("postgres", dbURL)
-		if err == nil {
-			err = db.Ping()
-			db.Close()
-			if err == nil {
-				return nil
-			}
+		if err != nil {
+			return err
 		}
-
-		time.Sleep(1 * time.Second)
+		defer db.Close()
+		return db.PingContext(ctx)
+	}, waitOptionsFor(ctx, 30*time.Second))
+	if err != nil {
+		return fmt.Errorf("timeout waiting for postgres: %w", err)
 	}
-
-	return fmt.Errorf("timeout waiting for postgres")
+	return nil
 }
 
 func WaitForJaeger(ctx context.Context, jaegerURL string) error {
@@ -142,61 +265,77 @@ func WaitForPrometheus(ctx context.Context, promURL string) error {
 	return WaitForHTTP(ctx, promURL+"/-/ready", 30*time.Second)
 }
 
+// WaitForHTTP waits up to timeout (or ctx's deadline) for url to answer below 400
 func WaitForHTTP(ctx context.Context, url string, timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
 	client := &http.Client{Timeout: 2 * time.Second}
 
-	for time.Now().Before(deadline) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
+	err := WaitForService(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
 		}
-
-		resp, err := client.Get(url)
-		if err == nil {
-			resp.Body.Close()
-			if resp.StatusCode < 400 {
-				return nil
-			}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
 		}
-
-		time.Sleep(1 * time.Second)
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+		return nil
+	}, waitOptionsFor(ctx, timeout))
+	if err != nil {
+		return fmt.Errorf("timeout waiting for %s: %w", url, err)
 	}
-
-	return fmt.Errorf("timeout waiting for %s", url)
+	return nil
 }
 
-func WaitForSchema(ctx context.Context, dbURL string) error {
-	deadline := time.Now().Add(15 * time.Second)
+// defaultSchemaTables is the table WaitForSchema checks when none are given,
+// and the DefaultConfig SchemaTables value
+var defaultSchemaTables = []string{"question_bank"}
 
-	for time.Now().Before(deadline) {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+// WaitForSchema waits up to 15s (or ctx's deadline) until every table in
+// tables exists, signalling that the server's migrations have run. With no
+// tables it waits for question_bank.
+func WaitForSchema(ctx context.Context, dbURL string, tables ...string) error {
+	if len(tables) == 0 {
+		tables = defaultSchemaTables
+	}
+	opts := waitOptionsFor(ctx, 15*time.Second)
+	opts.Max = 500 * time.Millisecond
 
+	err := WaitForService(ctx, func(ctx context.Context) error {
 		db, err := sql.Open("postgres", dbURL)
-		if err == nil {
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		var missing []string
+		for _, table := range tables {
 			var exists bool
 			err = db.QueryRowContext(ctx, `
 				SELECT EXISTS (
-					SELECT FROM information_schema.tables 
-					WHERE table_name = 'question_bank'
+					SELECT FROM information_schema.tables
+					WHERE table_name = $1
 				)
-			`).Scan(&exists)
-			db.Close()
-
-			if err == nil && exists {
-				return nil
+			`, table).Scan(&exists)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				missing = append(missing, table)
 			}
 		}
-
-		time.Sleep(500 * time.Millisecond)
+		if len(missing) > 0 {
+			return fmt.Errorf("tables not found: %s", strings.Join(missing, ", "))
+		}
+		return nil
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("timeout waiting for database schema: %w", err)
 	}
-
-	return fmt.Errorf("timeout waiting for database schema")
+	return nil
 }
 
 // VerifyPostgresHealth checks postgres health and basic functionality
@@ -293,82 +432,49 @@ func VerifyOtelCollectorHealth(ctx context.Context, healthURL, metricsURL string
 		return fmt.Errorf("otel collector health check failed: status %d", resp.StatusCode)
 	}
 
-	// Note: OTEL collector's internal metrics are on port 8888 but not in Prometheus format
-	// We only verify the health_check extension endpoint which confirms the collector is running
+	// Internal metrics (port 8888) are checked after traffic by verification.VerifyNoDroppedSpans
 
 	return nil
 }
 
-func StartServer(ctx context.Context, cfg *Config, infra *Infrastructure) error {
-	// Kill any existing process on the configured port
-	serverPort := cfg.ServerPort
-	if !isPortAvailable(serverPort) {
-		fmt.Printf("Port %s in use, killing existing process...\n", serverPort)
-		killProcessOnPort(serverPort)
-		time.Sleep(1 * time.Second)
-
-		// If still not available, try other ports
-		if !isPortAvailable(serverPort) {
-			for port := 8080; port <= 8090; port++ {
-				portStr := fmt.Sprintf("%d", port)
-				if isPortAvailable(portStr) {
-					serverPort = portStr
-					fmt.Printf("Using port %s instead\n", serverPort)
-					break
-				}
-			}
-			if !isPortAvailable(serverPort) {
-				return fmt.Errorf("no available port found in range 8080-8090")
-			}
-		}
-	}
-
-	// Set environment variables
-	if cfg.OTELEnabled {
-		// Verify OTEL endpoint is reachable before starting server
-		fmt.Printf("Server will use OTEL endpoint: %s\n", infra.OtelEndpoint)
-
-		// Test gRPC port is actually listening
-		conn, err := net.DialTimeout("tcp", infra.OtelEndpoint, 5*time.Second)
+// otelPreflightTimeout bounds how long server startup waits for the
+// collector's OTLP gRPC port
+const otelPreflightTimeout = 10 * time.Second
+
+// checkOTELEndpoint waits, with backoff, for the collector's OTLP gRPC port
+// to accept connections, for at most timeout or until ctx's deadline,
+// whichever comes first, so a longer ctx deadline (the whole startup
+// phase) never extends the preflight. OTLP exporters
+// reconnect on their own, so a collector that is still starting only fails
+// startup when cfg.OTELRequireCollector is set; otherwise it is reported
+// as a warning. A cancelled or expired ctx is always an error.
+func checkOTELEndpoint(ctx context.Context, cfg *Config, endpoint string, timeout time.Duration) error {
+	err := WaitForService(ctx, func(ctx context.Context) error {
+		var d net.Dialer
+		dialCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		conn, err := d.DialContext(dialCtx, "tcp", endpoint)
 		if err != nil {
-			return fmt.Errorf("OTEL endpoint %s not reachable: %w", infra.OtelEndpoint, err)
+			return err
 		}
-		conn.Close()
-		fmt.Printf("✓ OTEL gRPC endpoint verified reachable\n")
-
-		os.Setenv("OTEL_ENABLED", "true")
-		os.Setenv("OTEL_ENDPOINT", infra.OtelEndpoint)
-		os.Setenv("OTEL_SERVICE_NAME", cfg.OTELServiceName)
-		os.Setenv("OTEL_ENVIRONMENT", cfg.OTELEnvironment)
+		return conn.Close()
+	}, waitOptionsFor(ctx, timeout))
+	if err == nil {
+		printStatusf(cfg, "✓ OTEL gRPC endpoint verified reachable\n")
+		return nil
 	}
-	os.Setenv("DATABASE_URL", infra.PostgresURL)
-	os.Setenv("JWT_SECRET", cfg.JWTSecret)
-	os.Setenv("JWT_ISS", cfg.JWTIssuer)
-	os.Setenv("JWT_AUD", cfg.JWTAudience)
-	os.Setenv("PORT", serverPort)
-
-	// Set any extra environment variables
-	for k, v := range cfg.ExtraEnv {
-		os.Setenv(k, v)
+	if ctx.Err() != nil {
+		return fmt.Errorf("OTEL endpoint %s: %w", endpoint, err)
 	}
-
-	serverCmd = exec.Command(cfg.ServerCommand[0], cfg.ServerCommand[1:]...)
-	serverCmd.Stdout = os.Stdout
-	serverCmd.Stderr = os.Stderr
-
-	if err := serverCmd.Start(); err != nil {
-		return err
+	if cfg.OTELRequireCollector {
+		return fmt.Errorf("OTEL endpoint %s not reachable: %w", endpoint, err)
 	}
-
-	// Update config with actual port used
-	cfg.ServerPort = serverPort
-
-	// Wait for server to be ready
-	healthURL := fmt.Sprintf("http://localhost:%s%s", serverPort, cfg.HealthEndpoint)
-	return WaitForHTTP(ctx, healthURL, 15*time.Second)
+	fmt.Printf("Warning: OTEL endpoint %s not reachable yet; starting server anyway (exporters retry): %v\n", endpoint, err)
+	return nil
 }
 
-func isPortAvailable(port string) bool {
+// IsPortAvailable reports whether a TCP port can be bound on all interfaces
+func IsPortAvailable(port string) bool {
 	addr := fmt.Sprintf(":%s", port)
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -378,93 +484,102 @@ func isPortAvailable(port string) bool {
 	return true
 }
 
-func killProcessOnPort(port string) {
+// killProcessOnPort kills whatever listens on port; a variable so tests
+// holding the port themselves can replace it
+var killProcessOnPort = func(port string) {
 	cmd := exec.Command("sh", "-c", fmt.Sprintf("lsof -ti:%s | xargs kill -9 2>/dev/null || true", port))
 	cmd.Run()
 }
 
-func StopServer() {
-	if serverCmd != nil && serverCmd.Process != nil {
-		serverCmd.Process.Kill()
-		serverCmd.Wait()
-	}
-}
-
 func StartInfrastructure(ctx context.Context, cfg *Config, report *Report) (*Infrastructure, error) {
 	report.Step("Starting infrastructure with Docker Compose...")
 
+	// Render collector and Prometheus configs; the compose file mounts them
+	// via AIR_OTEL_CONFIG and AIR_PROMETHEUS_CONFIG
+	otelConfig, removeOtelConfig, err := WriteOtelConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("render otel collector config: %w", err)
+	}
+
+	promConfig, removePromConfig, err := WritePrometheusConfig(cfg)
+	if err != nil {
+		removeOtelConfig()
+		return nil, fmt.Errorf("render prometheus config: %w", err)
+	}
+	removeConfigs := func() {
+		removeOtelConfig()
+		removePromConfig()
+	}
+
 	// Use compose service (Docker SDK)
 	svc, err := compose.New(compose.Config{
 		ComposeFilePath: cfg.ComposeFilePath,
 		ProjectName:     cfg.ProjectName,
-		Env:             make(map[string]string),
+		RandomizePorts:  cfg.RandomizePorts,
+		ImageDigests:    cfg.ImageDigests,
+		Env: map[string]string{
+			"AIR_OTEL_CONFIG":       otelConfig,
+			"AIR_PROMETHEUS_CONFIG": promConfig,
+		},
 	})
 	if err != nil {
+		removeConfigs()
 		return nil, fmt.Errorf("initialize compose: %w", err)
 	}
 
+	// Teardown must still run when ctx has hit its deadline
+	teardown := func() {
+		stopCompose(context.WithoutCancel(ctx), svc)
+		removeConfigs()
+	}
+
 	if err := svc.Start(ctx); err != nil {
-		svc.Close()
+		teardown()
 		return nil, fmt.Errorf("start services: %w", err)
 	}
 
 	// Wait for services to be healthy
 	if err := svc.WaitForHealthy(ctx, 60*time.Second); err != nil {
-		svc.Stop(ctx)
-		svc.Close()
+		teardown()
 		return nil, fmt.Errorf("services not healthy: %w", err)
 	}
+	warnImageDigests(ctx, svc)
 
 	// Build Infrastructure struct with URLs
 	status, err := svc.Status(ctx)
 	if err != nil {
-		svc.Stop(ctx)
-		svc.Close()
+		teardown()
 		return nil, fmt.Errorf("get status: %w", err)
 	}
 
-	infra := &Infrastructure{
-		PostgresURL:    fmt.Sprintf("postgres://%s:%s@localhost:5432/%s?sslmode=disable", cfg.DBUser, cfg.DBPassword, cfg.DBName),
-		JaegerURL:      "http://localhost:16686",
-		PrometheusURL:  "http://localhost:9090",
-		OtelEndpoint:   "localhost:4317",
-		OtelHealthURL:  "http://localhost:13133/",
-		OtelMetricsURL: "http://localhost:8889/metrics",
-		DockerClient:   svc,
-	}
-
-	// Populate container IDs from status
-	for name, info := range status.Services {
-		switch name {
-		case "postgres":
-			infra.PostgresContainerID = info.ContainerID
-		case "jaeger":
-			infra.JaegerContainerID = info.ContainerID
-		case "prometheus":
-			infra.PrometheusContainerID = info.ContainerID
-		case "otel-collector":
-			infra.OtelContainerID = info.ContainerID
-		}
+	infra, err := composeInfrastructure(cfg, status)
+	if err != nil {
+		teardown()
+		return nil, err
 	}
+	infra.DockerClient = svc
 
 	// Set cleanup function
 	infra.Cleanup = func() {
 		StopServer()
-		svc.Stop(context.Background())
-		svc.Close()
+		stopCompose(context.Background(), svc)
+		removeConfigs()
 	}
 
 	// Basic availability checks (just port listening)
 	report.Step("Waiting for containers to be ready...")
 	if err := WaitForPostgres(ctx, infra.PostgresURL); err != nil {
+		infra.Cleanup()
 		return nil, fmt.Errorf("postgres wait: %w", err)
 	}
 
 	if err := WaitForJaeger(ctx, infra.JaegerURL); err != nil {
+		infra.Cleanup()
 		return nil, fmt.Errorf("jaeger wait: %w", err)
 	}
 
 	if err := WaitForPrometheus(ctx, infra.PrometheusURL); err != nil {
+		infra.Cleanup()
 		return nil, fmt.Errorf("prometheus wait: %w", err)
 	}
 
@@ -515,7 +630,7 @@ func StartApplicationServer(ctx context.Context, cfg *Config, infra *Infrastruct
 	}
 
 	report.Step("Waiting for database migrations...")
-	if err := WaitForSchema(ctx, infra.PostgresURL); err != nil {
+	if err := WaitForSchema(ctx, infra.PostgresURL, cfg.SchemaTables...); err != nil {
 		report.Fail("Schema readiness failed: %v", err)
 		return fmt.Errorf("schema readiness: %w", err)
 	}
@@ -524,6 +639,35 @@ func StartApplicationServer(ctx context.Context, cfg *Config, infra *Infrastruct
 	return nil
 }
 
+// stopCompose tears the stack down and warns about any project containers,
+// networks or volumes that survived, so leaks do not pile up across CI runs
+func stopCompose(ctx context.Context, svc *compose.Service) {
+	svc.Stop(ctx)
+	if err := svc.VerifyCleanup(ctx); err != nil {
+		fmt.Printf("Warning: incomplete cleanup: %v\n", err)
+	}
+	svc.Close()
+}
+
+// warnImageDigests warns about containers running a different image than
+// the digest pinned for them, such as a stale local ":latest"
+func warnImageDigests(ctx context.Context, svc *compose.Service) {
+	_, err := svc.VerifyImageDigests(ctx)
+	var digestErr *compose.DigestError
+	switch {
+	case errors.As(err, &digestErr):
+		for _, m := range digestErr.Mismatches {
+			actual := m.Actual
+			if actual == "" {
+				actual = "an image with no registry digest"
+			}
+			fmt.Printf("Warning: %s (%s) runs %s, expected %s; pull the image again\n", m.Service, m.Image, actual, m.Expected)
+		}
+	case err != nil:
+		fmt.Printf("Warning: could not verify image digests: %v\n", err)
+	}
+}
+
 func CleanupInfrastructure(infra *Infrastructure) {
 	if infra != nil {
 		infra.Cleanup()
@@ -557,6 +701,26 @@ func (infra *Infrastructure) GetContainerLogs(ctx context.Context, containerType
 	return infra.DockerClient.GetContainerLogs(ctx, containerID)
 }
 
+// upMigrationFiles lists the .sql files in dir in execution order (001, 002,
+// ...), leaving out the .down.sql rollback scripts paired with them
+func upMigrationFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("glob migrations: %w", err)
+	}
+	var files []string
+	for _, file := range matches {
+		if !strings.HasSuffix(file, ".down.sql") {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migration files in %s", dir)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 // ApplyMigrations executes SQL migration files from the configured directory
 func ApplyMigrations(ctx context.Context, dbURL, migrationsDir string) error {
 	db, err := sql.Open("postgres", dbURL)
@@ -573,19 +737,11 @@ func ApplyMigrations(ctx context.Context, dbURL, migrationsDir string) error {
 		}
 	}
 
-	// Find all .sql files
-	files, err := filepath.Glob(filepath.Join(absDir, "*.sql"))
+	files, err := upMigrationFiles(absDir)
 	if err != nil {
-		return fmt.Errorf("glob migrations: %w", err)
-	}
-
-	if len(files) == 0 {
-		return fmt.Errorf("no migration files in %s", absDir)
+		return err
 	}
 
-	// Sort to ensure execution order (001, 002, ...)
-	sort.Strings(files)
-
 	// Execute each migration
 	for _, file := range files {
 		sqlBytes, err := os.ReadFile(file)
@@ -0,0 +1,194 @@
+package containers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/compose"
+)
+
+func TestWaitForServiceRetriesWithBackoff(t *testing.T) {
+	var calls []time.Time
+	check := func(ctx context.Context) error {
+		calls = append(calls, time.Now())
+		if len(calls) < 4 {
+			return errors.New("not ready")
+		}
+		return nil
+	}
+
+	opts := WaitOptions{Timeout: 5 * time.Second, Initial: 10 * time.Millisecond, Max: 40 * time.Millisecond, Multiplier: 2}
+	if err := WaitForService(context.Background(), check, opts); err != nil {
+		t.Fatalf("WaitForService: %v", err)
+	}
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 checks (3 failures then success), got %d", len(calls))
+	}
+
+	// Delays grow 10ms, 20ms, 40ms
+	for i, want := range []time.Duration{10, 20, 40} {
+		if gap := calls[i+1].Sub(calls[i]); gap < want*time.Millisecond {
+			t.Fatalf("expected delay before check %d of at least %dms, got %v", i+2, want, gap)
+		}
+	}
+}
+
+func TestWaitForServiceTimeout(t *testing.T) {
+	notReady := errors.New("not ready")
+	opts := WaitOptions{Timeout: 50 * time.Millisecond, Initial: 5 * time.Millisecond}
+
+	err := WaitForService(context.Background(), func(context.Context) error { return notReady }, opts)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, notReady) {
+		t.Fatalf("expected deadline error wrapping last check error, got %v", err)
+	}
+}
+
+func TestWaitOptionsForCapsAtContextDeadline(t *testing.T) {
+	if got := waitOptionsFor(context.Background(), 30*time.Second).Timeout; got != 30*time.Second {
+		t.Fatalf("expected default timeout without ctx deadline, got %v", got)
+	}
+
+	long, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if got := waitOptionsFor(long, 30*time.Second).Timeout; got != 30*time.Second {
+		t.Fatalf("expected the per-service cap under a longer deadline, got %v", got)
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if got := waitOptionsFor(short, 30*time.Second).Timeout; got <= 0 || got > 5*time.Second {
+		t.Fatalf("expected the remaining deadline to govern, got %v", got)
+	}
+}
+
+func TestComposeInfrastructureUsesPublishedPorts(t *testing.T) {
+	cfg := &Config{DBUser: "u", DBPassword: "p", DBName: "d"}
+	status := &compose.ServiceStatus{Services: map[string]compose.ServiceInfo{
+		"postgres":   {ContainerID: "pg", Ports: []string{"0.0.0.0:49001->5432/tcp"}},
+		"jaeger":     {ContainerID: "jg", Ports: []string{"0.0.0.0:49002->16686/tcp"}},
+		"prometheus": {ContainerID: "pr", Ports: []string{"0.0.0.0:49003->9090/tcp"}},
+		"otel-collector": {ContainerID: "ot", Ports: []string{
+			"0.0.0.0:49004->4317/tcp", "0.0.0.0:49005->13133/tcp",
+			"0.0.0.0:49006->8889/tcp", "0.0.0.0:49007->8888/tcp",
+		}},
+	}}
+
+	infra, err := composeInfrastructure(cfg, status)
+	if err != nil {
+		t.Fatalf("composeInfrastructure: %v", err)
+	}
+	if infra.PostgresURL != "postgres://u:p@localhost:49001/d?sslmode=disable" {
+		t.Fatalf("unexpected postgres URL %q", infra.PostgresURL)
+	}
+	if infra.JaegerURL != "http://localhost:49002" || infra.PrometheusURL != "http://localhost:49003" {
+		t.Fatalf("unexpected jaeger/prometheus URLs %q %q", infra.JaegerURL, infra.PrometheusURL)
+	}
+	if infra.OtelEndpoint != "localhost:49004" || infra.OtelHealthURL != "http://localhost:49005/" ||
+		infra.OtelMetricsURL != "http://localhost:49006/metrics" || infra.OtelInternalMetricsURL != "http://localhost:49007/metrics" {
+		t.Fatalf("unexpected otel endpoints: %+v", infra)
+	}
+	if infra.PostgresContainerID != "pg" || infra.OtelContainerID != "ot" {
+		t.Fatalf("expected container IDs from status, got %+v", infra)
+	}
+
+	delete(status.Services, "jaeger")
+	if _, err := composeInfrastructure(cfg, status); err == nil || !strings.Contains(err.Error(), "jaeger") {
+		t.Fatalf("expected error naming the missing service, got %v", err)
+	}
+}
+
+func TestCheckOTELEndpoint(t *testing.T) {
+	// Reserve a port, then close it so nothing is listening
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	endpoint := lis.Addr().String()
+	lis.Close()
+
+	// The phase deadline is far longer than the preflight timeout; the
+	// preflight must still give up on its own
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	start := time.Now()
+	if err := checkOTELEndpoint(ctx, &Config{}, endpoint, 300*time.Millisecond); err != nil {
+		t.Fatalf("expected an unreachable collector to only warn, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("preflight ran %v, past its 300ms timeout", elapsed)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("preflight used up the caller's deadline")
+	}
+
+	err = checkOTELEndpoint(ctx, &Config{OTELRequireCollector: true}, endpoint, 300*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Fatalf("expected a fatal error with OTELRequireCollector, got %v", err)
+	}
+
+	// A ctx deadline shorter than the preflight timeout wins, and an
+	// expired ctx fails even without OTELRequireCollector
+	short, cancelShort := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelShort()
+	start = time.Now()
+	err = checkOTELEndpoint(short, &Config{}, endpoint, time.Minute)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the expired ctx to be reported, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("preflight ran %v, past the ctx deadline", elapsed)
+	}
+
+	// A listening collector passes
+	lis, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer lis.Close()
+	if err := checkOTELEndpoint(ctx, &Config{OTELRequireCollector: true}, lis.Addr().String(), 2*time.Second); err != nil {
+		t.Fatalf("expected listening collector to pass, got %v", err)
+	}
+}
+
+func TestWaitForSchemaReportsUnreachableDatabase(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := WaitForSchema(ctx, "postgres://u:p@127.0.0.1:1/d?sslmode=disable", "users", "curriculum")
+	if err == nil || !strings.Contains(err.Error(), "timeout waiting for database schema") {
+		t.Fatalf("expected schema timeout, got %v", err)
+	}
+}
+
+func TestUpMigrationFilesSkipsDownScripts(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"002_b.sql", "001_a.sql", "001_a.down.sql", "002_b.down.sql", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	files, err := upMigrationFiles(dir)
+	if err != nil {
+		t.Fatalf("upMigrationFiles: %v", err)
+	}
+	want := []string{filepath.Join(dir, "001_a.sql"), filepath.Join(dir, "002_b.sql")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v, got %v", want, files)
+	}
+}
+
+func TestUpMigrationFilesRejectsOnlyDownScripts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "001_a.down.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := upMigrationFiles(dir); err == nil {
+		t.Fatal("expected an error when only down scripts exist")
+	}
+}
@@ -6,14 +6,20 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/terminal"
 )
 
 type Report struct {
 	jsonMode    bool
+	plainMode   bool
 	startTime   time.Time
 	phases      []PhaseResult
 	currentStep string
+	stepStart   time.Time
 	steps       []StepResult
+	pendingLogs []LogEntry // Info output awaiting the step in progress
+	spinner     *terminal.Spinner
 }
 
 type PhaseResult struct {
@@ -21,17 +27,43 @@ type PhaseResult struct {
 	StartTime time.Time     `json:"start_time"`
 	Duration  time.Duration `json:"duration"`
 	Steps     []StepResult  `json:"steps"`
+	Logs      []LogEntry    `json:"logs,omitempty"` // Info output not tied to a step
 }
 
+// Severity classifies a step outcome. Warnings are non-fatal: the step did not
+// fully verify but the pipeline is not known to be broken (e.g. metrics pending
+// on the first export).
+type Severity string
+
+const (
+	SeverityPass Severity = "pass"
+	SeverityWarn Severity = "warn"
+	SeverityFail Severity = "fail"
+)
+
+// severityRank orders severities from best to worst
+var severityRank = map[Severity]int{SeverityPass: 0, SeverityWarn: 1, SeverityFail: 2}
+
 type StepResult struct {
 	Description string        `json:"description"`
 	Success     bool          `json:"success"`
+	Severity    Severity      `json:"severity"`
 	Duration    time.Duration `json:"duration"`
 	Error       string        `json:"error,omitempty"`
+	Logs        []LogEntry    `json:"logs,omitempty"`
+}
+
+// LogEntry is an informational message recorded by Report.Info, such as a
+// session or correlation ID worth keeping for debugging a failed run
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
 }
 
 type FinalReport struct {
 	Success   bool          `json:"success"`
+	Severity  Severity      `json:"severity"`
+	Warnings  int           `json:"warnings"`
 	Duration  time.Duration `json:"duration"`
 	Phases    []PhaseResult `json:"phases"`
 	Timestamp time.Time     `json:"timestamp"`
@@ -46,15 +78,20 @@ func NewReport(jsonMode bool) *Report {
 	}
 }
 
+// SetPlainMode toggles ASCII-only output without emoji or box drawing
+func (r *Report) SetPlainMode(enabled bool) {
+	r.plainMode = enabled
+}
+
+// SetCIMode toggles plain output for CI logs.
+//
+// Deprecated: use SetPlainMode.
+func (r *Report) SetCIMode(enabled bool) {
+	r.SetPlainMode(enabled)
+}
+
 func (r *Report) Phase(name string) {
-	if len(r.steps) > 0 {
-		// Save current phase before starting new one
-		if len(r.phases) > 0 {
-			r.phases[len(r.phases)-1].Steps = r.steps
-			r.phases[len(r.phases)-1].Duration = time.Since(r.phases[len(r.phases)-1].StartTime)
-		}
-		r.steps = make([]StepResult, 0)
-	}
+	r.finishPhase()
 
 	r.phases = append(r.phases, PhaseResult{
 		Name:      name,
@@ -63,76 +100,223 @@ func (r *Report) Phase(name string) {
 	})
 
 	if !r.jsonMode {
+		if r.plainMode {
+			r.printf("\n== %s ==\n", name)
+			return
+		}
 		separator := strings.Repeat("─", 60)
-		fmt.Printf("\n%s\n", separator)
-		fmt.Printf("▶ %s\n", name)
-		fmt.Printf("%s\n", separator)
+		r.printf("\n%s\n", separator)
+		r.printf("▶ %s\n", name)
+		r.printf("%s\n", separator)
 	}
 }
 
 func (r *Report) Step(description string) {
 	r.currentStep = description
-	// Silent - only show results, not intermediate steps
+	r.stepStart = time.Now()
+	// Only results are printed; on a terminal the step shows as a spinner until it finishes
+	if r.jsonMode {
+		return
+	}
+	if r.spinner == nil {
+		r.spinner = terminal.NewStdoutSpinner(r.plainMode)
+	}
+	if r.spinner.Interactive() {
+		if r.plainMode {
+			description = terminal.PlainText(description)
+		}
+		r.spinner.Start(description)
+	}
 }
 
 func (r *Report) StepSuccess(description string) {
-	r.steps = append(r.steps, StepResult{
+	r.recordStep(StepResult{
+		Description: description,
+		Success:     true,
+		Severity:    SeverityPass,
+		Duration:    r.stepDuration(),
+	})
+	if !r.jsonMode {
+		r.printf("  ✓ %s\n", description)
+	}
+}
+
+// StepWarn records a non-fatal step outcome with the reason it was not fully verified
+func (r *Report) StepWarn(description, reason string) {
+	r.recordStep(StepResult{
 		Description: description,
 		Success:     true,
-		Duration:    0,
+		Severity:    SeverityWarn,
+		Duration:    r.stepDuration(),
+		Error:       reason,
 	})
 	if !r.jsonMode {
-		fmt.Printf("  ✓ %s\n", description)
+		r.printf("  ⚠ %s: %s\n", description, reason)
 	}
 }
 
 func (r *Report) StepFail(description string, err error) {
-	r.steps = append(r.steps, StepResult{
+	r.recordStep(StepResult{
 		Description: description,
 		Success:     false,
+		Severity:    SeverityFail,
+		Duration:    r.stepDuration(),
 		Error:       err.Error(),
 	})
 	if !r.jsonMode {
-		fmt.Printf("  ❌ %s: %v\n", description, err)
+		r.printf("  ❌ %s: %v\n", description, err)
 	}
 }
 
 func (r *Report) Success(message string) {
 	if !r.jsonMode {
-		fmt.Printf("\n✅ %s\n", message)
+		r.printf("\n✅ %s\n", message)
 	}
 }
 
 func (r *Report) Fail(format string, args ...interface{}) {
 	if !r.jsonMode {
-		fmt.Printf("\n❌ "+format+"\n", args...)
+		r.printf("\n❌ "+format+"\n", args...)
 	}
 }
 
+// Info prints an informational line and records it in the structured
+// report, attached to the step in progress (or the last finished one)
 func (r *Report) Info(format string, args ...interface{}) {
+	r.addLog(strings.TrimSpace(fmt.Sprintf(format, args...)))
 	if !r.jsonMode {
-		fmt.Printf("    · "+format+"\n", args...)
+		r.printf("    · "+format+"\n", args...)
 	}
 }
 
 func (r *Report) Print() {
-	// Save last phase
-	if len(r.phases) > 0 && len(r.steps) > 0 {
-		r.phases[len(r.phases)-1].Steps = r.steps
-		r.phases[len(r.phases)-1].Duration = time.Since(r.phases[len(r.phases)-1].StartTime)
-	}
+	r.finishPhase()
 
 	if r.jsonMode {
-		finalReport := FinalReport{
-			Success:   true,
-			Duration:  time.Since(r.startTime),
-			Phases:    r.phases,
-			Timestamp: time.Now(),
-		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		enc.Encode(finalReport)
+		enc.Encode(r.Final())
 	} else {
-		fmt.Printf("\n📊 Total Duration: %v\n", time.Since(r.startTime))
+		r.PrintTimings()
+	}
+}
+
+// Final closes the current phase and returns the structured result of the run so far
+func (r *Report) Final() *FinalReport {
+	r.finishPhase()
+	severity := r.Severity()
+	return &FinalReport{
+		Success:   severity != SeverityFail,
+		Severity:  severity,
+		Warnings:  len(r.Warnings()),
+		Duration:  time.Since(r.startTime),
+		Phases:    append([]PhaseResult(nil), r.phases...),
+		Timestamp: time.Now(),
+	}
+}
+
+// PrintTimings prints the time spent in each phase and in total (text mode only).
+// It is safe to call on failure, before Print.
+func (r *Report) PrintTimings() {
+	r.finishPhase()
+	if r.jsonMode {
+		return
+	}
+
+	r.printf("\n📊 Total Duration: %v\n", time.Since(r.startTime).Round(time.Millisecond))
+	for _, phase := range r.phases {
+		r.printf("    %-32s %v\n", phase.Name, phase.Duration.Round(time.Millisecond))
+	}
+}
+
+// Severity returns the worst severity across all recorded steps (pass if none)
+func (r *Report) Severity() Severity {
+	worst := SeverityPass
+	for _, step := range r.allSteps() {
+		if severityRank[step.Severity] > severityRank[worst] {
+			worst = step.Severity
+		}
+	}
+	return worst
+}
+
+// Warnings returns the steps recorded with SeverityWarn
+func (r *Report) Warnings() []StepResult {
+	var warnings []StepResult
+	for _, step := range r.allSteps() {
+		if step.Severity == SeverityWarn {
+			warnings = append(warnings, step)
+		}
+	}
+	return warnings
+}
+
+// allSteps returns finished phase steps followed by those of the current phase
+func (r *Report) allSteps() []StepResult {
+	var steps []StepResult
+	for _, phase := range r.phases {
+		steps = append(steps, phase.Steps...)
+	}
+	return append(steps, r.steps...)
+}
+
+// recordStep appends a finished step, attaching Info output logged while it ran
+func (r *Report) recordStep(step StepResult) {
+	step.Logs = r.pendingLogs
+	r.pendingLogs = nil
+	r.steps = append(r.steps, step)
+}
+
+// addLog attaches an Info message to the step in progress, else to the last
+// finished step of the current phase, else to the phase itself
+func (r *Report) addLog(message string) {
+	entry := LogEntry{Time: time.Now(), Message: message}
+	switch {
+	case !r.stepStart.IsZero():
+		r.pendingLogs = append(r.pendingLogs, entry)
+	case len(r.steps) > 0:
+		last := &r.steps[len(r.steps)-1]
+		last.Logs = append(last.Logs, entry)
+	case len(r.phases) > 0:
+		current := &r.phases[len(r.phases)-1]
+		current.Logs = append(current.Logs, entry)
+	default:
+		r.pendingLogs = append(r.pendingLogs, entry)
+	}
+}
+
+// finishPhase records the current phase's steps and elapsed time
+func (r *Report) finishPhase() {
+	if len(r.phases) == 0 {
+		return
+	}
+	current := &r.phases[len(r.phases)-1]
+	// Logs of a step that never finished stay with the phase
+	current.Logs = append(current.Logs, r.pendingLogs...)
+	r.pendingLogs = nil
+	current.Steps = append(current.Steps, r.steps...)
+	current.Duration = time.Since(current.StartTime)
+	r.steps = make([]StepResult, 0)
+}
+
+// stepDuration returns time since the last Step call, or zero if no step is in progress
+func (r *Report) stepDuration() time.Duration {
+	if r.stepStart.IsZero() {
+		return 0
+	}
+	d := time.Since(r.stepStart)
+	r.stepStart = time.Time{}
+	return d
+}
+
+// printf writes text-mode output, stripping decorations in plain mode
+func (r *Report) printf(format string, args ...interface{}) {
+	if r.spinner != nil {
+		r.spinner.Stop("")
+	}
+	text := fmt.Sprintf(format, args...)
+	if r.plainMode {
+		text = terminal.PlainText(text)
 	}
+	fmt.Print(text)
 }
@@ -0,0 +1,80 @@
+package containers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReportSeverity(t *testing.T) {
+	r := NewReport(true)
+	if got := r.Severity(); got != SeverityPass {
+		t.Fatalf("empty report severity = %s, want pass", got)
+	}
+
+	r.Phase("Traces")
+	r.StepSuccess("Traces found")
+	r.Phase("Metrics")
+	r.StepWarn("OTEL metrics endpoint", "metrics pending")
+	if got := r.Severity(); got != SeverityWarn {
+		t.Fatalf("severity = %s, want warn", got)
+	}
+	if w := r.Warnings(); len(w) != 1 || w[0].Error != "metrics pending" || !w[0].Success {
+		t.Fatalf("unexpected warnings: %+v", w)
+	}
+
+	r.StepFail("Prometheus", errors.New("no series"))
+	if got := r.Severity(); got != SeverityFail {
+		t.Fatalf("severity = %s, want fail", got)
+	}
+}
+
+func TestReportFinal(t *testing.T) {
+	r := NewReport(true)
+	r.Phase("Traces")
+	r.StepSuccess("Traces found")
+	r.Phase("Metrics")
+	r.StepWarn("OTEL metrics endpoint", "metrics pending")
+
+	final := r.Final()
+	if !final.Success || final.Severity != SeverityWarn || final.Warnings != 1 {
+		t.Fatalf("unexpected final report: %+v", final)
+	}
+	if len(final.Phases) != 2 || len(final.Phases[1].Steps) != 1 {
+		t.Fatalf("expected the open phase to be closed, got %+v", final.Phases)
+	}
+}
+
+func TestReportInfoRecordedInJSON(t *testing.T) {
+	r := NewReport(true)
+	r.Phase("Generating Traffic")
+	r.Step("Connecting WebSocket...")
+	r.Info("WebSocket connected (session: %s)", "sess-1")
+	r.StepSuccess("Traffic generated")
+	// Info after a step finishes belongs to that step
+	r.Info("Correlation IDs: %s", CorrelationIDs{"user_id": "u1", "session_id": "sess-1"})
+	r.Phase("Traces")
+	r.Info("Trace ID: %s", "abc")
+
+	final := r.Final()
+	traffic := final.Phases[0]
+	if len(traffic.Steps) != 1 {
+		t.Fatalf("expected one traffic step, got %+v", traffic.Steps)
+	}
+	logs := traffic.Steps[0].Logs
+	if len(logs) != 2 || logs[0].Message != "WebSocket connected (session: sess-1)" || logs[1].Message != "Correlation IDs: session_id=sess-1 user_id=u1" {
+		t.Fatalf("unexpected step logs: %+v", logs)
+	}
+	if traces := final.Phases[1]; len(traces.Logs) != 1 || traces.Logs[0].Message != "Trace ID: abc" {
+		t.Fatalf("expected phase-level log without a step, got %+v", traces.Logs)
+	}
+
+	data, err := json.Marshal(final)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"message":"WebSocket connected (session: sess-1)"`) {
+		t.Fatalf("expected session info in JSON output: %s", data)
+	}
+}
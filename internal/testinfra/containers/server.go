@@ -3,104 +3,286 @@ package containers
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/terminal"
+)
+
+// DefaultServerLogPath is where the server's output is written unless
+// Config.ServerLogPath is set
+const DefaultServerLogPath = "logs/server-verify.log"
+
+// serverMode selects how startServer runs the application server
+type serverMode int
+
+const (
+	// serverBlocking outlives the start context; StopServer (called by
+	// Infrastructure.Cleanup) stops it. Output is mirrored to the terminal.
+	serverBlocking serverMode = iota
+	// serverBackground is stopped by cancelling the start context
+	serverBackground
+)
+
+// serverProcess is the running application server
+type serverProcess struct {
+	cmd      *exec.Cmd
+	done     chan struct{} // closed once the process has exited
+	stopping atomic.Bool   // set by StopServer so the exit is not reported as a crash
+}
+
+var (
+	serverMu sync.Mutex
+	server   *serverProcess // started in serverBlocking mode
 )
 
-// StartServerInBackground starts the application server in a goroutine
-// and signals via the ready channel when the server is healthy
+// StartServer starts the application server and waits for its health
+// endpoint. The server keeps running after ctx ends, until StopServer.
+func StartServer(ctx context.Context, cfg *Config, infra *Infrastructure) error {
+	return startServer(ctx, cfg, infra, serverBlocking)
+}
+
+// StartServerInBackground starts the application server for the lifetime of
+// ctx and signals via the ready channel when the server is healthy, its
+// schema is migrated and its log is free of errors
 func StartServerInBackground(ctx context.Context, cfg *Config, infra *Infrastructure, ready chan<- struct{}) error {
-	// Kill any existing process on the configured port
-	serverPort := cfg.ServerPort
-	if !isPortAvailable(serverPort) {
-		fmt.Printf("Port %s in use, killing existing process...\n", serverPort)
-		killProcessOnPort(serverPort)
-		time.Sleep(1 * time.Second)
+	if err := startServer(ctx, cfg, infra, serverBackground); err != nil {
+		return err
+	}
 
-		// If still not available, try other ports
-		if !isPortAvailable(serverPort) {
-			for port := 8080; port <= 8090; port++ {
-				portStr := fmt.Sprintf("%d", port)
-				if isPortAvailable(portStr) {
-					serverPort = portStr
-					fmt.Printf("Using port %s instead\n", serverPort)
-					break
-				}
-			}
-			if !isPortAvailable(serverPort) {
-				return fmt.Errorf("no available port found in range 8080-8090")
-			}
-		}
+	// The health check may respond before migrations finish
+	if err := WaitForSchema(ctx, infra.PostgresURL, cfg.SchemaTables...); err != nil {
+		return withServerLogTail(fmt.Errorf("server schema readiness: %w", err), infra.ServerLogPath)
+	}
+
+	// A server that crashed or logged errors while starting is not ready
+	if err := CheckServerLog(infra, DefaultServerLogTail); err != nil {
+		return err
 	}
 
-	// Set environment variables
+	// Signal that server is ready
+	if ready != nil {
+		close(ready)
+	}
+
+	return nil
+}
+
+// StopServer kills a server started by StartServer and waits for it to exit
+func StopServer() {
+	serverMu.Lock()
+	p := server
+	server = nil
+	serverMu.Unlock()
+
+	if p == nil {
+		return
+	}
+	p.stopping.Store(true)
+	p.cmd.Process.Kill()
+	<-p.done
+}
+
+// startServer runs cfg.ServerCommand with the verification environment,
+// writing its output to the server log, and waits for the health endpoint
+func startServer(ctx context.Context, cfg *Config, infra *Infrastructure, mode serverMode) error {
+	serverPort, releasePort, err := selectServerPort(cfg)
+	if err != nil {
+		return err
+	}
+	// Held until just before the server starts; released on every path
+	releasePort = sync.OnceFunc(releasePort)
+	defer releasePort()
+
 	if cfg.OTELEnabled {
-		// Verify OTEL endpoint is reachable before starting server (silent check)
-		conn, err := net.DialTimeout("tcp", infra.OtelEndpoint, 5*time.Second)
-		if err != nil {
-			return fmt.Errorf("OTEL endpoint %s not reachable: %w", infra.OtelEndpoint, err)
+		fmt.Printf("Server will use OTEL endpoint: %s\n", infra.OtelEndpoint)
+		if err := checkOTELEndpoint(ctx, cfg, infra.OtelEndpoint, otelPreflightTimeout); err != nil {
+			return err
 		}
-		conn.Close()
-
-		os.Setenv("OTEL_ENABLED", "true")
-		os.Setenv("OTEL_ENDPOINT", infra.OtelEndpoint)
-		os.Setenv("OTEL_SERVICE_NAME", cfg.OTELServiceName)
-		os.Setenv("OTEL_ENVIRONMENT", cfg.OTELEnvironment)
-		os.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")    // Disable TLS for local testing
-		os.Setenv("OTEL_EXPORTER_OTLP_TRACES_SYNC", "true") // Use sync exporter for immediate trace delivery
-	}
-	os.Setenv("DATABASE_URL", infra.PostgresURL)
-	os.Setenv("JWT_SECRET", cfg.JWTSecret)
-	os.Setenv("JWT_ISS", cfg.JWTIssuer)
-	os.Setenv("JWT_AUD", cfg.JWTAudience)
-	os.Setenv("PORT", serverPort)
-
-	// Set any extra environment variables
-	for k, v := range cfg.ExtraEnv {
-		os.Setenv(k, v)
 	}
 
-	// Update config with actual port used
-	cfg.ServerPort = serverPort
-
-	// Start server in goroutine
-	serverLogFile, err := os.Create("logs/server-verify.log")
+	serverLogPath := cfg.ServerLogPath
+	if serverLogPath == "" {
+		serverLogPath = DefaultServerLogPath
+	}
+	serverLogFile, err := createServerLog(serverLogPath)
 	if err != nil {
-		return fmt.Errorf("create server log file: %w", err)
+		return err
 	}
 
+	var cmd *exec.Cmd
+	if mode == serverBackground {
+		cmd = exec.CommandContext(ctx, cfg.ServerCommand[0], cfg.ServerCommand[1:]...)
+	} else {
+		cmd = exec.Command(cfg.ServerCommand[0], cfg.ServerCommand[1:]...)
+	}
+	cmd.Env = append(os.Environ(), serverEnv(cfg, infra, serverPort)...)
+	cmd.Stdout, cmd.Stderr = serverOutput(mode == serverBlocking || cfg.MirrorServerOutput, serverLogFile, os.Stdout, os.Stderr)
+
+	releasePort()
+	if err := cmd.Start(); err != nil {
+		serverLogFile.Close()
+		return fmt.Errorf("start server: %w", err)
+	}
+
+	p := &serverProcess{cmd: cmd, done: make(chan struct{})}
+	if mode == serverBlocking {
+		serverMu.Lock()
+		server = p
+		serverMu.Unlock()
+	}
+
+	// Update config with actual port used
+	cfg.ServerPort = serverPort
+	infra.ServerPort = serverPort
+	infra.ServerLogPath = serverLogPath
+	exited := make(chan struct{})
+	infra.serverExited = exited
+
 	go func() {
-		defer serverLogFile.Close()
-		cmd := exec.CommandContext(ctx, cfg.ServerCommand[0], cfg.ServerCommand[1:]...)
-
-		// Explicitly pass environment variables to subprocess
-		cmd.Env = os.Environ() // Start with parent's environment
-		cmd.Stdout = serverLogFile
-		cmd.Stderr = serverLogFile
-
-		if err := cmd.Run(); err != nil {
-			// Context cancellation is expected during cleanup
-			if ctx.Err() == nil {
-				fmt.Printf("❌ Server exited with error: %v\n", err)
-			}
+		err := cmd.Wait()
+		serverLogFile.Close()
+		close(p.done)
+
+		// Cancellation and StopServer are expected during cleanup
+		if (mode == serverBackground && ctx.Err() != nil) || p.stopping.Load() {
+			return
+		}
+		if err != nil {
+			printStatusf(cfg, "❌ Server exited with error: %v\n", err)
 		}
+		infra.serverExitErr = err
+		close(exited)
 	}()
 
 	// Wait for server to be ready
 	healthURL := fmt.Sprintf("http://localhost:%s%s", serverPort, cfg.HealthEndpoint)
 	if err := WaitForHTTP(ctx, healthURL, 15*time.Second); err != nil {
-		return fmt.Errorf("server health check failed: %w", err)
+		return withServerLogTail(fmt.Errorf("server health check failed: %w", err), serverLogPath)
 	}
+	return nil
+}
 
-	// Give migrations extra time to complete (health check may respond before migrations finish)
-	time.Sleep(3 * time.Second)
+// selectServerPort returns the port the server should listen on: a
+// kernel-assigned one under cfg.RandomizePorts or when cfg.ServerPort stays
+// taken. Call release right before starting the server; until then the port
+// is held so nothing else can bind it.
+func selectServerPort(cfg *Config) (port string, release func(), err error) {
+	if !cfg.RandomizePorts {
+		if IsPortAvailable(cfg.ServerPort) {
+			return cfg.ServerPort, func() {}, nil
+		}
 
-	// Signal that server is ready
-	if ready != nil {
-		close(ready)
+		// Kill any existing process on the configured port
+		fmt.Printf("Port %s in use, killing existing process...\n", cfg.ServerPort)
+		killProcessOnPort(cfg.ServerPort)
+		time.Sleep(1 * time.Second)
+		if IsPortAvailable(cfg.ServerPort) {
+			return cfg.ServerPort, func() {}, nil
+		}
 	}
 
-	return nil
+	reserved, err := reservePort()
+	if err != nil {
+		return "", nil, fmt.Errorf("allocate server port: %w", err)
+	}
+	port = strconv.Itoa(reserved.Port)
+	if !cfg.RandomizePorts {
+		fmt.Printf("Using port %s instead\n", port)
+	}
+	return port, reserved.Release, nil
+}
+
+// reservedPort is a kernel-assigned TCP port held open by a listener
+type reservedPort struct {
+	Port     int
+	listener net.Listener
+}
+
+// reservePort listens on :0 and keeps the listener, so the port cannot be
+// handed out again before Release
+func reservePort() (*reservedPort, error) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return nil, err
+	}
+	return &reservedPort{
+		Port:     listener.Addr().(*net.TCPAddr).Port,
+		listener: listener,
+	}, nil
+}
+
+// Release closes the listener so the server can bind the port
+func (r *reservedPort) Release() {
+	r.listener.Close()
+}
+
+// serverEnv returns the environment the server runs with, on top of the
+// parent's. Both start modes share it so traces are exported the same way.
+func serverEnv(cfg *Config, infra *Infrastructure, port string) []string {
+	env := map[string]string{
+		"DATABASE_URL": infra.PostgresURL,
+		"JWT_SECRET":   cfg.JWTSecret,
+		"JWT_ISS":      cfg.JWTIssuer,
+		"JWT_AUD":      cfg.JWTAudience,
+		"PORT":         port,
+	}
+	if cfg.OTELEnabled {
+		env["OTEL_ENABLED"] = "true"
+		env["OTEL_ENDPOINT"] = infra.OtelEndpoint
+		env["OTEL_SERVICE_NAME"] = cfg.OTELServiceName
+		env["OTEL_ENVIRONMENT"] = cfg.OTELEnvironment
+		env["OTEL_EXPORTER_OTLP_INSECURE"] = "true"    // Disable TLS for local testing
+		env["OTEL_EXPORTER_OTLP_TRACES_SYNC"] = "true" // Use sync exporter for immediate trace delivery
+	}
+	// Extra variables override the defaults
+	for k, v := range cfg.ExtraEnv {
+		env[k] = v
+	}
+
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// createServerLog creates (or truncates) the server log file, creating its
+// parent directory so a fresh checkout without logs/ works
+func createServerLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create server log directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create server log file: %w", err)
+	}
+	return f, nil
+}
+
+// serverOutput returns the server's stdout and stderr writers: the log file,
+// teed to the parent's streams when mirror is set
+func serverOutput(mirror bool, logFile io.Writer, stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	if !mirror {
+		return logFile, logFile
+	}
+	return io.MultiWriter(logFile, stdout), io.MultiWriter(logFile, stderr)
+}
+
+// printStatusf prints a server lifecycle message, stripping emoji in plain mode
+func printStatusf(cfg *Config, format string, args ...any) {
+	text := fmt.Sprintf(format, args...)
+	if cfg.PlainMode() {
+		text = terminal.PlainText(text)
+	}
+	fmt.Print(text)
 }
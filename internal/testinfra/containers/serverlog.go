@@ -0,0 +1,129 @@
+package containers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultServerLogTail is how many trailing server log lines a failure reports
+const DefaultServerLogTail = 50
+
+// serverLogProblem matches Go runtime panics and error- or fatal-level lines
+// from common log formats (slog text and JSON, logrus, zap, log.Fatal)
+var serverLogProblem = regexp.MustCompile(`^panic: |^fatal error: |(?i:"?level"?[=:]\s*"?(error|fatal|panic)\b)|\b(ERROR|FATAL|PANIC)\b`)
+
+// ServerLogError reports a server that exited or logged errors, with the
+// tail of its log so the failure points at the cause.
+type ServerLogError struct {
+	Path     string
+	Exited   bool  // the server process stopped while verification was running
+	ExitErr  error // why the process stopped, if it reported an error
+	Problems []string
+	Tail     []string
+}
+
+func (e *ServerLogError) Error() string {
+	var b strings.Builder
+	switch {
+	case e.Exited && e.ExitErr != nil:
+		fmt.Fprintf(&b, "server exited unexpectedly: %v", e.ExitErr)
+	case e.Exited:
+		b.WriteString("server exited unexpectedly")
+	default:
+		fmt.Fprintf(&b, "server logged %d error line(s)", len(e.Problems))
+	}
+	if e.Exited && len(e.Problems) > 0 {
+		fmt.Fprintf(&b, " after logging %d error line(s)", len(e.Problems))
+	}
+	if len(e.Problems) > 0 {
+		fmt.Fprintf(&b, "; first: %s", e.Problems[0])
+	}
+	fmt.Fprintf(&b, "\nlast %d line(s) of %s:", len(e.Tail), e.Path)
+	for _, line := range e.Tail {
+		b.WriteString("\n  " + line)
+	}
+	return b.String()
+}
+
+// ReadServerLog returns the error and panic lines in a server log along with
+// its last tail lines
+func ReadServerLog(path string, tail int) (problems, last []string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open server log: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if serverLogProblem.MatchString(line) {
+			problems = append(problems, line)
+		}
+		if tail > 0 {
+			if len(last) == tail {
+				last = append(last[:0], last[1:]...)
+			}
+			last = append(last, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("read server log: %w", err)
+	}
+	return problems, last, nil
+}
+
+// CheckServerLog asserts that the server started by StartServer or
+// StartServerInBackground is still running and has not logged errors or panics. The returned
+// *ServerLogError includes the last tail lines of the log.
+func CheckServerLog(infra *Infrastructure, tail int) error {
+	if infra.ServerLogPath == "" {
+		return fmt.Errorf("no server log: server has not been started")
+	}
+	problems, last, err := ReadServerLog(infra.ServerLogPath, tail)
+	if err != nil {
+		return err
+	}
+
+	logErr := &ServerLogError{Path: infra.ServerLogPath, Problems: problems, Tail: last}
+	select {
+	case <-infra.serverExited:
+		logErr.Exited = true
+		logErr.ExitErr = infra.serverExitErr
+	default:
+	}
+	if !logErr.Exited && len(problems) == 0 {
+		return nil
+	}
+	return logErr
+}
+
+// WithServerLogContext adds what the server log shows to err, a failure
+// from a phase that ran after the server started: the *ServerLogError from
+// CheckServerLog when the server exited or logged errors (so errors.As
+// finds it), otherwise just the log tail. err is returned as is when no
+// server was started.
+func WithServerLogContext(err error, infra *Infrastructure) error {
+	if err == nil || infra == nil || infra.ServerLogPath == "" {
+		return err
+	}
+	var logErr *ServerLogError
+	if errors.As(CheckServerLog(infra, DefaultServerLogTail), &logErr) {
+		return fmt.Errorf("%w\n%w", err, logErr)
+	}
+	return withServerLogTail(err, infra.ServerLogPath)
+}
+
+// withServerLogTail appends the tail of the server log to a startup error
+func withServerLogTail(err error, path string) error {
+	_, last, readErr := ReadServerLog(path, DefaultServerLogTail)
+	if readErr != nil || len(last) == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\nlast %d line(s) of %s:\n  %s", err, len(last), path, strings.Join(last, "\n  "))
+}
@@ -0,0 +1,213 @@
+package containers
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writeServerLog(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "server.log")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write server log: %v", err)
+	}
+	return path
+}
+
+func TestReadServerLog(t *testing.T) {
+	path := writeServerLog(t, strings.Join([]string{
+		`time=2025-01-01T00:00:00Z level=INFO msg="listening" port=8080`,
+		`{"time":"2025-01-01T00:00:01Z","level":"ERROR","msg":"db ping failed"}`,
+		`2025/01/01 00:00:02 handled request, 0 errors`,
+		`panic: runtime error: invalid memory address or nil pointer dereference`,
+		`goroutine 1 [running]:`,
+	}, "\n")+"\n")
+
+	problems, last, err := ReadServerLog(path, 2)
+	if err != nil {
+		t.Fatalf("ReadServerLog: %v", err)
+	}
+	if len(problems) != 2 || !strings.Contains(problems[0], "db ping failed") || !strings.HasPrefix(problems[1], "panic: ") {
+		t.Fatalf("unexpected problems: %q", problems)
+	}
+	if len(last) != 2 || last[1] != "goroutine 1 [running]:" {
+		t.Fatalf("expected last 2 lines, got %q", last)
+	}
+}
+
+func TestCheckServerLog(t *testing.T) {
+	clean := writeServerLog(t, "level=INFO msg=started\n")
+	infra := &Infrastructure{ServerLogPath: clean, serverExited: make(chan struct{})}
+	if err := CheckServerLog(infra, DefaultServerLogTail); err != nil {
+		t.Fatalf("expected clean log to pass, got %v", err)
+	}
+
+	// A server that stops after becoming healthy is reported even with a clean log
+	infra.serverExitErr = errors.New("exit status 2")
+	close(infra.serverExited)
+	err := CheckServerLog(infra, DefaultServerLogTail)
+	var logErr *ServerLogError
+	if !errors.As(err, &logErr) || !logErr.Exited {
+		t.Fatalf("expected exited ServerLogError, got %v", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "exit status 2") || !strings.Contains(msg, "level=INFO msg=started") {
+		t.Fatalf("expected exit reason and log tail in %q", msg)
+	}
+
+	noisy := writeServerLog(t, "level=INFO msg=started\nlevel=ERROR msg=\"query failed\"\n")
+	err = CheckServerLog(&Infrastructure{ServerLogPath: noisy}, 1)
+	if !errors.As(err, &logErr) || logErr.Exited || len(logErr.Problems) != 1 || len(logErr.Tail) != 1 {
+		t.Fatalf("expected one error line and a one-line tail, got %#v", err)
+	}
+
+	if err := CheckServerLog(&Infrastructure{}, DefaultServerLogTail); err == nil {
+		t.Fatal("expected an error without a server log")
+	}
+}
+
+func TestWithServerLogContext(t *testing.T) {
+	phaseErr := errors.New("trace verification: no trace found")
+
+	// A healthy server adds its log tail
+	clean := writeServerLog(t, "level=INFO msg=started\n")
+	infra := &Infrastructure{ServerLogPath: clean, serverExited: make(chan struct{})}
+	err := WithServerLogContext(phaseErr, infra)
+	if !errors.Is(err, phaseErr) || !strings.Contains(err.Error(), "level=INFO msg=started") {
+		t.Fatalf("expected the phase error with the log tail, got %v", err)
+	}
+
+	// A crashed server is reported as a ServerLogError
+	infra.serverExitErr = errors.New("signal: killed")
+	close(infra.serverExited)
+	err = WithServerLogContext(phaseErr, infra)
+	var logErr *ServerLogError
+	if !errors.Is(err, phaseErr) || !errors.As(err, &logErr) || !logErr.Exited {
+		t.Fatalf("expected the phase error and an exited ServerLogError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "signal: killed") {
+		t.Fatalf("expected the exit reason in %q", err)
+	}
+
+	// Before the server starts there is nothing to add
+	if err := WithServerLogContext(phaseErr, &Infrastructure{}); err != phaseErr {
+		t.Fatalf("expected the error unchanged without a server log, got %v", err)
+	}
+	if err := WithServerLogContext(nil, infra); err != nil {
+		t.Fatalf("expected nil for a nil error, got %v", err)
+	}
+}
+
+func TestCreateServerLogMakesParentDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "nested", "server.log")
+	f, err := createServerLog(path)
+	if err != nil {
+		t.Fatalf("createServerLog: %v", err)
+	}
+	f.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected log file to exist: %v", err)
+	}
+}
+
+func TestServerOutputMirror(t *testing.T) {
+	var logFile, stdout, stderr bytes.Buffer
+
+	outW, errW := serverOutput(false, &logFile, &stdout, &stderr)
+	outW.Write([]byte("out\n"))
+	errW.Write([]byte("err\n"))
+	if logFile.String() != "out\nerr\n" || stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatalf("expected output only in the log file, got log=%q stdout=%q stderr=%q", logFile.String(), stdout.String(), stderr.String())
+	}
+
+	logFile.Reset()
+	outW, errW = serverOutput(true, &logFile, &stdout, &stderr)
+	outW.Write([]byte("out\n"))
+	errW.Write([]byte("err\n"))
+	if logFile.String() != "out\nerr\n" || stdout.String() != "out\n" || stderr.String() != "err\n" {
+		t.Fatalf("expected mirrored output, got log=%q stdout=%q stderr=%q", logFile.String(), stdout.String(), stderr.String())
+	}
+}
+
+func TestServerEnv(t *testing.T) {
+	cfg := &Config{
+		OTELEnabled:     true,
+		OTELServiceName: "svc",
+		JWTSecret:       "secret",
+		ExtraEnv:        map[string]string{"PORT": "9999", "EXTRA": "1"},
+	}
+	infra := &Infrastructure{PostgresURL: "postgres://db", OtelEndpoint: "localhost:4317"}
+
+	env := strings.Join(serverEnv(cfg, infra, "8080"), "\n")
+	for _, want := range []string{
+		"DATABASE_URL=postgres://db",
+		"JWT_SECRET=secret",
+		"OTEL_ENDPOINT=localhost:4317",
+		"OTEL_SERVICE_NAME=svc",
+		// Both start modes must export traces synchronously
+		"OTEL_EXPORTER_OTLP_TRACES_SYNC=true",
+		"OTEL_EXPORTER_OTLP_INSECURE=true",
+		"EXTRA=1",
+		"PORT=9999",
+	} {
+		if !strings.Contains(env, want) {
+			t.Fatalf("expected %s in:\n%s", want, env)
+		}
+	}
+	if strings.Contains(env, "PORT=8080") {
+		t.Fatalf("expected ExtraEnv to override PORT:\n%s", env)
+	}
+
+	cfg.OTELEnabled = false
+	if env := strings.Join(serverEnv(cfg, infra, "8080"), "\n"); strings.Contains(env, "OTEL_") {
+		t.Fatalf("expected no OTEL variables when disabled:\n%s", env)
+	}
+}
+
+func TestSelectServerPortReservesTakenPort(t *testing.T) {
+	// Occupy the configured port; the listener is ours, so stub out killing its owner
+	busy, err := reservePort()
+	if err != nil {
+		t.Fatalf("reservePort: %v", err)
+	}
+	defer busy.Release()
+	configured := strconv.Itoa(busy.Port)
+
+	kill := killProcessOnPort
+	t.Cleanup(func() { killProcessOnPort = kill })
+	var killed []string
+	killProcessOnPort = func(port string) { killed = append(killed, port) }
+
+	port, release, err := selectServerPort(&Config{ServerPort: configured})
+	if err != nil {
+		t.Fatalf("selectServerPort: %v", err)
+	}
+	if len(killed) != 1 || killed[0] != configured {
+		t.Fatalf("expected one attempt to free port %s, got %v", configured, killed)
+	}
+	if port == "" || port == configured {
+		t.Fatalf("expected a fallback port instead of %s, got %q", configured, port)
+	}
+	// The port stays held until release, so nothing else can take it
+	if IsPortAvailable(port) {
+		t.Fatalf("expected port %s to be held before release", port)
+	}
+	release()
+	if !IsPortAvailable(port) {
+		t.Fatalf("expected port %s to be free after release", port)
+	}
+
+	free := port
+	port, release, err = selectServerPort(&Config{ServerPort: free})
+	if err != nil {
+		t.Fatalf("selectServerPort: %v", err)
+	}
+	release()
+	if port != free {
+		t.Fatalf("expected the configured free port %s, got %q", free, port)
+	}
+}
@@ -0,0 +1,227 @@
+package containers
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+)
+
+//go:generate go run gen_otel_config.go ../../../config/observability/otel-collector-config.yaml
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// OtelTemplateData holds the values substituted into the OTEL collector config template
+type OtelTemplateData struct {
+	ServiceName           string
+	Environment           string
+	TraceExporterEndpoint string // OTLP gRPC destination for traces (Jaeger, Tempo, cloud)
+	TraceExporterInsecure bool
+	SamplingPercentage    float64 // 100 keeps every trace
+	BatchTimeout          time.Duration
+	BatchSize             int
+	MetricsNamespace      string
+
+	MetricsExporterName     string // OTLPMetricsExporter
+	MetricsExporterEndpoint string // OTLP gRPC destination for metrics; empty keeps metrics on the Prometheus exporter only
+	MetricsExporterInsecure bool
+}
+
+// otelTemplateData derives collector template values from the config
+func otelTemplateData(cfg *Config) OtelTemplateData {
+	sampling := cfg.OtelSamplingPercentage
+	if sampling <= 0 || sampling > 100 {
+		sampling = 100
+	}
+	return OtelTemplateData{
+		ServiceName:           cfg.OTELServiceName,
+		Environment:           cfg.OTELEnvironment,
+		TraceExporterEndpoint: cfg.OtelTraceExporterEndpoint,
+		TraceExporterInsecure: cfg.OtelTraceExporterInsecure,
+		SamplingPercentage:    sampling,
+		BatchTimeout:          time.Second,
+		BatchSize:             1,
+		MetricsNamespace:      "skillflow",
+
+		MetricsExporterName:     OTLPMetricsExporter,
+		MetricsExporterEndpoint: cfg.OtelMetricsExporterEndpoint,
+		MetricsExporterInsecure: cfg.OtelMetricsExporterInsecure,
+	}
+}
+
+// StaticOtelConfigHeader starts config/observability/otel-collector-config.yaml,
+// the collector config compose mounts when air has not rendered one
+const StaticOtelConfigHeader = "# Code generated from internal/testinfra/containers/templates/otel-collector-config.yaml.tmpl\n" +
+	"# by go generate ./internal/testinfra/containers; DO NOT EDIT.\n\n"
+
+// StaticOtelTemplateData is what the static collector config is rendered
+// with: the development stack's Jaeger, every trace kept, and metrics on the
+// Prometheus exporter only
+func StaticOtelTemplateData() OtelTemplateData {
+	return OtelTemplateData{
+		ServiceName:           "skillflow-backend",
+		Environment:           "development",
+		TraceExporterEndpoint: "jaeger:4317",
+		TraceExporterInsecure: true,
+		SamplingPercentage:    100,
+		BatchTimeout:          time.Second,
+		BatchSize:             1,
+		MetricsNamespace:      "skillflow",
+		MetricsExporterName:   OTLPMetricsExporter,
+	}
+}
+
+// RenderStaticOtelConfig renders config/observability/otel-collector-config.yaml
+// from the built-in template, header included
+func RenderStaticOtelConfig() ([]byte, error) {
+	out, err := renderTemplate("otel-collector-config.yaml.tmpl", "", StaticOtelTemplateData())
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(StaticOtelConfigHeader), out...), nil
+}
+
+// RenderOtelConfig renders the OTEL collector config from cfg.OtelConfigTemplate,
+// or from the built-in template when no template path is set.
+func RenderOtelConfig(cfg *Config) ([]byte, error) {
+	return renderTemplate("otel-collector-config.yaml.tmpl", cfg.OtelConfigTemplate, otelTemplateData(cfg))
+}
+
+// WriteOtelConfig renders the collector config to a temp file for bind mounting.
+// The returned cleanup removes the file.
+func WriteOtelConfig(cfg *Config) (string, func(), error) {
+	data, err := RenderOtelConfig(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	return writeTempConfig("air-otel-collector-*.yaml", data)
+}
+
+// ScrapeTarget is a Prometheus scrape job rendered into the Prometheus config
+type ScrapeTarget struct {
+	JobName     string
+	Targets     []string      // host:port as seen from the Prometheus container
+	MetricsPath string        // defaults to /metrics when empty
+	Interval    time.Duration // overrides the global scrape interval when non-zero
+}
+
+// PrometheusTemplateData holds the values substituted into the Prometheus config template
+type PrometheusTemplateData struct {
+	ScrapeInterval     time.Duration
+	EvaluationInterval time.Duration
+	Targets            []ScrapeTarget
+}
+
+// DefaultScrapeTargets returns the collector and self-monitoring scrape jobs
+func DefaultScrapeTargets() []ScrapeTarget {
+	return []ScrapeTarget{
+		{JobName: "otel-collector", Targets: []string{"otel-collector:8889"}},
+		{JobName: "otel-collector-internal", Targets: []string{"otel-collector:8888"}},
+		{JobName: "prometheus", Targets: []string{"localhost:9090"}},
+	}
+}
+
+// RenderPrometheusConfig renders the Prometheus config from cfg.PrometheusConfigTemplate,
+// or from the built-in template when no template path is set.
+func RenderPrometheusConfig(cfg *Config) ([]byte, error) {
+	interval := cfg.PrometheusScrapeInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return renderTemplate("prometheus.yml.tmpl", cfg.PrometheusConfigTemplate, PrometheusTemplateData{
+		ScrapeInterval:     interval,
+		EvaluationInterval: interval,
+		Targets:            cfg.PrometheusScrapeTargets,
+	})
+}
+
+// WritePrometheusConfig renders the Prometheus config to a temp file for bind mounting.
+// The returned cleanup removes the file.
+func WritePrometheusConfig(cfg *Config) (string, func(), error) {
+	data, err := RenderPrometheusConfig(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	return writeTempConfig("air-prometheus-*.yml", data)
+}
+
+// templateFuncs are available to the built-in and custom config templates
+var templateFuncs = template.FuncMap{
+	"promDuration": promDuration,
+}
+
+// promDuration formats d in the largest unit that divides it exactly (90s,
+// 1500ms, 2m). Prometheus's duration parser rejects time.Duration.String
+// forms such as 1.5s; anything below a millisecond rounds up to 1ms.
+func promDuration(d time.Duration) string {
+	for _, unit := range []struct {
+		size   time.Duration
+		suffix string
+	}{
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	} {
+		if d >= unit.size && d%unit.size == 0 {
+			return fmt.Sprintf("%d%s", d/unit.size, unit.suffix)
+		}
+	}
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	return fmt.Sprintf("%dms", max(ms, 1))
+}
+
+// renderTemplate executes the template at path, falling back to the embedded template name
+func renderTemplate(name, path string, data any) ([]byte, error) {
+	var (
+		src []byte
+		err error
+	)
+	if path != "" {
+		src, err = os.ReadFile(path)
+	} else {
+		src, err = templateFS.ReadFile("templates/" + name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read template %s: %w", name, err)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeTempConfig writes rendered config to a world-readable temp file
+// (containers may run as a non-root user).
+func writeTempConfig(pattern string, data []byte) (string, func(), error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp config: %w", err)
+	}
+	path := f.Name()
+	cleanup := func() { os.Remove(path) }
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("write temp config: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("close temp config: %w", err)
+	}
+	if err := os.Chmod(path, 0o644); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("chmod temp config: %w", err)
+	}
+	return path, cleanup, nil
+}
@@ -0,0 +1,217 @@
+package containers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderOtelConfig(t *testing.T) {
+	cfg := &Config{
+		OTELServiceName:           "backend",
+		OTELEnvironment:           "ci",
+		OtelTraceExporterEndpoint: "tempo:4317",
+		OtelTraceExporterInsecure: true,
+		OtelSamplingPercentage:    100,
+	}
+
+	out, err := RenderOtelConfig(cfg)
+	if err != nil {
+		t.Fatalf("RenderOtelConfig error: %v", err)
+	}
+
+	var parsed struct {
+		Exporters map[string]map[string]any `yaml:"exporters"`
+		Service   struct {
+			Pipelines map[string]struct {
+				Processors []string `yaml:"processors"`
+			} `yaml:"pipelines"`
+		} `yaml:"service"`
+	}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("rendered config is not valid YAML: %v\n%s", err, out)
+	}
+	if got := parsed.Exporters["otlp/jaeger"]["endpoint"]; got != "tempo:4317" {
+		t.Fatalf("expected trace exporter endpoint tempo:4317, got %v", got)
+	}
+	if strings.Contains(string(out), "probabilistic_sampler") {
+		t.Fatal("sampler should be omitted at 100%")
+	}
+	if !strings.Contains(string(out), "value: ci") {
+		t.Fatal("expected deployment.environment from config")
+	}
+
+	cfg.OtelSamplingPercentage = 25
+	out, err = RenderOtelConfig(cfg)
+	if err != nil {
+		t.Fatalf("RenderOtelConfig error: %v", err)
+	}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("rendered config is not valid YAML: %v", err)
+	}
+	if processors := parsed.Service.Pipelines["traces"].Processors; len(processors) < 2 || processors[1] != "probabilistic_sampler" {
+		t.Fatalf("expected sampler in traces pipeline, got %v", processors)
+	}
+}
+
+func TestRenderOtelConfigOTLPMetricsExporter(t *testing.T) {
+	cfg := &Config{OtelTraceExporterEndpoint: "jaeger:4317", OtelSamplingPercentage: 100}
+
+	var parsed struct {
+		Exporters map[string]map[string]any `yaml:"exporters"`
+		Service   struct {
+			Pipelines map[string]struct {
+				Exporters []string `yaml:"exporters"`
+			} `yaml:"pipelines"`
+		} `yaml:"service"`
+	}
+	out, err := RenderOtelConfig(cfg)
+	if err != nil {
+		t.Fatalf("RenderOtelConfig error: %v", err)
+	}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("rendered config is not valid YAML: %v", err)
+	}
+	if _, ok := parsed.Exporters[OTLPMetricsExporter]; ok {
+		t.Fatal("OTLP metrics exporter rendered without an endpoint")
+	}
+
+	cfg.OtelMetricsExporterEndpoint = "mimir:4317"
+	cfg.OtelMetricsExporterInsecure = true
+	out, err = RenderOtelConfig(cfg)
+	if err != nil {
+		t.Fatalf("RenderOtelConfig error: %v", err)
+	}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("rendered config is not valid YAML: %v\n%s", err, out)
+	}
+	if got := parsed.Exporters[OTLPMetricsExporter]["endpoint"]; got != "mimir:4317" {
+		t.Fatalf("expected metrics exporter endpoint mimir:4317, got %v", got)
+	}
+	if got := strings.Join(parsed.Service.Pipelines["metrics"].Exporters, ","); got != "prometheus,otlp/metrics,debug" {
+		t.Fatalf("metrics pipeline exporters = %s", got)
+	}
+}
+
+func TestRenderOtelConfigCustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.tmpl")
+	if err := os.WriteFile(path, []byte("endpoint: {{ .TraceExporterEndpoint }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := RenderOtelConfig(&Config{OtelConfigTemplate: path, OtelTraceExporterEndpoint: "otlp.example.com:443"})
+	if err != nil {
+		t.Fatalf("RenderOtelConfig error: %v", err)
+	}
+	if string(out) != "endpoint: otlp.example.com:443\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	if err := os.WriteFile(path, []byte("{{ .Missing }}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RenderOtelConfig(&Config{OtelConfigTemplate: path}); err == nil {
+		t.Fatal("expected error for unknown template field")
+	}
+}
+
+func TestWriteOtelConfig(t *testing.T) {
+	path, cleanup, err := WriteOtelConfig(&Config{OtelTraceExporterEndpoint: "jaeger:4317"})
+	if err != nil {
+		t.Fatalf("WriteOtelConfig error: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected rendered file: %v", err)
+	}
+	cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("expected cleanup to remove the file")
+	}
+}
+
+func TestRenderPrometheusConfig(t *testing.T) {
+	cfg := &Config{
+		PrometheusScrapeInterval: 1500 * time.Millisecond,
+		PrometheusScrapeTargets: append(DefaultScrapeTargets(), ScrapeTarget{
+			JobName:     "worker",
+			Targets:     []string{"worker:9100", "worker-2:9100"},
+			MetricsPath: "/internal/metrics",
+			Interval:    90 * time.Second,
+		}),
+	}
+
+	out, err := RenderPrometheusConfig(cfg)
+	if err != nil {
+		t.Fatalf("RenderPrometheusConfig error: %v", err)
+	}
+
+	var parsed struct {
+		Global struct {
+			ScrapeInterval string `yaml:"scrape_interval"`
+		} `yaml:"global"`
+		ScrapeConfigs []struct {
+			JobName        string `yaml:"job_name"`
+			ScrapeInterval string `yaml:"scrape_interval"`
+			MetricsPath    string `yaml:"metrics_path"`
+			StaticConfigs  []struct {
+				Targets []string `yaml:"targets"`
+			} `yaml:"static_configs"`
+		} `yaml:"scrape_configs"`
+	}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("rendered config is not valid YAML: %v\n%s", err, out)
+	}
+
+	// Whole units Prometheus accepts, not Go's 1.5s or 1m30s
+	if parsed.Global.ScrapeInterval != "1500ms" {
+		t.Fatalf("expected global interval 1500ms, got %q", parsed.Global.ScrapeInterval)
+	}
+	if len(parsed.ScrapeConfigs) != 4 {
+		t.Fatalf("expected 4 scrape jobs, got %d:\n%s", len(parsed.ScrapeConfigs), out)
+	}
+	worker := parsed.ScrapeConfigs[3]
+	if worker.JobName != "worker" || worker.ScrapeInterval != "90s" || worker.MetricsPath != "/internal/metrics" {
+		t.Fatalf("unexpected worker job: %+v", worker)
+	}
+	if targets := worker.StaticConfigs[0].Targets; len(targets) != 2 || targets[1] != "worker-2:9100" {
+		t.Fatalf("unexpected worker targets: %v", targets)
+	}
+	if parsed.ScrapeConfigs[0].MetricsPath != "" {
+		t.Fatal("metrics_path should be omitted when unset")
+	}
+}
+
+func TestPromDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		5 * time.Second:         "5s",
+		90 * time.Second:        "90s",
+		2 * time.Minute:         "2m",
+		time.Hour:               "1h",
+		1500 * time.Millisecond: "1500ms",
+		250 * time.Microsecond:  "1ms",
+		0:                       "1ms",
+	}
+	for d, want := range tests {
+		if got := promDuration(d); got != want {
+			t.Fatalf("promDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestStaticOtelConfigMatchesTemplate(t *testing.T) {
+	want, err := RenderStaticOtelConfig()
+	if err != nil {
+		t.Fatalf("RenderStaticOtelConfig: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join("..", "..", "..", "config", "observability", "otel-collector-config.yaml"))
+	if err != nil {
+		t.Fatalf("read static config: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("config/observability/otel-collector-config.yaml is out of date with the template; run go generate ./internal/testinfra/containers")
+	}
+}
@@ -0,0 +1,70 @@
+package containers
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TrafficServiceSuffix is appended to cfg.ServiceName for spans emitted by the traffic generator
+const TrafficServiceSuffix = "-traffic"
+
+// trafficTracer emits client-side spans for generated traffic so the server
+// can continue the trace via the traceparent carried in envelope meta.
+type trafficTracer struct {
+	tracer   trace.Tracer
+	shutdown func(context.Context) error
+}
+
+// newTrafficTracer exports client spans to the OTEL collector when OTEL is enabled,
+// and is a no-op (no traceparent injected) otherwise.
+func newTrafficTracer(ctx context.Context, cfg *Config, infra *Infrastructure) (*trafficTracer, error) {
+	if !cfg.OTELEnabled || infra.OtelEndpoint == "" {
+		return &trafficTracer{
+			tracer:   noop.NewTracerProvider().Tracer("air-traffic"),
+			shutdown: func(context.Context) error { return nil },
+		}, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(infra.OtelEndpoint),
+		otlptracegrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create traffic span exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceName(cfg.ServiceName+TrafficServiceSuffix),
+		semconv.DeploymentEnvironment(cfg.OTELEnvironment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create traffic resource: %w", err)
+	}
+
+	// Sync export so spans are in the collector before verification starts
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+
+	return &trafficTracer{tracer: tp.Tracer("air-traffic"), shutdown: tp.Shutdown}, nil
+}
+
+// injectTraceParent returns the W3C traceparent header for the span in ctx,
+// or "" when ctx carries no valid span context.
+func injectTraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
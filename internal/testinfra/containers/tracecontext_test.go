@@ -0,0 +1,40 @@
+package containers
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectTraceParent(t *testing.T) {
+	if got := injectTraceParent(context.Background()); got != "" {
+		t.Fatalf("expected no traceparent without a span, got %q", got)
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	ctx, span := tp.Tracer("test").Start(context.Background(), "ws.client.send")
+	defer span.End()
+
+	sc := span.SpanContext()
+	want := "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-01"
+	if got := injectTraceParent(ctx); got != want {
+		t.Fatalf("traceparent = %q, want %q", got, want)
+	}
+}
+
+func TestTrafficTracerNoopWhenOTELDisabled(t *testing.T) {
+	tt, err := newTrafficTracer(context.Background(), &Config{OTELEnabled: false}, &Infrastructure{OtelEndpoint: "localhost:4317"})
+	if err != nil {
+		t.Fatalf("newTrafficTracer: %v", err)
+	}
+	defer tt.shutdown(context.Background())
+
+	ctx, span := tt.tracer.Start(context.Background(), "traffic.session", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	if got := injectTraceParent(ctx); got != "" {
+		t.Fatalf("expected no traceparent with OTEL disabled, got %q", got)
+	}
+}
@@ -7,16 +7,34 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/fasthttp/websocket"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/raja-aiml/air/internal/foundation/auth"
 )
 
 type CorrelationIDs map[string]string
 
+// String formats the IDs as space-separated key=value pairs in key order
+func (ids CorrelationIDs) String() string {
+	keys := make([]string, 0, len(ids))
+	for k := range ids {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + ids[k]
+	}
+	return strings.Join(pairs, " ")
+}
+
 type envelope struct {
 	Event string          `json:"event"`
 	Data  json.RawMessage `json:"data"`
@@ -28,6 +46,9 @@ type meta struct {
 	UserID    string `json:"user_id"`
 	SessionID string `json:"session_id"`
 	RequestID string `json:"request_id,omitempty"`
+
+	// TraceParent carries the client span context (W3C traceparent format)
+	TraceParent string `json:"traceparent,omitempty"`
 }
 
 type widgetEnvelope struct {
@@ -58,6 +79,27 @@ func GenerateTraffic(ctx context.Context, cfg *Config, infra *Infrastructure, re
 	}
 	defer conn.Close()
 
+	tt, err := newTrafficTracer(ctx, cfg, infra)
+	if err != nil {
+		return nil, err
+	}
+	defer tt.shutdown(context.WithoutCancel(ctx))
+
+	// Every envelope is sent as a child of this span
+	ctx, rootSpan := tt.tracer.Start(ctx, "traffic.session", trace.WithSpanKind(trace.SpanKindClient))
+	defer rootSpan.End()
+
+	send := func(env envelope) error {
+		spanCtx, span := tt.tracer.Start(ctx, "ws.client.send "+env.Event, trace.WithSpanKind(trace.SpanKindProducer))
+		defer span.End()
+		env.Meta.TraceParent = injectTraceParent(spanCtx)
+		if err := conn.WriteJSON(env); err != nil {
+			span.RecordError(err)
+			return err
+		}
+		return nil
+	}
+
 	requestID := uuid.New().String()
 
 	connect := envelope{
@@ -71,7 +113,7 @@ func GenerateTraffic(ctx context.Context, cfg *Config, infra *Infrastructure, re
 		},
 	}
 
-	if err := conn.WriteJSON(connect); err != nil {
+	if err := send(connect); err != nil {
 		return nil, fmt.Errorf("send client.connect: %w", err)
 	}
 
@@ -112,7 +154,7 @@ func GenerateTraffic(ctx context.Context, cfg *Config, infra *Infrastructure, re
 		},
 	}
 
-	if err := conn.WriteJSON(nextReq); err != nil {
+	if err := send(nextReq); err != nil {
 		return nil, fmt.Errorf("send kc.request.next: %w", err)
 	}
 
@@ -156,7 +198,7 @@ func GenerateTraffic(ctx context.Context, cfg *Config, infra *Infrastructure, re
 		},
 	}
 
-	if err := conn.WriteJSON(answerEnv); err != nil {
+	if err := send(answerEnv); err != nil {
 		return nil, fmt.Errorf("send kc.answer.submit: %w", err)
 	}
 
@@ -188,25 +230,40 @@ func GenerateTraffic(ctx context.Context, cfg *Config, infra *Infrastructure, re
 		// Silent - non-critical
 	}
 
-	return CorrelationIDs{
+	ids := CorrelationIDs{
 		"user_id":    userID,
 		"session_id": sessionID,
 		"request_id": nextReqID,
-	}, nil
+	}
+	if sc := rootSpan.SpanContext(); sc.IsValid() {
+		ids["trace_id"] = sc.TraceID().String()
+	}
+	report.Info("Correlation IDs: %s", ids)
+	return ids, nil
 }
 
+// generateJWT signs a 10-minute token for userID: RS256 with the key at
+// cfg.JWTPrivateKeyPath when set, otherwise HS256 with cfg.JWTSecret
 func generateJWT(userID string, cfg *Config) (string, error) {
-	now := time.Now()
-	claims := jwt.MapClaims{
-		"sub": userID,
-		"iss": cfg.JWTIssuer,
-		"aud": cfg.JWTAudience,
-		"iat": now.Unix(),
-		"exp": now.Add(10 * time.Minute).Unix(),
+	claims := auth.TokenClaims{
+		Subject:    userID,
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		ExpMinutes: 10,
+	}
+	if cfg.JWTPrivateKeyPath == "" {
+		return auth.GenerateToken(claims, cfg.JWTSecret)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.JWTSecret))
+	data, err := os.ReadFile(cfg.JWTPrivateKeyPath)
+	if err != nil {
+		return "", fmt.Errorf("read JWT private key: %w", err)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return "", fmt.Errorf("parse JWT private key %s: %w", cfg.JWTPrivateKeyPath, err)
+	}
+	return auth.GenerateTokenRS256(claims, key)
 }
 
 func mustJSON(v interface{}) json.RawMessage {
@@ -0,0 +1,47 @@
+package containers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raja-aiml/air/internal/foundation/auth"
+)
+
+func TestGenerateJWTSigningMethod(t *testing.T) {
+	cfg := &Config{JWTSecret: "test-secret", JWTIssuer: "skill-flow", JWTAudience: "skill-flow-app"}
+
+	token, err := generateJWT("user-1", cfg)
+	if err != nil {
+		t.Fatalf("generateJWT HS256: %v", err)
+	}
+	if claims, err := auth.VerifyToken(token, auth.SigningMethodHS256, []byte(cfg.JWTSecret)); err != nil || claims.Subject != "user-1" {
+		t.Fatalf("verify HS256 token = %+v, %v", claims, err)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	cfg.JWTPrivateKeyPath = filepath.Join(t.TempDir(), "jwt.pem")
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if err := os.WriteFile(cfg.JWTPrivateKeyPath, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	token, err = generateJWT("user-1", cfg)
+	if err != nil {
+		t.Fatalf("generateJWT RS256: %v", err)
+	}
+	claims, err := auth.ParseAndVerifyRS256(token, &key.PublicKey)
+	if err != nil {
+		t.Fatalf("verify RS256 token: %v", err)
+	}
+	if claims.Issuer != cfg.JWTIssuer || claims.Audience != cfg.JWTAudience || claims.ExpMinutes != 10 {
+		t.Fatalf("claims = %+v", claims)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build integration
+
+package testinfra
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+func TestEmbeddedBackend(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := containers.DefaultConfig()
+	cfg.Backend = containers.BackendEmbedded
+
+	infra, err := containers.Start(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Failed to start embedded infrastructure: %v", err)
+	}
+	defer containers.CleanupInfrastructure(infra)
+
+	if err := containers.VerifyPostgresHealth(ctx, infra.PostgresURL); err != nil {
+		t.Fatalf("Postgres health check failed: %v", err)
+	}
+	if err := containers.VerifyJaegerHealth(ctx, infra.JaegerURL); err != nil {
+		t.Fatalf("Jaeger stub health check failed: %v", err)
+	}
+	if err := containers.VerifyPrometheusHealth(ctx, infra.PrometheusURL); err != nil {
+		t.Fatalf("Prometheus stub health check failed: %v", err)
+	}
+
+	t.Log("✅ Embedded backend ready without Docker")
+}
+
+func TestWaitForSchemaTables(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := containers.DefaultConfig()
+	cfg.Backend = containers.BackendEmbedded
+
+	infra, err := containers.Start(ctx, cfg)
+	if err != nil {
+		t.Fatalf("Failed to start embedded infrastructure: %v", err)
+	}
+	defer containers.CleanupInfrastructure(infra)
+
+	db, err := sql.Open("postgres", infra.PostgresURL)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, `CREATE TABLE first_table (id INT)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	shortCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	if err := containers.WaitForSchema(shortCtx, infra.PostgresURL, "first_table", "second_table"); err == nil || !strings.Contains(err.Error(), "second_table") {
+		t.Fatalf("expected second_table to be reported missing, got %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE second_table (id INT)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if err := containers.WaitForSchema(ctx, infra.PostgresURL, "first_table", "second_table"); err != nil {
+		t.Fatalf("expected schema to be ready: %v", err)
+	}
+}
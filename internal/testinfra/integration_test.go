@@ -2,9 +2,11 @@ package testinfra
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/raja-aiml/air/internal/foundation/compose"
 	"github.com/raja-aiml/air/internal/testinfra/containers"
 	"github.com/raja-aiml/air/internal/testinfra/tests"
 )
@@ -15,7 +17,13 @@ func TestObservabilityPipeline(t *testing.T) {
 	// Phase 1: Start infrastructure
 	t.Log("🔵 Starting Infrastructure")
 	cfg := containers.DefaultConfig()
+	if cfg.Backend == containers.BackendEmbedded {
+		t.Skip("pipeline test requires the compose backend (AIR_TEST_BACKEND=embedded)")
+	}
 	infra, err := containers.StartWithCompose(ctx, cfg)
+	if errors.Is(err, compose.ErrDockerUnavailable) {
+		t.Skipf("Docker not available: %v", err)
+	}
 	if err != nil {
 		t.Fatalf("Failed to start infrastructure: %v", err)
 	}
@@ -46,18 +54,33 @@ func TestObservabilityPipeline(t *testing.T) {
 		t.Fatal("Server startup timeout")
 	}
 
-	// Phase 5: Run verification tests
+	// Phase 5: Run verification tests. A server crash surfaces as the
+	// failure instead of a confusing downstream error.
 	t.Run("TracesPropagation", func(t *testing.T) {
 		if err := tests.VerifyTracesPropagation(t, ctx, cfg, infra); err != nil {
-			t.Fatalf("Traces verification failed: %v", err)
+			failWithServerLog(t, infra, "Traces verification failed: %v", err)
 		}
 	})
 
 	t.Run("MetricsCollection", func(t *testing.T) {
 		if err := tests.VerifyMetricsCollection(t, ctx, cfg, infra); err != nil {
-			t.Fatalf("Metrics verification failed: %v", err)
+			failWithServerLog(t, infra, "Metrics verification failed: %v", err)
 		}
 	})
 
+	if err := containers.CheckServerLog(infra, containers.DefaultServerLogTail); err != nil {
+		t.Fatalf("Server log check failed: %v", err)
+	}
+
 	t.Log("\n✅ All observability pipeline tests passed!")
 }
+
+// failWithServerLog fails the test, reporting a server crash or logged
+// error ahead of the verification error it likely caused
+func failWithServerLog(t *testing.T, infra *containers.Infrastructure, format string, err error) {
+	t.Helper()
+	if logErr := containers.CheckServerLog(infra, containers.DefaultServerLogTail); logErr != nil {
+		t.Fatalf("%v\n"+format, logErr, err)
+	}
+	t.Fatalf(format, err)
+}
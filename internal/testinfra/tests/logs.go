@@ -6,6 +6,7 @@ import (
 	"io"
 	"strings"
 
+	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
 	"github.com/raja-aiml/air/internal/testinfra/containers"
 )
 
@@ -24,33 +25,16 @@ func VerifyOtelCollectorLogs(t TestingT, ctx context.Context, infra *containers.
 		return fmt.Errorf("failed to read OTEL logs: %w", err)
 	}
 
-	logContent := string(logBytes)
-
-	// Check for common errors (including Jaeger export errors)
-	errorPatterns := []string{
-		"connection refused",
-		"dial tcp.*failed",
-		"TLS handshake",
-		"authentication handshake failed",
-		"no such host",
-		"connection error",
-		"Exporting failed",
-		"failed to export",
-		"error sending spans",
-	}
-
+	// Report the first line for each kind of export error
 	foundErrors := []string{}
-	for _, pattern := range errorPatterns {
-		if strings.Contains(strings.ToLower(logContent), strings.ToLower(pattern)) {
-			// Extract the line with the error
-			lines := strings.Split(logContent, "\n")
-			for _, line := range lines {
-				if strings.Contains(strings.ToLower(line), strings.ToLower(pattern)) {
-					foundErrors = append(foundErrors, line)
-					break
-				}
-			}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(string(logBytes), "\n") {
+		pattern := telemetry.CollectorExportError(line)
+		if pattern == "" || seen[pattern] {
+			continue
 		}
+		seen[pattern] = true
+		foundErrors = append(foundErrors, line)
 	}
 
 	if len(foundErrors) > 0 {
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"github.com/raja-aiml/air/internal/testinfra/containers"
 	"github.com/raja-aiml/air/internal/testinfra/verification"
+	"time"
 )
 
 // VerifyTracesPropagation generates traffic and verifies traces reach Jaeger
 func VerifyTracesPropagation(t TestingT, ctx context.Context, cfg *containers.Config, infra *containers.Infrastructure) error {
 	report := containers.NewReport(false) // Use verbose mode, not JSON
+	report.SetPlainMode(cfg.PlainMode())
 
+	trafficStart := time.Now()
 	correlationIDs, err := containers.GenerateTraffic(ctx, cfg, infra, report)
 	if err != nil {
 		return fmt.Errorf("generate traffic: %w", err)
@@ -29,7 +32,7 @@ func VerifyTracesPropagation(t TestingT, ctx context.Context, cfg *containers.Co
 		t.Logf("⚠️  Jaeger has issues: %v", err)
 	}
 
-	if err := verification.VerifyJaegerTraces(ctx, cfg, infra.JaegerURL, correlationIDs, report); err != nil {
+	if err := verification.VerifyJaegerTraces(ctx, cfg, infra.JaegerURL, correlationIDs, trafficStart, report); err != nil {
 		return fmt.Errorf("verify jaeger traces: %w", err)
 	}
 
@@ -39,6 +42,7 @@ func VerifyTracesPropagation(t TestingT, ctx context.Context, cfg *containers.Co
 // VerifyMetricsCollection verifies metrics are collected in Prometheus, OTEL collector, and server /metrics endpoint
 func VerifyMetricsCollection(t TestingT, ctx context.Context, cfg *containers.Config, infra *containers.Infrastructure) error {
 	report := containers.NewReport(false) // Use verbose mode, not JSON
+	report.SetPlainMode(cfg.PlainMode())
 
 	if err := verification.VerifyPrometheusMetrics(ctx, infra.PrometheusURL, report); err != nil {
 		return fmt.Errorf("verify prometheus: %w", err)
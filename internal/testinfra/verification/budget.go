@@ -0,0 +1,42 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPhaseTimeout is returned when a verification phase exceeds its time budget
+var ErrPhaseTimeout = errors.New("verification phase exceeded its time budget")
+
+// ErrDeadlineExhausted is returned when too little of the overall deadline remains to start a phase
+var ErrDeadlineExhausted = errors.New("verification deadline exhausted")
+
+// runPhase runs fn with a context limited to budget, further capped so that
+// reserve remains before the parent deadline for cleanup.
+func runPhase(ctx context.Context, name string, budget, reserve time.Duration, fn func(context.Context) error) error {
+	limit := budget
+	if deadline, ok := ctx.Deadline(); ok {
+		remaining := time.Until(deadline) - reserve
+		if remaining <= 0 {
+			return fmt.Errorf("%s: %w (%s reserved for cleanup)", name, ErrDeadlineExhausted, reserve)
+		}
+		if limit <= 0 || remaining < limit {
+			limit = remaining
+		}
+	}
+
+	phaseCtx := ctx
+	if limit > 0 {
+		var cancel context.CancelFunc
+		phaseCtx, cancel = context.WithTimeout(ctx, limit)
+		defer cancel()
+	}
+
+	err := fn(phaseCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%s after %s: %w: %w", name, limit.Round(time.Millisecond), ErrPhaseTimeout, err)
+	}
+	return err
+}
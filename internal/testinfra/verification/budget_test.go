@@ -0,0 +1,46 @@
+package verification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunPhaseBudget(t *testing.T) {
+	err := runPhase(context.Background(), "stuck", 10*time.Millisecond, 0, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, ErrPhaseTimeout) {
+		t.Fatalf("expected ErrPhaseTimeout, got %v", err)
+	}
+}
+
+func TestRunPhaseReservesCleanup(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var phaseDeadline time.Time
+	err := runPhase(ctx, "phase", time.Minute, 900*time.Millisecond, func(ctx context.Context) error {
+		phaseDeadline, _ = ctx.Deadline()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if remaining := time.Until(phaseDeadline); remaining > 150*time.Millisecond {
+		t.Fatalf("expected phase capped to leave cleanup reserve, got %v remaining", remaining)
+	}
+
+	if err := runPhase(ctx, "late", time.Minute, 2*time.Second, func(context.Context) error { return nil }); !errors.Is(err, ErrDeadlineExhausted) {
+		t.Fatalf("expected ErrDeadlineExhausted, got %v", err)
+	}
+}
+
+func TestRunPhasePassesThroughErrors(t *testing.T) {
+	want := errors.New("boom")
+	if err := runPhase(context.Background(), "phase", time.Second, 0, func(context.Context) error { return want }); err != want {
+		t.Fatalf("expected original error, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package verification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+// dumpMetricQueries are the Prometheus queries saved to metrics.json:
+// scrape health plus every series exported through the collector
+var dumpMetricQueries = []string{
+	`up`,
+	`{job="otel-collector"}`,
+}
+
+// DumpPrometheusMetrics runs dumpMetricQueries against Prometheus and writes the
+// results, keyed by query, to dir/metrics.json. It returns the written path.
+func DumpPrometheusMetrics(ctx context.Context, prometheusURL, dir string) (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	results := make(map[string]PrometheusQueryResult, len(dumpMetricQueries))
+	for _, query := range dumpMetricQueries {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, prometheusURL+"/api/v1/query?query="+url.QueryEscape(query), nil)
+		if err != nil {
+			return "", fmt.Errorf("build prometheus request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("query prometheus: %w", err)
+		}
+
+		var result PrometheusQueryResult
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("decode prometheus response for %s: %w", query, err)
+		}
+		results[query] = result
+	}
+
+	return writeArtifact(dir, "metrics.json", results)
+}
+
+// writeArtifact writes v as indented JSON to dir/name, creating dir if needed
+func writeArtifact(dir, name string, v any) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create dump dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode %s: %w", name, err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("write %s: %w", name, err)
+	}
+	return path, nil
+}
+
+// dumpArtifact writes v to cfg.DumpDir when set. Failures are reported, not
+// returned, so a bad dump dir never fails verification.
+func dumpArtifact(cfg *containers.Config, name string, v any, report *containers.Report) {
+	if cfg.DumpDir == "" {
+		return
+	}
+	path, err := writeArtifact(cfg.DumpDir, name, v)
+	if err != nil {
+		report.Info("Could not write %s: %v", name, err)
+		return
+	}
+	report.Info("Wrote %s", path)
+}
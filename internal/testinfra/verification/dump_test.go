@@ -0,0 +1,44 @@
+package verification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDumpPrometheusMetrics(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"otel-collector"},"value":[1700000000,"1"]}]}}`))
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	path, err := DumpPrometheusMetrics(context.Background(), srv.URL, dir)
+	if err != nil {
+		t.Fatalf("DumpPrometheusMetrics error: %v", err)
+	}
+	if path != filepath.Join(dir, "metrics.json") {
+		t.Fatalf("unexpected path %s", path)
+	}
+	if len(queries) != len(dumpMetricQueries) || queries[1] != `{job="otel-collector"}` {
+		t.Fatalf("unexpected queries: %v", queries)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read metrics.json: %v", err)
+	}
+	var results map[string]PrometheusQueryResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatalf("decode metrics.json: %v", err)
+	}
+	if got := results["up"]; got.Status != "success" || len(got.Data.Result) != 1 {
+		t.Fatalf("unexpected up result: %+v", got)
+	}
+}
@@ -11,30 +11,91 @@ import (
 	"time"
 )
 
+// JaegerTrace is the /api/traces search response
 type JaegerTrace struct {
-	Data []struct {
-		TraceID string `json:"traceID"`
-		Spans   []struct {
-			TraceID       string `json:"traceID"`
-			SpanID        string `json:"spanID"`
-			OperationName string `json:"operationName"`
-			References    []struct {
-				RefType string `json:"refType"`
-				TraceID string `json:"traceID"`
-				SpanID  string `json:"spanID"`
-			} `json:"references"`
-			StartTime int64 `json:"startTime"`
-			Duration  int64 `json:"duration"`
-			Tags      []struct {
-				Key   string      `json:"key"`
-				Type  string      `json:"type"`
-				Value interface{} `json:"value"`
-			} `json:"tags"`
-		} `json:"spans"`
-	} `json:"data"`
+	Data []JaegerTraceData `json:"data"`
 }
 
-func VerifyJaegerTraces(_ context.Context, cfg *containers.Config, jaegerURL string, correlationIDs map[string]string, report *containers.Report) error {
+// JaegerTraceData is one trace in a Jaeger search response
+type JaegerTraceData struct {
+	TraceID string       `json:"traceID"`
+	Spans   []JaegerSpan `json:"spans"`
+}
+
+// JaegerSpan is a span as the Jaeger query API returns it
+type JaegerSpan struct {
+	TraceID       string                `json:"traceID"`
+	SpanID        string                `json:"spanID"`
+	OperationName string                `json:"operationName"`
+	References    []JaegerSpanReference `json:"references"`
+	StartTime     int64                 `json:"startTime"`
+	Duration      int64                 `json:"duration"`
+	Tags          []JaegerTag           `json:"tags"`
+}
+
+// JaegerSpanReference links a span to its parent or a followed span
+type JaegerSpanReference struct {
+	RefType string `json:"refType"`
+	TraceID string `json:"traceID"`
+	SpanID  string `json:"spanID"`
+}
+
+// JaegerTag is a span attribute; Value keeps its JSON type
+type JaegerTag struct {
+	Key   string      `json:"key"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Tag returns the value of the span's tag key, formatted as a string
+func (s JaegerSpan) Tag(key string) (string, bool) {
+	for _, tag := range s.Tags {
+		if tag.Key == key {
+			return fmt.Sprint(tag.Value), true
+		}
+	}
+	return "", false
+}
+
+// correlationTags maps span tag keys to the correlationIDs keys they carry
+var correlationTags = map[string]string{
+	"user.id":    "user_id",
+	"session.id": "session_id",
+	"request.id": "request_id",
+}
+
+// minCorrelationMatches is how many of the three correlation IDs a span
+// must carry to count as belonging to the verified session
+const minCorrelationMatches = 2
+
+// correlationMatches counts the correlation IDs span carries
+func correlationMatches(span JaegerSpan, correlationIDs map[string]string) int {
+	matches := 0
+	for tag, id := range correlationTags {
+		if value, ok := span.Tag(tag); ok && value == correlationIDs[id] {
+			matches++
+		}
+	}
+	return matches
+}
+
+// findCorrelatedTrace returns the first trace with a span carrying the
+// correlation IDs
+func findCorrelatedTrace(traces []JaegerTraceData, correlationIDs map[string]string) (JaegerTraceData, bool) {
+	for _, trace := range traces {
+		for _, span := range trace.Spans {
+			if correlationMatches(span, correlationIDs) >= minCorrelationMatches {
+				return trace, true
+			}
+		}
+	}
+	return JaegerTraceData{}, false
+}
+
+// VerifyJaegerTraces finds the trace carrying correlationIDs and checks its expected spans.
+// The search window starts at since minus cfg.JaegerLookbackMargin, so traces from earlier
+// runs are not matched; a zero since falls back to cfg.JaegerLookback.
+func VerifyJaegerTraces(ctx context.Context, cfg *containers.Config, jaegerURL string, correlationIDs map[string]string, since time.Time, report *containers.Report) error {
 	report.Step("Querying Jaeger for trace...")
 
 	client := &http.Client{
@@ -45,9 +106,11 @@ func VerifyJaegerTraces(_ context.Context, cfg *containers.Config, jaegerURL str
 	}
 
 	// Build Jaeger query - search by service and filter client-side
-	// Use wide time range and high limit to ensure we get recent traces
-	query := fmt.Sprintf("%s/api/traces?service=%s&lookback=5m&limit=100",
-		jaegerURL, url.QueryEscape(cfg.ServiceName))
+	start := searchStart(cfg, since, time.Now())
+	limit := cfg.JaegerQueryLimit
+	if limit <= 0 {
+		limit = 100
+	}
 
 	// Retry logic: wait for traces to propagate through OTEL collector to Jaeger
 	var trace JaegerTrace
@@ -57,10 +120,21 @@ func VerifyJaegerTraces(_ context.Context, cfg *containers.Config, jaegerURL str
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		if attempt > 1 {
 			// Silent retry
-			time.Sleep(retryDelay)
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("query jaeger: %w", ctx.Err())
+			case <-time.After(retryDelay):
+			}
 		}
 
-		resp, err := client.Get(query)
+		query := fmt.Sprintf("%s/api/traces?service=%s&start=%d&end=%d&limit=%d",
+			jaegerURL, url.QueryEscape(cfg.ServiceName), start.UnixMicro(), time.Now().UnixMicro(), limit)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+		if err != nil {
+			return fmt.Errorf("build jaeger request: %w", err)
+		}
+		resp, err := client.Do(req)
 		if err != nil {
 			if attempt == maxAttempts {
 				return fmt.Errorf("query jaeger: %w", err)
@@ -86,59 +160,20 @@ func VerifyJaegerTraces(_ context.Context, cfg *containers.Config, jaegerURL str
 		resp.Body.Close()
 
 		// Filter traces by correlation IDs (client-side filtering)
-		if len(trace.Data) > 0 {
-			// Check if any trace has matching correlation IDs
-			for _, traceData := range trace.Data {
-				for _, span := range traceData.Spans {
-					matchingTags := 0
-					for _, tag := range span.Tags {
-						if tag.Key == "user.id" && fmt.Sprint(tag.Value) == correlationIDs["user_id"] {
-							matchingTags++
-						}
-						if tag.Key == "session.id" && fmt.Sprint(tag.Value) == correlationIDs["session_id"] {
-							matchingTags++
-						}
-						if tag.Key == "request.id" && fmt.Sprint(tag.Value) == correlationIDs["request_id"] {
-							matchingTags++
-						}
-					}
-					// If we found at least 2 matching tags, consider this a match
-					if matchingTags >= 2 {
-						// Re-structure trace.Data to only include matching trace
-						trace.Data = []struct {
-							TraceID string `json:"traceID"`
-							Spans   []struct {
-								TraceID       string `json:"traceID"`
-								SpanID        string `json:"spanID"`
-								OperationName string `json:"operationName"`
-								References    []struct {
-									RefType string `json:"refType"`
-									TraceID string `json:"traceID"`
-									SpanID  string `json:"spanID"`
-								} `json:"references"`
-								StartTime int64 `json:"startTime"`
-								Duration  int64 `json:"duration"`
-								Tags      []struct {
-									Key   string      `json:"key"`
-									Type  string      `json:"type"`
-									Value interface{} `json:"value"`
-								} `json:"tags"`
-							} `json:"spans"`
-						}{traceData}
-						goto found
-					}
-				}
-			}
+		if matched, ok := findCorrelatedTrace(trace.Data, correlationIDs); ok {
+			trace.Data = []JaegerTraceData{matched}
+			break
 		}
 
 		if attempt == maxAttempts {
+			dumpArtifact(cfg, "trace.json", trace, report)
 			return fmt.Errorf("no trace found for correlation IDs %v after %d attempts", correlationIDs, maxAttempts)
 		}
-		continue
-	found:
-		break
 	}
 
+	// Save before checking spans so failing runs still leave an artifact
+	dumpArtifact(cfg, "trace.json", trace, report)
+
 	if len(trace.Data) == 0 {
 		return fmt.Errorf("no trace found for correlation IDs %v", correlationIDs)
 	}
@@ -166,21 +201,7 @@ func VerifyJaegerTraces(_ context.Context, cfg *containers.Config, jaegerURL str
 	// Verify correlation IDs exist in at least one span
 	foundMatchingSpan := false
 	for _, span := range spans {
-		matchCount := 0
-		for _, tag := range span.Tags {
-			tagValue := fmt.Sprint(tag.Value)
-			if tag.Key == "user.id" && tagValue == correlationIDs["user_id"] {
-				matchCount++
-			}
-			if tag.Key == "session.id" && tagValue == correlationIDs["session_id"] {
-				matchCount++
-			}
-			if tag.Key == "request.id" && tagValue == correlationIDs["request_id"] {
-				matchCount++
-			}
-		}
-		// If at least 2 out of 3 correlation IDs match, consider it found
-		if matchCount >= 2 {
+		if matchCount := correlationMatches(span, correlationIDs); matchCount >= minCorrelationMatches {
 			report.Info("✓ Found span '%s' with matching correlation IDs (%d/3)", span.OperationName, matchCount)
 			foundMatchingSpan = true
 			break
@@ -195,3 +216,103 @@ func VerifyJaegerTraces(_ context.Context, cfg *containers.Config, jaegerURL str
 	report.StepSuccess("Traces: Server → OTEL → Jaeger")
 	return nil
 }
+
+// searchStart returns the beginning of the Jaeger search window
+func searchStart(cfg *containers.Config, since, now time.Time) time.Time {
+	if since.IsZero() {
+		lookback := cfg.JaegerLookback
+		if lookback <= 0 {
+			lookback = 5 * time.Minute
+		}
+		return now.Add(-lookback)
+	}
+	return since.Add(-cfg.JaegerLookbackMargin)
+}
+
+// VerifyTracePropagation checks that the client trace started by the traffic generator
+// also contains server spans, proving the server continued the propagated traceparent.
+func VerifyTracePropagation(ctx context.Context, cfg *containers.Config, jaegerURL, traceID string, report *containers.Report) error {
+	report.Step("Checking client → server trace propagation...")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	query := fmt.Sprintf("%s/api/traces/%s", jaegerURL, url.PathEscape(traceID))
+	clientService := cfg.ServiceName + containers.TrafficServiceSuffix
+
+	var lastErr error
+	for attempt := 1; attempt <= 10; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+
+		services, err := fetchTraceServices(ctx, client, query)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var serverSpans int
+		for service, count := range services {
+			if service != clientService {
+				serverSpans += count
+			}
+		}
+		if services[clientService] == 0 {
+			lastErr = fmt.Errorf("trace %s has no client spans from %s", traceID, clientService)
+			continue
+		}
+		if serverSpans == 0 {
+			lastErr = fmt.Errorf("trace %s has no server spans - traceparent was not continued", traceID)
+			continue
+		}
+
+		report.Info("Trace %s: %d client spans, %d server spans", traceID, services[clientService], serverSpans)
+		report.StepSuccess("Trace context propagated client → server")
+		return nil
+	}
+
+	return lastErr
+}
+
+// fetchTraceServices returns span counts per service name for a single Jaeger trace
+func fetchTraceServices(ctx context.Context, client *http.Client, query string) (map[string]int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query jaeger: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jaeger returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data []struct {
+			Spans []struct {
+				ProcessID string `json:"processID"`
+			} `json:"spans"`
+			Processes map[string]struct {
+				ServiceName string `json:"serviceName"`
+			} `json:"processes"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode jaeger response: %w", err)
+	}
+	if len(result.Data) == 0 {
+		return nil, fmt.Errorf("trace not found")
+	}
+
+	services := make(map[string]int)
+	for _, span := range result.Data[0].Spans {
+		services[result.Data[0].Processes[span.ProcessID].ServiceName]++
+	}
+	return services, nil
+}
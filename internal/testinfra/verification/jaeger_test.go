@@ -0,0 +1,114 @@
+package verification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+func TestSearchStart(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	trafficStart := now.Add(-20 * time.Minute)
+
+	tests := []struct {
+		name  string
+		cfg   containers.Config
+		since time.Time
+		want  time.Time
+	}{
+		{
+			name:  "traffic start with margin",
+			cfg:   containers.Config{JaegerLookback: 5 * time.Minute, JaegerLookbackMargin: 30 * time.Second},
+			since: trafficStart,
+			want:  trafficStart.Add(-30 * time.Second),
+		},
+		{
+			name: "fallback lookback",
+			cfg:  containers.Config{JaegerLookback: 10 * time.Minute},
+			want: now.Add(-10 * time.Minute),
+		},
+		{
+			name: "default lookback",
+			want: now.Add(-5 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchStart(&tt.cfg, tt.since, now); !got.Equal(tt.want) {
+				t.Fatalf("searchStart = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindCorrelatedTrace(t *testing.T) {
+	const body = `{"data": [
+		{"traceID": "other", "spans": [
+			{"operationName": "ws.auth", "tags": [{"key": "user.id", "type": "string", "value": "user-1"}]}
+		]},
+		{"traceID": "match", "spans": [
+			{"operationName": "ws.connection"},
+			{"operationName": "ws.event.dispatch", "tags": [
+				{"key": "user.id", "type": "string", "value": "user-1"},
+				{"key": "request.id", "type": "int64", "value": 42}
+			]}
+		]}
+	]}`
+	var trace JaegerTrace
+	if err := json.Unmarshal([]byte(body), &trace); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	ids := map[string]string{"user_id": "user-1", "session_id": "sess-1", "request_id": "42"}
+
+	found, ok := findCorrelatedTrace(trace.Data, ids)
+	if !ok || found.TraceID != "match" {
+		t.Fatalf("expected trace match, got %q (%v)", found.TraceID, ok)
+	}
+	if got := correlationMatches(found.Spans[1], ids); got != 2 {
+		t.Fatalf("correlationMatches = %d, want 2", got)
+	}
+	if value, ok := found.Spans[1].Tag("request.id"); !ok || value != "42" {
+		t.Fatalf("Tag(request.id) = %q, %v", value, ok)
+	}
+
+	if _, ok := findCorrelatedTrace(trace.Data, map[string]string{"user_id": "user-2"}); ok {
+		t.Fatal("matched a trace without the correlation IDs")
+	}
+}
+
+func TestVerifyTracePropagation(t *testing.T) {
+	cfg := &containers.Config{ServiceName: "skill-flow"}
+	jaeger := func(t *testing.T, body string) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/traces/abc123" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	continued := jaeger(t, `{"data": [{"spans": [
+		{"processID": "p1"}, {"processID": "p1"}, {"processID": "p2"}
+	], "processes": {"p1": {"serviceName": "skill-flow-traffic"}, "p2": {"serviceName": "skill-flow"}}}]}`)
+	if err := VerifyTracePropagation(context.Background(), cfg, continued, "abc123", containers.NewReport(false)); err != nil {
+		t.Fatalf("expected a trace with client and server spans to pass: %v", err)
+	}
+
+	// Only the client's spans: the server started its own trace
+	clientOnly := jaeger(t, `{"data": [{"spans": [{"processID": "p1"}],
+		"processes": {"p1": {"serviceName": "skill-flow-traffic"}}}]}`)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := VerifyTracePropagation(ctx, cfg, clientOnly, "abc123", containers.NewReport(false)); err == nil {
+		t.Fatal("expected a trace without server spans to fail")
+	}
+}
@@ -0,0 +1,131 @@
+package verification
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+// droppedSpanMetrics are collector self-telemetry counters that must stay at zero.
+// Newer collectors append a _total suffix, so both spellings are matched.
+var droppedSpanMetrics = []string{
+	"otelcol_exporter_send_failed_spans",
+	"otelcol_exporter_enqueue_failed_spans",
+	"otelcol_processor_dropped_spans",
+}
+
+// VerifyNoDroppedSpans checks the collector's internal metrics (port 8888) and fails
+// if any span was dropped by a processor or failed to export. The check is skipped
+// when the endpoint is not reachable.
+func VerifyNoDroppedSpans(ctx context.Context, internalMetricsURL string, report *containers.Report) error {
+	report.Step("Checking OTEL collector for dropped spans...")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, internalMetricsURL, nil)
+	if err != nil {
+		return fmt.Errorf("build collector metrics request: %w", err)
+	}
+	// An unreachable endpoint means telemetry isn't exposed (e.g. default collector config),
+	// which is not itself evidence of dropped spans
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(req)
+	if err != nil {
+		report.StepWarn("Dropped span check skipped", fmt.Sprintf("collector internal metrics unavailable: %v", err))
+		return nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		report.StepWarn("Dropped span check skipped", fmt.Sprintf("collector internal metrics returned status %d", resp.StatusCode))
+		return nil
+	}
+
+	totals, err := sumCounters(resp.Body, droppedSpanMetrics)
+	if err != nil {
+		return fmt.Errorf("parse collector internal metrics: %w", err)
+	}
+
+	var dropped []string
+	for _, name := range droppedSpanMetrics {
+		if totals[name] > 0 {
+			dropped = append(dropped, fmt.Sprintf("%s=%g", name, totals[name]))
+		}
+	}
+	if len(dropped) > 0 {
+		return fmt.Errorf("collector dropped spans: %s", strings.Join(dropped, ", "))
+	}
+
+	report.StepSuccess("OTEL collector: no dropped spans")
+	return nil
+}
+
+// sumCounters sums samples of the named metrics (across all label sets) from
+// Prometheus text exposition format. A "_total" suffix is folded into the base name.
+func sumCounters(r io.Reader, names []string) (map[string]float64, error) {
+	return sumCountersWithLabel(r, names, "", "")
+}
+
+// sumCountersWithLabel is sumCounters restricted to samples whose label
+// set has label="value"; an empty label matches every sample
+func sumCountersWithLabel(r io.Reader, names []string, label, value string) (map[string]float64, error) {
+	matcher := label + "=" + strconv.Quote(value)
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	totals := make(map[string]float64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rawName, valuePart := line, ""
+		if i := strings.IndexAny(line, "{ "); i >= 0 {
+			rawName, valuePart = line[:i], line[i:]
+		}
+		name := strings.TrimSuffix(rawName, "_total")
+		if !wanted[name] {
+			continue
+		}
+		if label != "" && !hasLabel(valuePart, matcher) {
+			continue
+		}
+
+		// Value follows the label set; an optional timestamp may follow the value
+		if end := strings.LastIndex(valuePart, "}"); end >= 0 {
+			valuePart = valuePart[end+1:]
+		}
+		fields := strings.Fields(valuePart)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("metric %s: %w", name, err)
+		}
+		totals[name] += value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// hasLabel reports whether a sample's `{...} value` text contains the
+// label matcher `name="value"` as a whole label, not a suffix of another
+func hasLabel(sample, matcher string) bool {
+	end := strings.LastIndex(sample, "}")
+	if !strings.HasPrefix(sample, "{") || end < 0 {
+		return false
+	}
+	labels := sample[:end]
+	return strings.Contains(labels, "{"+matcher) || strings.Contains(labels, ","+matcher)
+}
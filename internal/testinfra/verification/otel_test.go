@@ -0,0 +1,61 @@
+package verification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSumCounters(t *testing.T) {
+	input := `# HELP otelcol_exporter_send_failed_spans Number of spans in failed attempts to send to destination.
+# TYPE otelcol_exporter_send_failed_spans counter
+otelcol_exporter_send_failed_spans{exporter="otlp/jaeger",service_instance_id="a"} 0
+otelcol_exporter_send_failed_spans{exporter="debug",service_instance_id="a"} 2
+otelcol_processor_dropped_spans_total{processor="memory_limiter"} 3 1700000000000
+otelcol_exporter_sent_spans{exporter="otlp/jaeger"} 42
+otelcol_exporter_enqueue_failed_spans 1
+`
+	totals, err := sumCounters(strings.NewReader(input), droppedSpanMetrics)
+	if err != nil {
+		t.Fatalf("sumCounters error: %v", err)
+	}
+
+	want := map[string]float64{
+		"otelcol_exporter_send_failed_spans":    2,
+		"otelcol_processor_dropped_spans":       3,
+		"otelcol_exporter_enqueue_failed_spans": 1,
+	}
+	for name, v := range want {
+		if totals[name] != v {
+			t.Fatalf("%s = %g, want %g", name, totals[name], v)
+		}
+	}
+	if _, ok := totals["otelcol_exporter_sent_spans"]; ok {
+		t.Fatal("unrequested metric should not be summed")
+	}
+}
+
+func TestSumCountersInvalidValue(t *testing.T) {
+	if _, err := sumCounters(strings.NewReader("otelcol_processor_dropped_spans{} NaNx\n"), droppedSpanMetrics); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestSumCountersWithLabel(t *testing.T) {
+	input := `otelcol_exporter_sent_metric_points{exporter="otlp/metrics",service_instance_id="a"} 12
+otelcol_exporter_sent_metric_points{exporter="prometheus"} 30
+otelcol_exporter_sent_metric_points{service_instance_id="a",exporter="otlp/metrics"} 3
+otelcol_exporter_sent_metric_points{myexporter="otlp/metrics"} 100
+otelcol_exporter_send_failed_metric_points_total{exporter="otlp/metrics"} 0
+`
+	totals, err := sumCountersWithLabel(strings.NewReader(input),
+		[]string{sentMetricPoints, sendFailedMetricPoints}, "exporter", "otlp/metrics")
+	if err != nil {
+		t.Fatalf("sumCountersWithLabel error: %v", err)
+	}
+	if totals[sentMetricPoints] != 15 {
+		t.Fatalf("%s = %g, want 15", sentMetricPoints, totals[sentMetricPoints])
+	}
+	if v, ok := totals[sendFailedMetricPoints]; !ok || v != 0 {
+		t.Fatalf("%s = %g (present %v), want 0", sendFailedMetricPoints, v, ok)
+	}
+}
@@ -0,0 +1,120 @@
+package verification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/raja-aiml/air/internal/foundation/httpclient"
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+// Collector self-telemetry counters for metric points handed to an exporter
+const (
+	sentMetricPoints       = "otelcol_exporter_sent_metric_points"
+	sendFailedMetricPoints = "otelcol_exporter_send_failed_metric_points"
+)
+
+// otlpMetricsAttempts and otlpMetricsInterval bound the wait for the first
+// metrics export; batching and the SDK's export interval delay it
+const (
+	otlpMetricsAttempts = 5
+	otlpMetricsInterval = 3 * time.Second
+)
+
+// VerifyOTLPMetrics checks that the collector pushed the server's metrics
+// to the OTLP backend at cfg.OtelMetricsExporterEndpoint. With
+// cfg.OTLPMetricsQueryURL set the backend itself is asked (PromQL, so any
+// Prometheus-compatible store such as Mimir or VictoriaMetrics works) for
+// series from the server's service; otherwise the collector's own counters
+// must show metric points sent without failures.
+func VerifyOTLPMetrics(ctx context.Context, cfg *containers.Config, internalMetricsURL string, report *containers.Report) error {
+	if cfg.OtelMetricsExporterEndpoint == "" {
+		return fmt.Errorf("metrics backend %q needs OtelMetricsExporterEndpoint (AIR_OTEL_METRICS_ENDPOINT)", cfg.MetricsBackend)
+	}
+	if cfg.OTLPMetricsQueryURL != "" {
+		return verifyOTLPBackend(ctx, cfg, report)
+	}
+	return verifyOTLPExporterCounters(ctx, internalMetricsURL, report)
+}
+
+// verifyOTLPBackend polls the backend's query API for the server's series
+func verifyOTLPBackend(ctx context.Context, cfg *containers.Config, report *containers.Report) error {
+	// OTLP ingestion maps service.name to the job label
+	query := fmt.Sprintf(`count({job=%q})`, cfg.OTELServiceName)
+	queryURL := strings.TrimRight(cfg.OTLPMetricsQueryURL, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	client := httpclient.New(httpclient.DefaultTimeout)
+
+	err := pollOTLPMetrics(ctx, func() (bool, error) {
+		var result PrometheusQueryResult
+		if err := client.GetJSON(ctx, queryURL, &result); err != nil {
+			return false, fmt.Errorf("query metrics backend: %w", err)
+		}
+		if result.Status != "success" || len(result.Data.Result) == 0 {
+			return false, nil
+		}
+		report.Info("%s = %v", query, result.Data.Result[0].Value[1])
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	report.StepSuccess("Metrics: Server → OTEL → OTLP backend")
+	return nil
+}
+
+// verifyOTLPExporterCounters polls the collector's internal metrics until
+// the OTLP metrics exporter reports sent points, failing on send failures
+func verifyOTLPExporterCounters(ctx context.Context, internalMetricsURL string, report *containers.Report) error {
+	names := []string{sentMetricPoints, sendFailedMetricPoints}
+	client := httpclient.New(httpclient.DefaultTimeout)
+
+	var sent float64
+	err := pollOTLPMetrics(ctx, func() (bool, error) {
+		body, err := client.Get(ctx, internalMetricsURL)
+		if err != nil {
+			return false, fmt.Errorf("query collector internal metrics: %w", err)
+		}
+		totals, err := sumCountersWithLabel(bytes.NewReader(body), names, "exporter", containers.OTLPMetricsExporter)
+		if err != nil {
+			return false, fmt.Errorf("parse collector internal metrics: %w", err)
+		}
+		if failed := totals[sendFailedMetricPoints]; failed > 0 {
+			return false, fmt.Errorf("collector failed to export %g metric points via %s", failed, containers.OTLPMetricsExporter)
+		}
+		sent = totals[sentMetricPoints]
+		return sent > 0, nil
+	})
+	if err != nil {
+		return err
+	}
+	report.Info("%s{exporter=%q} = %g", sentMetricPoints, containers.OTLPMetricsExporter, sent)
+	report.StepSuccess("Metrics: Server → OTEL → OTLP backend")
+	return nil
+}
+
+// pollOTLPMetrics calls check until it reports done, errors, the attempts
+// run out or ctx ends. Metrics reach the backend only after the first
+// export, so not found yet is retried rather than failing straight away.
+func pollOTLPMetrics(ctx context.Context, check func() (bool, error)) error {
+	for attempt := 1; ; attempt++ {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if attempt == otlpMetricsAttempts {
+			return fmt.Errorf("no metrics exported via %s after %d checks", containers.OTLPMetricsExporter, attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for OTLP metrics export: %w", ctx.Err())
+		case <-time.After(otlpMetricsInterval):
+		}
+	}
+}
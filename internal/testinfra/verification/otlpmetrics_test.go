@@ -0,0 +1,71 @@
+package verification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+func otlpConfig(queryURL string) *containers.Config {
+	return &containers.Config{
+		OTELServiceName:             "backend",
+		MetricsBackend:              containers.MetricsBackendOTLP,
+		OtelMetricsExporterEndpoint: "mimir:4317",
+		OTLPMetricsQueryURL:         queryURL,
+	}
+}
+
+func TestVerifyOTLPMetricsFromCollectorCounters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `otelcol_exporter_sent_metric_points_total{exporter="otlp/metrics"} 8`)
+		fmt.Fprintln(w, `otelcol_exporter_send_failed_metric_points_total{exporter="prometheus"} 2`)
+	}))
+	defer srv.Close()
+
+	if err := VerifyOTLPMetrics(context.Background(), otlpConfig(""), srv.URL, containers.NewReport(false)); err != nil {
+		t.Fatalf("VerifyOTLPMetrics: %v", err)
+	}
+}
+
+func TestVerifyOTLPMetricsSendFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `otelcol_exporter_sent_metric_points{exporter="otlp/metrics"} 8`)
+		fmt.Fprintln(w, `otelcol_exporter_send_failed_metric_points{exporter="otlp/metrics"} 4`)
+	}))
+	defer srv.Close()
+
+	err := VerifyOTLPMetrics(context.Background(), otlpConfig(""), srv.URL, containers.NewReport(false))
+	if err == nil || !strings.Contains(err.Error(), "failed to export 4 metric points") {
+		t.Fatalf("expected send failure error, got %v", err)
+	}
+}
+
+func TestVerifyOTLPMetricsFromBackend(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"17"]}]}}`)
+	}))
+	defer srv.Close()
+
+	// The collector counters are not consulted when the backend can be queried
+	if err := VerifyOTLPMetrics(context.Background(), otlpConfig(srv.URL+"/"), "http://127.0.0.1:0/metrics", containers.NewReport(false)); err != nil {
+		t.Fatalf("VerifyOTLPMetrics: %v", err)
+	}
+	if query != `count({job="backend"})` {
+		t.Fatalf("backend query = %q", query)
+	}
+}
+
+func TestVerifyOTLPMetricsNeedsEndpoint(t *testing.T) {
+	cfg := otlpConfig("")
+	cfg.OtelMetricsExporterEndpoint = ""
+	if err := VerifyOTLPMetrics(context.Background(), cfg, "", containers.NewReport(false)); err == nil {
+		t.Fatal("expected an error without an exporter endpoint")
+	}
+}
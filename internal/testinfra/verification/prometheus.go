@@ -70,8 +70,8 @@ func VerifyOTELMetricsEndpoint(ctx context.Context, otelMetricsURL string, repor
 		}
 
 		if attempt == 2 {
-			// On last attempt, this is acceptable - OTEL may not have metrics yet
-			report.Info("OTEL collector metrics pending (awaiting first export)")
+			// On last attempt, this is a warning - OTEL may not have metrics yet
+			report.StepWarn("OTEL metrics endpoint", "metrics pending (awaiting first export)")
 			return nil
 		}
 	}
@@ -2,58 +2,193 @@ package verification
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/raja-aiml/air/internal/testinfra/containers"
 )
 
-// Run executes the full observability verification workflow
+// ErrVerificationWarnings is returned by Run when every check passed but some
+// reported warnings and cfg.FailOnWarnings is set.
+var ErrVerificationWarnings = errors.New("verification completed with warnings")
+
+// Run executes the full observability verification workflow.
+// Each phase runs under cfg.PhaseBudgets, and when ctx has a deadline every phase
+// is capped so the cleanup budget remains for tearing down containers.
 func Run(ctx context.Context, cfg *containers.Config, jsonOutput bool) error {
 	report := containers.NewReport(jsonOutput)
+	return run(ctx, cfg, report)
+}
+
+// RunReport executes the same workflow as Run with text progress output and
+// also returns the structured per-phase result. The report is returned even
+// when verification fails, covering the phases that ran, and is marked failed
+// whenever the run failed for a reason other than ErrVerificationWarnings.
+func RunReport(ctx context.Context, cfg *containers.Config) (*containers.FinalReport, error) {
+	report := containers.NewReport(false)
+	err := run(ctx, cfg, report)
+	final := report.Final()
+	if err != nil && !errors.Is(err, ErrVerificationWarnings) {
+		final.Success = false
+		final.Severity = containers.SeverityFail
+	}
+	return final, err
+}
+
+// run executes the verification phases, recording progress in report
+func run(ctx context.Context, cfg *containers.Config, report *containers.Report) (err error) {
+	report.SetPlainMode(cfg.PlainMode())
+	budgets := cfg.PhaseBudgets
+
+	defer func() {
+		if err != nil {
+			report.PrintTimings()
+		}
+	}()
+
+	phase := func(name string, budget time.Duration, fn func(context.Context) error) error {
+		return runPhase(ctx, name, budget, budgets.Cleanup, fn)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		report.Fail("Invalid configuration: %v", err)
+		return fmt.Errorf("invalid config: %w", err)
+	}
 
 	// Phase 1: Start Containers
 	report.Phase("Starting Infrastructure")
-	infra, err := containers.StartInfrastructure(ctx, cfg, report)
-	if err != nil {
+	var infra *containers.Infrastructure
+	if err := phase("infrastructure", budgets.Infrastructure, func(ctx context.Context) error {
+		var err error
+		infra, err = containers.StartInfrastructure(ctx, cfg, report)
+		return err
+	}); err != nil {
 		report.Fail("Container startup failed: %v", err)
 		return fmt.Errorf("container startup: %w", err)
 	}
 	defer containers.CleanupInfrastructure(infra)
 
 	// Phase 2: Verify Container Health (before starting server)
-	if err := containers.VerifyContainerHealth(ctx, infra, report); err != nil {
+	if err := phase("health", budgets.Health, func(ctx context.Context) error {
+		return containers.VerifyContainerHealth(ctx, infra, report)
+	}); err != nil {
 		return fmt.Errorf("container health check: %w", err)
 	}
 
 	// Phase 3: Start Application Server
-	if err := containers.StartApplicationServer(ctx, cfg, infra, report); err != nil {
+	if err := phase("server", budgets.Server, func(ctx context.Context) error {
+		return containers.StartApplicationServer(ctx, cfg, infra, report)
+	}); err != nil {
 		return fmt.Errorf("server startup: %w", err)
 	}
 
 	// Phase 4: Generate Traffic
 	report.Phase("Generating Traffic")
-	correlationIDs, err := containers.GenerateTraffic(ctx, cfg, infra, report)
-	if err != nil {
+	trafficStart := time.Now()
+	var correlationIDs containers.CorrelationIDs
+	if err := phase("traffic", budgets.Traffic, func(ctx context.Context) error {
+		var err error
+		correlationIDs, err = containers.GenerateTraffic(ctx, cfg, infra, report)
+		return err
+	}); err != nil {
+		err = containers.WithServerLogContext(err, infra)
 		report.Fail("Traffic generation failed: %v", err)
 		return fmt.Errorf("traffic generation: %w", err)
 	}
 
 	// Phase 5: Verify Data Flow Through Pipeline
 	report.Phase("Verifying Data Flow")
+	if err := phase("verification", budgets.Verification, func(ctx context.Context) error {
+		return verifyDataFlow(ctx, cfg, infra, correlationIDs, trafficStart, report)
+	}); err != nil {
+		// A server that crashed mid-run shows up here as missing data
+		return containers.WithServerLogContext(err, infra)
+	}
+
+	// Final Report
+	report.Phase("Verification Complete")
+	warnings := report.Warnings()
+	if len(warnings) == 0 {
+		report.Success("All checks passed!")
+	} else {
+		report.Success(fmt.Sprintf("All checks passed with %d warning(s)", len(warnings)))
+	}
+	report.Info("  • Containers: healthy")
+	report.Info("  • Server: running")
+	report.Info("  • Traces: propagating to Jaeger")
+	if cfg.MetricsBackend == containers.MetricsBackendOTLP {
+		report.Info("  • Metrics: exported over OTLP")
+	} else {
+		report.Info("  • Metrics: propagating to Prometheus")
+	}
+	for _, w := range warnings {
+		report.Info("  • Warning: %s: %s", w.Description, w.Error)
+	}
+	report.Print()
+
+	if len(warnings) > 0 && cfg.FailOnWarnings {
+		return fmt.Errorf("%w: %d warning(s)", ErrVerificationWarnings, len(warnings))
+	}
+	return nil
+}
+
+// verifyDataFlow checks traces and metrics produced by the generated traffic
+func verifyDataFlow(ctx context.Context, cfg *containers.Config, infra *containers.Infrastructure, correlationIDs containers.CorrelationIDs, trafficStart time.Time, report *containers.Report) error {
+	if cfg.DumpDir != "" {
+		// Dump even when a check fails; that is when the artifact is most useful
+		defer func() {
+			dumpCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			defer cancel()
+			path, err := DumpPrometheusMetrics(dumpCtx, infra.PrometheusURL, cfg.DumpDir)
+			if err != nil {
+				report.Info("Could not write metrics.json: %v", err)
+				return
+			}
+			report.Info("Wrote %s", path)
+		}()
+	}
 
 	report.Step("Checking traces in Jaeger...")
-	if err := VerifyJaegerTraces(ctx, cfg, infra.JaegerURL, correlationIDs, report); err != nil {
+	if err := VerifyJaegerTraces(ctx, cfg, infra.JaegerURL, correlationIDs, trafficStart, report); err != nil {
 		report.Fail("Trace verification failed: %v", err)
 		return fmt.Errorf("trace verification: %w", err)
 	}
 	report.Info("✓ Server → OTEL Collector → Jaeger")
 
-	report.Step("Checking metrics in Prometheus...")
-	if err := VerifyPrometheusMetrics(ctx, infra.PrometheusURL, report); err != nil {
+	if traceID := correlationIDs["trace_id"]; traceID != "" {
+		if err := VerifyTracePropagation(ctx, cfg, infra.JaegerURL, traceID, report); err != nil {
+			report.Fail("Trace propagation verification failed: %v", err)
+			return fmt.Errorf("trace propagation: %w", err)
+		}
+		report.Info("✓ Client → Server trace context")
+	}
+
+	if err := VerifyNoDroppedSpans(ctx, infra.OtelInternalMetricsURL, report); err != nil {
+		report.Fail("Dropped span check failed: %v", err)
+		return fmt.Errorf("dropped spans: %w", err)
+	}
+
+	switch cfg.MetricsBackend {
+	case "", containers.MetricsBackendPrometheus:
+		report.Step("Checking metrics in Prometheus...")
+		if err := VerifyPrometheusMetrics(ctx, infra.PrometheusURL, report); err != nil {
+			report.Fail("Metrics verification failed: %v", err)
+			return fmt.Errorf("metrics verification: %w", err)
+		}
+		report.Info("✓ Server → OTEL Collector → Prometheus")
+	case containers.MetricsBackendOTLP:
+		report.Step("Checking metrics exported over OTLP...")
+		if err := VerifyOTLPMetrics(ctx, cfg, infra.OtelInternalMetricsURL, report); err != nil {
+			report.Fail("Metrics verification failed: %v", err)
+			return fmt.Errorf("metrics verification: %w", err)
+		}
+		report.Info("✓ Server → OTEL Collector → %s", cfg.OtelMetricsExporterEndpoint)
+	default:
+		err := fmt.Errorf("unknown metrics backend: %s", cfg.MetricsBackend)
 		report.Fail("Metrics verification failed: %v", err)
-		return fmt.Errorf("metrics verification: %w", err)
+		return err
 	}
-	report.Info("✓ Server → OTEL Collector → Prometheus")
 
 	report.Step("Checking server metrics endpoint...")
 	if err := VerifyMetricsEndpoint(ctx, cfg, report); err != nil {
@@ -61,15 +196,5 @@ func Run(ctx context.Context, cfg *containers.Config, jsonOutput bool) error {
 		return fmt.Errorf("metrics endpoint: %w", err)
 	}
 	report.StepSuccess("Complete data flow verified")
-
-	// Final Report
-	report.Phase("Verification Complete")
-	report.Success("✅ All checks passed!")
-	report.Info("  • Containers: healthy")
-	report.Info("  • Server: running")
-	report.Info("  • Traces: propagating to Jaeger")
-	report.Info("  • Metrics: propagating to Prometheus")
-	report.Print()
-
 	return nil
 }
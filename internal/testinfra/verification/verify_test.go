@@ -0,0 +1,39 @@
+package verification
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/raja-aiml/air/internal/testinfra/containers"
+)
+
+func TestRunReportMarksFailedPhase(t *testing.T) {
+	// An unreachable daemon and a missing compose file fail the infrastructure phase without Docker
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(t.TempDir(), "docker.sock"))
+	cfg := &containers.Config{
+		ComposeFilePath: filepath.Join(t.TempDir(), "missing.yml"),
+		PlainOutput:     true,
+	}
+
+	final, err := RunReport(context.Background(), cfg)
+	if err == nil {
+		t.Fatal("expected the infrastructure phase to fail")
+	}
+	if final.Success || final.Severity != containers.SeverityFail {
+		t.Fatalf("expected a failed report, got success=%v severity=%s", final.Success, final.Severity)
+	}
+	if len(final.Phases) == 0 || final.Phases[0].Name != "Starting Infrastructure" {
+		t.Fatalf("expected the infrastructure phase in the report, got %+v", final.Phases)
+	}
+}
+
+func TestRunReportMarksInvalidConfig(t *testing.T) {
+	final, err := RunReport(context.Background(), &containers.Config{MetricsBackend: "graphite", PlainOutput: true})
+	if err == nil {
+		t.Fatal("expected invalid config error")
+	}
+	if final.Success || final.Severity != containers.SeverityFail {
+		t.Fatalf("expected a failed report, got success=%v severity=%s", final.Success, final.Severity)
+	}
+}
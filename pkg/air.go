@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -24,6 +25,7 @@ import (
 	"github.com/raja-aiml/air/internal/foundation/logging"
 	"github.com/raja-aiml/air/internal/foundation/observability/metrics"
 	telemetry "github.com/raja-aiml/air/internal/foundation/observability/tracing"
+	"github.com/raja-aiml/air/internal/foundation/terminal"
 	"github.com/raja-aiml/air/internal/mcp"
 	"github.com/raja-aiml/air/internal/nlp"
 	"github.com/raja-aiml/air/internal/testinfra/containers"
@@ -40,21 +42,46 @@ type (
 	ComposeServiceStatus = compose.ServiceStatus
 	ComposeServiceInfo   = compose.ServiceInfo
 	ComposeConfig        = compose.Config
+	ComposeLogLine       = compose.LogLine
+	ComposeLogOptions    = compose.LogOptions
+	ContainerStats       = compose.ContainerStats
+	ComposeLeakError     = compose.LeakError
+	ComposeImageDigest   = compose.ImageDigest
+	ComposeDigestError   = compose.DigestError
 )
 
 func NewComposeService(cfg ComposeConfig) (*ComposeService, error) {
 	return compose.New(cfg)
 }
 
+var (
+	ErrDockerUnavailable   = compose.ErrDockerUnavailable
+	ErrImagePull           = compose.ErrImagePull
+	ErrImageBuild          = compose.ErrImageBuild
+	ErrPortInUse           = compose.ErrPortInUse
+	ErrCircularDependency  = compose.ErrCircularDependency
+	ErrServiceNotFound     = compose.ErrServiceNotFound
+	ErrResourcesLeaked     = compose.ErrResourcesLeaked
+	ErrImageDigestMismatch = compose.ErrImageDigestMismatch
+
+	PingDocker          = compose.PingDocker
+	ValidateComposeFile = compose.ValidateFile
+)
+
 // ============================================================================
 // HTTP - HTTP Client Utilities
 // ============================================================================
 
-type HTTPClient = httpclient.Client
+type (
+	HTTPClient       = httpclient.Client
+	HTTPClientOption = httpclient.Option
+)
 
 var (
-	NewHTTPClient     = httpclient.New
-	DefaultHTTPClient = httpclient.Default
+	NewHTTPClient         = httpclient.New
+	DefaultHTTPClient     = httpclient.Default
+	HTTPClientWithRetry   = httpclient.WithRetry
+	HTTPClientWithTracing = httpclient.WithTracing
 )
 
 // ============================================================================
@@ -65,6 +92,25 @@ type (
 	ServerConfig   = config.ServerConfig
 	BackfillConfig = config.BackfillConfig
 	JWTGenConfig   = config.JWTGenConfig
+	ConfigSource   = config.Source
+	ConfigValue    = config.Value
+	ResolvedConfig = config.Resolved
+	ResolveOptions = config.ResolveOptions
+	ConfigEntry    = config.Entry
+)
+
+const (
+	DefaultDatabaseURL = config.DefaultDatabaseURL
+	DefaultProjectName = config.DefaultProjectName
+
+	ConfigKeyDatabaseURL = config.KeyDatabaseURL
+	ConfigKeyComposeFile = config.KeyComposeFile
+	ConfigKeyProjectName = config.KeyProjectName
+
+	ConfigSourceDefault = config.SourceDefault
+	ConfigSourceFile    = config.SourceFile
+	ConfigSourceEnv     = config.SourceEnv
+	ConfigSourceFlag    = config.SourceFlag
 )
 
 var (
@@ -73,6 +119,8 @@ var (
 	LoadJWTGenConfig   = config.LoadJWTGenConfig
 	ParseLogLevel      = config.ParseLogLevel
 	ParseInt           = config.ParseInt
+	ResolveConfig      = config.ResolveConfig
+	MaskURL            = config.MaskURL
 )
 
 // ============================================================================
@@ -87,10 +135,54 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 	return db.RunMigrations(ctx, pool)
 }
 
+func RunMigrationsFS(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, dir string) error {
+	return db.RunMigrationsFS(ctx, pool, fsys, dir)
+}
+
+type MigrationConfig = db.MigrationConfig
+
+const DefaultMigrationTimeout = db.DefaultMigrationTimeout
+
+func Migrate(ctx context.Context, pool *pgxpool.Pool, cfg MigrationConfig) error {
+	return db.Migrate(ctx, pool, cfg)
+}
+
+func RollbackMigration(ctx context.Context, pool *pgxpool.Pool, toVersion int) error {
+	return db.RollbackMigration(ctx, pool, toVersion)
+}
+
+func RollbackMigrationFS(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, dir string, toVersion int) error {
+	return db.RollbackMigrationFS(ctx, pool, fsys, dir, toVersion)
+}
+
+type MigrationInfo = db.MigrationInfo
+type MigrationStatus = db.MigrationStatus
+
+func Migrations() ([]MigrationInfo, error) {
+	return db.Migrations()
+}
+
+func MigrationsFS(fsys fs.FS, dir string) ([]MigrationInfo, error) {
+	return db.MigrationsFS(fsys, dir)
+}
+
+func CompareMigrations(migrations []MigrationInfo, applied map[int]time.Time) []MigrationStatus {
+	return db.CompareMigrations(migrations, applied)
+}
+
 func PingDatabase(ctx context.Context, pool *pgxpool.Pool) error {
 	return db.Ping(ctx, pool)
 }
 
+type QueryResult = db.QueryResult
+
+var (
+	FormatQueryResult = db.Format
+	FormatQueryTable  = db.FormatTable
+	FormatQueryJSON   = db.FormatJSON
+	FormatQueryCSV    = db.FormatCSV
+)
+
 // ============================================================================
 // AUTH - JWT Token Management
 // ============================================================================
@@ -101,6 +193,26 @@ func GenerateJWTToken(claims TokenClaims, secret string) (string, error) {
 	return auth.GenerateToken(claims, secret)
 }
 
+type (
+	JWTSigningMethod = auth.SigningMethod
+	JWTParseOptions  = auth.ParseOptions
+)
+
+const (
+	JWTSigningMethodHS256 = auth.SigningMethodHS256
+	JWTSigningMethodRS256 = auth.SigningMethodRS256
+)
+
+var (
+	ParseJWTToken          = auth.ParseToken
+	JWTMiddleware          = auth.Middleware
+	JWTClaimsFromContext   = auth.ClaimsFromContext
+	GenerateJWTTokenRS256  = auth.GenerateTokenRS256
+	ParseAndVerifyJWTRS256 = auth.ParseAndVerifyRS256
+	SignJWTToken           = auth.SignToken
+	VerifyJWTToken         = auth.VerifyToken
+)
+
 // ============================================================================
 // ERRORS - Structured Error Handling
 // ============================================================================
@@ -131,6 +243,8 @@ var (
 	NewError            = errors.New
 	WrapError           = errors.Wrap
 	IsErrorCode         = errors.Is
+	ErrorCode           = errors.Code
+	ErrorFromJSON       = errors.FromJSON
 	InvalidEnvelope     = errors.InvalidEnvelope
 	InvalidEvent        = errors.InvalidEvent
 	UnknownEvent        = errors.UnknownEvent
@@ -146,28 +260,58 @@ var (
 	NotFound            = errors.NotFound
 	RateLimited         = errors.RateLimited
 	Internal            = errors.Internal
+	ErrorFromPanic      = errors.FromPanic
 )
 
 // ============================================================================
 // LOGGING - Structured Logging
 // ============================================================================
 
-var InitLogger = logging.InitLogger
+var (
+	InitLogger    = logging.InitLogger
+	ContextLogger = logging.ContextLogger
+	WithLogger    = logging.WithLogger
+)
+
+// ============================================================================
+// TERMINAL - CI Detection & Plain Output
+// ============================================================================
+
+type (
+	Environment = terminal.Environment
+	Spinner     = terminal.Spinner
+	History     = terminal.History
+	LineReader  = terminal.LineReader
+)
+
+var (
+	DetectEnvironment = terminal.DetectEnvironment
+	IsTerminal        = terminal.IsTerminal
+	NoColor           = terminal.NoColor
+	PlainText         = terminal.PlainText
+	NewSpinner        = terminal.NewSpinner
+	NewStdoutSpinner  = terminal.NewStdoutSpinner
+	LoadHistory       = terminal.LoadHistory
+	NewLineReader     = terminal.NewLineReader
+)
 
 // ============================================================================
 // TRACING - OpenTelemetry Tracing
 // ============================================================================
 
 type (
-	DBTracer  = telemetry.DBTracer
-	Span      = trace.Span
-	Attribute = attribute.KeyValue
+	DBTracer    = telemetry.DBTracer
+	QueryTracer = telemetry.QueryTracer
+	Span        = trace.Span
+	Attribute   = attribute.KeyValue
 )
 
 var (
 	InitTracer        = telemetry.InitTracer
+	InitMeter         = telemetry.InitMeter
 	GetTracer         = telemetry.Tracer
 	GetTraceID        = telemetry.GetTraceID
+	HasActiveTrace    = telemetry.HasActiveTrace
 	AddSpanAttributes = telemetry.AddSpanAttributes
 	LogInfo           = telemetry.LogInfo
 	LogDebug          = telemetry.LogDebug
@@ -184,26 +328,42 @@ var (
 	GetSessionID      = telemetry.GetSessionID
 	NewCorrelationID  = telemetry.NewCorrelationID
 	EnrichContext     = telemetry.EnrichContext
+	InjectHTTP        = telemetry.InjectHTTP
+	ExtractHTTP       = telemetry.ExtractHTTP
+
+	StartSpanWithCorrelation = telemetry.StartSpanWithCorrelation
+	RecoverHandler           = telemetry.RecoverHandler
+	HTTPMiddleware           = telemetry.HTTPMiddleware
 )
 
 func NewDBTracer() *DBTracer {
 	return telemetry.NewDBTracer()
 }
 
+func NewQueryTracer() *QueryTracer {
+	return telemetry.NewQueryTracer()
+}
+
 // ============================================================================
 // ENGINE - Command registry and command types (re-exported from internal/engine)
 // ============================================================================
 
 type (
-	Registry  = engine.Registry
-	Command   = engine.Command
-	Result    = engine.Result
-	Params    = engine.Params
-	Parameter = engine.Parameter
+	Registry   = engine.Registry
+	Command    = engine.Command
+	Result     = engine.Result
+	Params     = engine.Params
+	Parameter  = engine.Parameter
+	Handler    = engine.Handler
+	Middleware = engine.Middleware
 )
 
 var (
-	NewRegistry = engine.NewRegistry
+	NewRegistry       = engine.NewRegistry
+	RecoverMiddleware = engine.RecoverMiddleware
+	TracingMiddleware = engine.TracingMiddleware
+	WithLiveOutput    = engine.WithLiveOutput
+	LiveOutput        = engine.LiveOutput
 )
 
 // ============================================================================
@@ -211,15 +371,25 @@ var (
 // ============================================================================
 
 type (
-	InfraCommands = commands.InfraCommands
-	DBCommands    = commands.DBCommands
+	InfraCommands  = commands.InfraCommands
+	DBCommands     = commands.DBCommands
+	ComposeManager = commands.ComposeManager
+	LogStreamer    = commands.LogStreamer
+	Querier        = commands.Querier
+	QuerierFactory = commands.QuerierFactory
+	JaegerClient   = commands.JaegerClient
+	Page           = commands.Page
+	TraceSummary   = commands.TraceSummary
 )
 
 var (
-	NewInfraCommands = commands.NewInfraCommands
-	NewDBCommands    = commands.NewDBCommands
-	NewObsCommands   = commands.NewObsCommands
-	NewLintCommands  = commands.NewLintCommands
+	NewInfraCommands         = commands.NewInfraCommands
+	NewDBCommands            = commands.NewDBCommands
+	NewDBCommandsWithFactory = commands.NewDBCommandsWithFactory
+	NewObsCommands           = commands.NewObsCommands
+	NewObsCommandsWithURLs   = commands.NewObsCommandsWithURLs
+	NewJaegerClient          = commands.NewJaegerClient
+	NewLintCommands          = commands.NewLintCommands
 )
 
 // ============================================================================
@@ -227,13 +397,17 @@ var (
 // ============================================================================
 
 type (
-	MCPServer = mcp.Server
-	MCPConfig = mcp.Config
+	MCPServer      = mcp.Server
+	MCPConfig      = mcp.Config
+	MCPInfo        = mcp.Info
+	MCPCommandInfo = mcp.CommandInfo
 )
 
 var (
 	NewMCPServer     = mcp.NewServer
 	DefaultMCPConfig = mcp.DefaultConfig
+
+	ErrMCPShutdownTimeout = mcp.ErrShutdownTimeout
 )
 
 // ============================================================================
@@ -248,6 +422,7 @@ type (
 var (
 	NewParser           = nlp.NewParser
 	DefaultParserConfig = nlp.DefaultParserConfig
+	DefaultCachePath    = nlp.DefaultCachePath
 )
 
 // ============================================================================
@@ -279,6 +454,8 @@ type (
 	Metrics    = metrics.Metrics
 	Stats      = metrics.Stats
 	EventStats = metrics.EventStats
+
+	TimestampedStats = metrics.TimestampedStats
 )
 
 var (
@@ -286,8 +463,12 @@ var (
 	IncWebSocket   = metrics.IncWS
 	DecWebSocket   = metrics.DecWS
 	MetricsHandler = metrics.MetricsHandler
+
+	ResetMetricsHandler = metrics.ResetMetricsHandler
 )
 
+const ResetMetricsPath = metrics.ResetMetricsPath
+
 func RecordEvent(eventName string, duration time.Duration) {
 	GetMetrics().WSEventProcessed(eventName, duration)
 }
@@ -308,13 +489,49 @@ type (
 	Infrastructure = containers.Infrastructure
 	TestConfig     = containers.Config
 	Report         = containers.Report
+	TestBackend    = containers.Backend
+	MetricsBackend = containers.MetricsBackend
+	PhaseBudgets   = containers.PhaseBudgets
+	Severity       = containers.Severity
+	StepResult     = containers.StepResult
+	PhaseResult    = containers.PhaseResult
+	LogEntry       = containers.LogEntry
+	FinalReport    = containers.FinalReport
+	WaitOptions    = containers.WaitOptions
+	ServerLogError = containers.ServerLogError
+
+	OtelTemplateData       = containers.OtelTemplateData
+	PrometheusTemplateData = containers.PrometheusTemplateData
+	ScrapeTarget           = containers.ScrapeTarget
+)
+
+const (
+	TestBackendCompose  = containers.BackendCompose
+	TestBackendEmbedded = containers.BackendEmbedded
+
+	MetricsBackendPrometheus = containers.MetricsBackendPrometheus
+	MetricsBackendOTLP       = containers.MetricsBackendOTLP
+	OTLPMetricsExporter      = containers.OTLPMetricsExporter
+
+	SeverityPass = containers.SeverityPass
+	SeverityWarn = containers.SeverityWarn
+	SeverityFail = containers.SeverityFail
+
+	DefaultServerLogTail = containers.DefaultServerLogTail
+	DefaultServerLogPath = containers.DefaultServerLogPath
 )
 
 var (
 	DefaultTestConfig         = containers.DefaultConfig
+	DefaultPhaseBudgets       = containers.DefaultPhaseBudgets
 	StartWithCompose          = containers.StartWithCompose
+	StartEmbedded             = containers.StartEmbedded
+	StartWithBackend          = containers.Start
 	StartInfrastructure       = containers.StartInfrastructure
 	StartServerInBackground   = containers.StartServerInBackground
+	CheckServerLog            = containers.CheckServerLog
+	WithServerLogContext      = containers.WithServerLogContext
+	ReadServerLog             = containers.ReadServerLog
 	VerifyContainerHealth     = containers.VerifyContainerHealth
 	StartApplicationServer    = containers.StartApplicationServer
 	CleanupInfrastructure     = containers.CleanupInfrastructure
@@ -324,18 +541,33 @@ var (
 	WaitForPrometheus         = containers.WaitForPrometheus
 	WaitForHTTP               = containers.WaitForHTTP
 	WaitForSchema             = containers.WaitForSchema
+	WaitForService            = containers.WaitForService
+	DefaultWaitOptions        = containers.DefaultWaitOptions
 	VerifyPostgresHealth      = containers.VerifyPostgresHealth
 	VerifyJaegerHealth        = containers.VerifyJaegerHealth
 	VerifyPrometheusHealth    = containers.VerifyPrometheusHealth
 	VerifyOtelCollectorHealth = containers.VerifyOtelCollectorHealth
 	ApplyMigrations           = containers.ApplyMigrations
+	IsPortAvailable           = containers.IsPortAvailable
+	RenderOtelConfig          = containers.RenderOtelConfig
+	WriteOtelConfig           = containers.WriteOtelConfig
+	RenderPrometheusConfig    = containers.RenderPrometheusConfig
+	WritePrometheusConfig     = containers.WritePrometheusConfig
+	DefaultScrapeTargets      = containers.DefaultScrapeTargets
 )
 
-// Helper: Start test infrastructure with cleanup
+// Helper: Start test infrastructure with cleanup.
+// The backend defaults to Docker Compose; set AIR_TEST_BACKEND=embedded to run without Docker.
 func StartTestInfrastructure(ctx context.Context) (*Infrastructure, func(), error) {
+	return StartTestInfrastructureWithBackend(ctx, DefaultTestConfig().Backend)
+}
+
+// Helper: Start test infrastructure on an explicit backend with cleanup
+func StartTestInfrastructureWithBackend(ctx context.Context, backend TestBackend) (*Infrastructure, func(), error) {
 	cfg := DefaultTestConfig()
+	cfg.Backend = backend
 
-	infra, err := StartWithCompose(ctx, cfg)
+	infra, err := StartWithBackend(ctx, cfg)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -377,7 +609,18 @@ func GetContainerLogs(ctx context.Context, infra *Infrastructure, containerType
 // VERIFICATION - Observability Verification
 // ============================================================================
 
-var RunVerification = verification.Run
+var (
+	RunVerification       = verification.Run
+	RunVerificationReport = verification.RunReport
+	VerifyNoDroppedSpans  = verification.VerifyNoDroppedSpans
+	VerifyOTLPMetrics     = verification.VerifyOTLPMetrics
+	DumpPrometheusMetrics = verification.DumpPrometheusMetrics
+
+	ErrPhaseTimeout      = verification.ErrPhaseTimeout
+	ErrDeadlineExhausted = verification.ErrDeadlineExhausted
+
+	ErrVerificationWarnings = verification.ErrVerificationWarnings
+)
 
 func VerifyObservability(ctx context.Context) error {
 	cfg := DefaultTestConfig()
@@ -398,11 +641,18 @@ type (
 	RepositoryConfig = ghpub.RepositoryConfig
 	ReleaseConfig    = ghpub.ReleaseConfig
 	PublishOptions   = ghpub.PublishOptions
+	GitAuthOptions   = ghpub.AuthOptions
 )
 
 var (
 	NewGitHubPublisher = ghpub.NewPublisher
 	PublishToGitHub    = ghpub.Publish
+	ParseRemoteURL     = ghpub.ParseRemoteURL
+	GenerateChangelog  = ghpub.GenerateChangelog
+	PreviousTag        = ghpub.PreviousTag
+	CurrentBranch      = ghpub.CurrentBranch
+
+	ErrTagExists = ghpub.ErrTagExists
 )
 
 // PublishRepo is a convenience wrapper that runs the full publish workflow
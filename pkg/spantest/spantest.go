@@ -0,0 +1,157 @@
+// Package spantest provides assertions for spans recorded with the
+// OpenTelemetry SDK's in-memory exporter:
+//
+//	exporter := spantest.Record(t)
+//	// ... exercise the code under test ...
+//	span := spantest.MustFindSpan(t, exporter.GetSpans(), "db.query")
+//	spantest.AssertSpanHasAttribute(t, span, "db.system", "postgresql")
+//
+// It is a separate package from air so that importing air does not link
+// the testing package into production binaries.
+package spantest
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Record installs a global tracer provider that records every span
+// synchronously into the returned exporter, and restores the previous
+// provider when t finishes
+func Record(t testing.TB) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+// FindSpan returns the first span named name
+func FindSpan(spans []tracetest.SpanStub, name string) (tracetest.SpanStub, bool) {
+	for _, span := range spans {
+		if span.Name == name {
+			return span, true
+		}
+	}
+	return tracetest.SpanStub{}, false
+}
+
+// FindSpans returns every span named name, in recording order
+func FindSpans(spans []tracetest.SpanStub, name string) []tracetest.SpanStub {
+	var found []tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+	return found
+}
+
+// MustFindSpan is FindSpan that fails the test, listing the recorded span
+// names, when there is no such span
+func MustFindSpan(t testing.TB, spans []tracetest.SpanStub, name string) tracetest.SpanStub {
+	t.Helper()
+	span, ok := FindSpan(spans, name)
+	if !ok {
+		names := make([]string, 0, len(spans))
+		for _, s := range spans {
+			names = append(names, s.Name)
+		}
+		t.Fatalf("no span named %q among %d recorded: %v", name, len(spans), names)
+	}
+	return span
+}
+
+// Attribute returns the value of a span attribute
+func Attribute(span tracetest.SpanStub, key string) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// HasAttribute reports whether span has attribute key equal to want (see
+// AssertSpanHasAttribute for how want is compared)
+func HasAttribute(span tracetest.SpanStub, key string, want any) bool {
+	got, ok := Attribute(span, key)
+	return ok && reflect.DeepEqual(got.AsInterface(), normalize(want))
+}
+
+// AssertSpanHasAttribute reports a test error unless span has attribute
+// key equal to want. want may be a Go value (ints and float32 are widened
+// to the int64 and float64 attributes store) or an attribute.Value. Like
+// t.Errorf it lets the test continue; the result says whether it held.
+func AssertSpanHasAttribute(t testing.TB, span tracetest.SpanStub, key string, want any) bool {
+	t.Helper()
+	got, ok := Attribute(span, key)
+	if !ok {
+		t.Errorf("span %q has no attribute %q; attributes: %v", span.Name, key, span.Attributes)
+		return false
+	}
+	if !reflect.DeepEqual(got.AsInterface(), normalize(want)) {
+		t.Errorf("span %q attribute %q = %v (%s), want %v", span.Name, key, got.Emit(), got.Type(), want)
+		return false
+	}
+	return true
+}
+
+// AssertSpanLacksAttribute reports a test error when span has attribute key
+func AssertSpanLacksAttribute(t testing.TB, span tracetest.SpanStub, key string) bool {
+	t.Helper()
+	if got, ok := Attribute(span, key); ok {
+		t.Errorf("span %q has unexpected attribute %q = %v", span.Name, key, got.Emit())
+		return false
+	}
+	return true
+}
+
+// AssertSpanStatus reports a test error unless span's status code is want
+func AssertSpanStatus(t testing.TB, span tracetest.SpanStub, want codes.Code) bool {
+	t.Helper()
+	if span.Status.Code != want {
+		t.Errorf("span %q status = %v (%q), want %v", span.Name, span.Status.Code, span.Status.Description, want)
+		return false
+	}
+	return true
+}
+
+// normalize converts want to the type attribute.Value.AsInterface returns
+func normalize(want any) any {
+	switch v := want.(type) {
+	case attribute.Value:
+		return v.AsInterface()
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []int:
+		out := make([]int64, len(v))
+		for i, n := range v {
+			out[i] = int64(n)
+		}
+		return out
+	default:
+		return want
+	}
+}
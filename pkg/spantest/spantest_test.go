@@ -0,0 +1,144 @@
+package spantest
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recorder captures assertion failures instead of failing the real test
+type recorder struct {
+	testing.TB
+	mu     sync.Mutex
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+	runtime.Goexit()
+}
+
+// run calls fn with a recorder on its own goroutine so Fatalf can stop it
+func run(fn func(*recorder)) *recorder {
+	r := &recorder{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	return r
+}
+
+func recordSpans(t *testing.T) []tracetest.SpanStub {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("spantest")
+
+	_, span := tracer.Start(context.Background(), "db.query")
+	span.SetAttributes(
+		attribute.String("db.system", "postgresql"),
+		attribute.Int("db.rows_affected", 3),
+		attribute.Float64("db.duration_ms", 1.5),
+		attribute.StringSlice("db.tables", []string{"users", "orders"}),
+	)
+	span.SetStatus(codes.Error, "syntax error")
+	span.End()
+
+	_, other := tracer.Start(context.Background(), "db.query")
+	other.End()
+	return exporter.GetSpans()
+}
+
+func TestFindSpan(t *testing.T) {
+	spans := recordSpans(t)
+
+	span, ok := FindSpan(spans, "db.query")
+	if !ok || len(span.Attributes) == 0 {
+		t.Fatalf("FindSpan did not return the first db.query span: %+v, %v", span, ok)
+	}
+	if _, ok := FindSpan(spans, "ws.connection"); ok {
+		t.Fatal("FindSpan found a span that was never recorded")
+	}
+	if got := len(FindSpans(spans, "db.query")); got != 2 {
+		t.Fatalf("FindSpans returned %d spans, want 2", got)
+	}
+
+	r := run(func(r *recorder) { MustFindSpan(r, spans, "ws.connection") })
+	if !r.fatal || !strings.Contains(r.errors[0], `no span named "ws.connection"`) || !strings.Contains(r.errors[0], "db.query") {
+		t.Fatalf("MustFindSpan did not fail with the recorded names: %v", r.errors)
+	}
+}
+
+func TestAssertSpanHasAttribute(t *testing.T) {
+	span := recordSpans(t)[0]
+
+	r := run(func(r *recorder) {
+		AssertSpanHasAttribute(r, span, "db.system", "postgresql")
+		AssertSpanHasAttribute(r, span, "db.rows_affected", 3)
+		AssertSpanHasAttribute(r, span, "db.rows_affected", int64(3))
+		AssertSpanHasAttribute(r, span, "db.duration_ms", 1.5)
+		AssertSpanHasAttribute(r, span, "db.tables", []string{"users", "orders"})
+		AssertSpanHasAttribute(r, span, "db.system", attribute.StringValue("postgresql"))
+		AssertSpanLacksAttribute(r, span, "db.statement")
+		AssertSpanStatus(r, span, codes.Error)
+	})
+	if len(r.errors) != 0 {
+		t.Fatalf("expected every assertion to hold, got %v", r.errors)
+	}
+	if !HasAttribute(span, "db.rows_affected", 3) || HasAttribute(span, "db.rows_affected", 4) {
+		t.Fatal("HasAttribute disagrees with the recorded value")
+	}
+
+	r = run(func(r *recorder) {
+		AssertSpanHasAttribute(r, span, "db.system", "mysql")
+		AssertSpanHasAttribute(r, span, "db.statement", "SELECT 1")
+		AssertSpanHasAttribute(r, span, "db.rows_affected", "3")
+		AssertSpanLacksAttribute(r, span, "db.system")
+		AssertSpanStatus(r, span, codes.Ok)
+	})
+	if r.fatal || len(r.errors) != 5 {
+		t.Fatalf("expected 5 non-fatal failures, got %d: %v", len(r.errors), r.errors)
+	}
+	if !strings.Contains(r.errors[0], `attribute "db.system" = postgresql (STRING), want mysql`) {
+		t.Fatalf("unexpected mismatch message: %s", r.errors[0])
+	}
+	if !strings.Contains(r.errors[1], `has no attribute "db.statement"`) {
+		t.Fatalf("unexpected missing-attribute message: %s", r.errors[1])
+	}
+}
+
+func TestRecordRestoresProvider(t *testing.T) {
+	before := otel.GetTracerProvider()
+
+	var exporter *tracetest.InMemoryExporter
+	t.Run("record", func(t *testing.T) {
+		exporter = Record(t)
+		_, span := otel.Tracer("spantest").Start(context.Background(), "ws.connection")
+		span.End()
+	})
+
+	MustFindSpan(t, exporter.GetSpans(), "ws.connection")
+	if otel.GetTracerProvider() != before {
+		t.Fatal("Record did not restore the previous tracer provider")
+	}
+}